@@ -5,19 +5,37 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
+
+	"github.com/xgnid-tw/gx5/domain"
+)
+
+const (
+	defaultMemberCacheTTL    = 10 * time.Minute
+	defaultBuyRevisionWindow = 15 * time.Minute
+
+	defaultReminderThresholdTWD = 2000
+	defaultReminderThresholdJPY = 8000
 )
 
 type Config struct {
-	NotionToken         string
-	NotionUserDBID      string
-	NotionOthersDBID    string
-	NotionOrderDBID     string
-	DiscordToken        string
-	DiscordAppID        string
-	DiscordGuildID      string
-	DiscordLogChannelID string
-	ExchangeRateJPYTWD  float64
-	TagRoleMap          map[string]string
+	NotionToken           string
+	NotionUserDBID        string
+	NotionOthersDBID      string
+	NotionOrderDBID       string
+	DiscordToken          string
+	DiscordAppID          string
+	DiscordGuildID        string
+	DiscordGlobalCommands bool
+	DiscordLogChannelID   string
+	ExchangeRateJPYTWD    float64
+	TagRoleMap            map[string]string
+	MemberCacheTTL        time.Duration
+	BuyRevisionWindow     time.Duration
+	ReminderThresholds    domain.ReminderThresholds
+	ReminderTemplateDir   string
+	ReminderPaymentInfo   string
+	NotionSchemaFile      string // JSON column mapping read by the Notion gateway; empty keeps the defaults
 }
 
 func Load() (Config, error) {
@@ -30,6 +48,9 @@ func Load() (Config, error) {
 		DiscordAppID:        os.Getenv("DISCORD_APP_ID"),
 		DiscordGuildID:      os.Getenv("DISCORD_GUILD_ID"),
 		DiscordLogChannelID: os.Getenv("DISCORD_GUILD_LOG_CHANNEL_ID"),
+		ReminderTemplateDir: os.Getenv("REMINDER_TEMPLATE_DIR"),
+		ReminderPaymentInfo: os.Getenv("REMINDER_PAYMENT_INFO"),
+		NotionSchemaFile:    os.Getenv("NOTION_SCHEMA_FILE"),
 	}
 	cfg.TagRoleMap = parseTagRoleMap(os.Getenv("TAG_ROLE_MAP"))
 
@@ -40,6 +61,34 @@ func Load() (Config, error) {
 
 	cfg.ExchangeRateJPYTWD = rate
 
+	ttl, err := parseDurationOrDefault(os.Getenv("MEMBER_CACHE_TTL"), defaultMemberCacheTTL)
+	if err != nil || ttl <= 0 {
+		return Config{}, fmt.Errorf("MEMBER_CACHE_TTL must be a positive duration (e.g. 10m)")
+	}
+
+	cfg.MemberCacheTTL = ttl
+
+	window, err := parseDurationOrDefault(os.Getenv("BUY_REVISION_WINDOW"), defaultBuyRevisionWindow)
+	if err != nil || window <= 0 {
+		return Config{}, fmt.Errorf("BUY_REVISION_WINDOW must be a positive duration (e.g. 15m)")
+	}
+
+	cfg.BuyRevisionWindow = window
+
+	global, err := parseBoolOrDefault(os.Getenv("DISCORD_GLOBAL_COMMANDS"), false)
+	if err != nil {
+		return Config{}, fmt.Errorf("DISCORD_GLOBAL_COMMANDS must be true or false")
+	}
+
+	cfg.DiscordGlobalCommands = global
+
+	thresholds, err := loadReminderThresholds()
+	if err != nil {
+		return Config{}, err
+	}
+
+	cfg.ReminderThresholds = thresholds
+
 	if cfg.NotionToken == "" {
 		return Config{}, fmt.Errorf("NOTION_TOKEN is required")
 	}
@@ -92,3 +141,76 @@ func parseTagRoleMap(raw string) map[string]string {
 
 	return m
 }
+
+func parseDurationOrDefault(raw string, def time.Duration) (time.Duration, error) {
+	if raw == "" {
+		return def, nil
+	}
+
+	d, err := time.ParseDuration(raw)
+	if err != nil {
+		return 0, fmt.Errorf("parse duration %q: %w", raw, err)
+	}
+
+	return d, nil
+}
+
+func parseFloatOrDefault(raw string, def float64) (float64, error) {
+	if raw == "" {
+		return def, nil
+	}
+
+	f, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parse number %q: %w", raw, err)
+	}
+
+	return f, nil
+}
+
+func parseBoolOrDefault(raw string, def bool) (bool, error) {
+	if raw == "" {
+		return def, nil
+	}
+
+	b, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, fmt.Errorf("parse bool %q: %w", raw, err)
+	}
+
+	return b, nil
+}
+
+// loadReminderThresholds reads the debt reminder thresholds per currency and the
+// one for members of the shared others database, who are reminded of any amount
+// by default.
+func loadReminderThresholds() (domain.ReminderThresholds, error) {
+	twd, err := parseThreshold("REMINDER_THRESHOLD_TWD", defaultReminderThresholdTWD)
+	if err != nil {
+		return domain.ReminderThresholds{}, err
+	}
+
+	jpy, err := parseThreshold("REMINDER_THRESHOLD_JPY", defaultReminderThresholdJPY)
+	if err != nil {
+		return domain.ReminderThresholds{}, err
+	}
+
+	others, err := parseThreshold("REMINDER_THRESHOLD_OTHERS", 0)
+	if err != nil {
+		return domain.ReminderThresholds{}, err
+	}
+
+	return domain.ReminderThresholds{
+		ByCurrency: map[domain.Currency]float64{domain.CurrencyTWD: twd, domain.CurrencyJPY: jpy},
+		Others:     others,
+	}, nil
+}
+
+func parseThreshold(name string, def float64) (float64, error) {
+	v, err := parseFloatOrDefault(os.Getenv(name), def)
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("%s must be a non-negative number", name)
+	}
+
+	return v, nil
+}
@@ -2,8 +2,11 @@ package config
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/xgnid-tw/gx5/domain"
 )
 
 func TestParseTagRoleMap(t *testing.T) {
@@ -25,3 +28,78 @@ func TestParseTagRoleMap(t *testing.T) {
 		})
 	}
 }
+
+func TestParseDurationOrDefault(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"empty uses default", "", time.Minute, false},
+		{"minutes", "5m", 5 * time.Minute, false},
+		{"seconds", "90s", 90 * time.Second, false},
+		{"invalid", "soon", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseDurationOrDefault(tt.raw, time.Minute)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseBoolOrDefault(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		want    bool
+		wantErr bool
+	}{
+		{"empty uses default", "", true, false},
+		{"false", "false", false, false},
+		{"one", "1", true, false},
+		{"invalid", "yes", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseBoolOrDefault(tt.raw, true)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestLoadReminderThresholds(t *testing.T) {
+	t.Setenv("REMINDER_THRESHOLD_TWD", "")
+	t.Setenv("REMINDER_THRESHOLD_JPY", "10000")
+	t.Setenv("REMINDER_THRESHOLD_OTHERS", "500")
+
+	thresholds, err := loadReminderThresholds()
+
+	require.NoError(t, err)
+	require.Equal(t, domain.ReminderThresholds{
+		ByCurrency: map[domain.Currency]float64{domain.CurrencyTWD: 2000, domain.CurrencyJPY: 10000},
+		Others:     500,
+	}, thresholds)
+
+	for _, raw := range []string{"-1", "many"} {
+		t.Setenv("REMINDER_THRESHOLD_OTHERS", raw)
+
+		_, err = loadReminderThresholds()
+		require.EqualError(t, err, "REMINDER_THRESHOLD_OTHERS must be a non-negative number")
+	}
+}
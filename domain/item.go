@@ -0,0 +1,84 @@
+package domain
+
+import "slices"
+
+// ItemStatus is the 物品狀況 of a purchased item. Values are the default TBL-002 select
+// options; the Notion gateway translates them through its schema mapping.
+type ItemStatus string
+
+const (
+	ItemStatusNotOrdered ItemStatus = "未訂購"
+	ItemStatusOrdered    ItemStatus = "已下訂"
+	ItemStatusArrivedJP  ItemStatus = "已到貨"
+	ItemStatusArrivedTW  ItemStatus = "已回台"
+
+	// Rows that are charges rather than goods; they never advance.
+	ItemStatusPaidOnBehalf ItemStatus = "代付"
+	ItemStatusShipping     ItemStatus = "運費"
+)
+
+// itemProgress is the order in which goods move from purchase to pickup in Taiwan.
+var itemProgress = []ItemStatus{
+	ItemStatusNotOrdered, ItemStatusOrdered, ItemStatusArrivedJP, ItemStatusArrivedTW,
+}
+
+// ItemProgress returns the statuses an item can be advanced through, in order.
+func ItemProgress() []ItemStatus {
+	return slices.Clone(itemProgress)
+}
+
+// rank returns the position of s in itemProgress. An unset status counts as 未訂購.
+func (s ItemStatus) rank() int {
+	if s == "" {
+		return 0
+	}
+
+	return slices.Index(itemProgress, s)
+}
+
+// Next returns the status that follows s, or false when s is final or not a progress status.
+func (s ItemStatus) Next() (ItemStatus, bool) {
+	r := s.rank()
+	if r < 0 || r+1 >= len(itemProgress) {
+		return "", false
+	}
+
+	return itemProgress[r+1], true
+}
+
+// CanAdvanceTo reports whether an item in status s may move forward to to.
+func (s ItemStatus) CanAdvanceTo(to ItemStatus) bool {
+	from, target := s.rank(), slices.Index(itemProgress, to)
+
+	return from >= 0 && target > from
+}
+
+// ItemRecord is a TBL-002 or TBL-003 row as needed for fulfillment tracking.
+type ItemRecord struct {
+	PageID     string
+	ItemName   string
+	Status     ItemStatus
+	DatabaseID string // parent database of the row
+	BuyerName  string // 購買人, TBL-003 rows only
+	JPYAmount  float64
+	TWDAmount  float64
+	Shop       string // 購買途徑, may be empty
+	Link       string // 連結, may be empty
+	Note       string // 備註, may be empty
+
+	ExpectedArrival string // 預計到貨: ISO-8601 date, may be empty
+}
+
+// ItemStatusChange is the outcome of advancing a single item.
+type ItemStatusChange struct {
+	Item ItemRecord
+	From ItemStatus
+	To   ItemStatus
+}
+
+// ItemStatusReport summarizes a bulk status update.
+type ItemStatusReport struct {
+	Advanced []ItemStatusChange
+	Skipped  int // items already at or past the target status, or not goods
+	Notified int // members sent an arrival DM
+}
@@ -1,5 +1,11 @@
 package domain
 
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
 type Tag string
 
 const (
@@ -14,8 +20,106 @@ const (
 var ValidTags = []Tag{Tag315Pro, TagGakumas, Tag283Pro, Tag346Pro, Tag765Pro}
 
 type Order struct {
+	PageID     string // Notion page ID, set when read from TBL-004
 	ThreadName string
 	Deadline   string // ISO-8601 date, may be empty
 	Tag        Tag    // single select, may be empty
-	ShopURL    string // not persisted to Notion
+	ShopURL    string // may be empty
+	ThreadID   string // Discord thread opened for the order
+	ChannelID  string // channel the thread was opened in
+	CreatedBy  string // Discord ID of the operator who ran /neworder
+	Status     OrderStatus
+	SummaryID  string // pinned summary message in the thread
+}
+
+// OrderStatus is the lifecycle stage of an order. Values are the default TBL-004 select
+// options; the Notion gateway translates them through its schema mapping.
+type OrderStatus string
+
+const (
+	OrderStatusOpen    OrderStatus = "開放中"
+	OrderStatusClosed  OrderStatus = "已截止"
+	OrderStatusOrdered OrderStatus = "已下訂"
+	OrderStatusShipped OrderStatus = "已出貨"
+	OrderStatusArrived OrderStatus = "已到貨"
+	OrderStatusSettled OrderStatus = "已結清"
+)
+
+// orderLifecycle is the only path an order may take, one step at a time.
+var orderLifecycle = []OrderStatus{
+	OrderStatusOpen, OrderStatusClosed, OrderStatusOrdered,
+	OrderStatusShipped, OrderStatusArrived, OrderStatusSettled,
+}
+
+// OrderLifecycle returns every order status in lifecycle order.
+func OrderLifecycle() []OrderStatus {
+	return slices.Clone(orderLifecycle)
+}
+
+// rank returns the position of s in orderLifecycle. An unset status counts as 開放中,
+// since orders created before the status column existed were never closed.
+func (s OrderStatus) rank() int {
+	if s == "" {
+		return 0
+	}
+
+	return slices.Index(orderLifecycle, s)
+}
+
+// CanTransitionTo reports whether an order in status s may move to to,
+// which is only allowed for the status directly following s.
+func (s OrderStatus) CanTransitionTo(to OrderStatus) bool {
+	from := s.rank()
+
+	return from >= 0 && from+1 < len(orderLifecycle) && orderLifecycle[from+1] == to
+}
+
+// OrderStatusChange is the outcome of moving an order to a new status.
+type OrderStatusChange struct {
+	Order Order
+	From  OrderStatus
+	To    OrderStatus
+}
+
+// DeadlineAt returns the moment an order with the given ISO-8601 deadline
+// closes: the end of that day in loc.
+func DeadlineAt(deadline string, loc *time.Location) (time.Time, error) {
+	day, err := time.ParseInLocation("2006-01-02", deadline, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid deadline %q: %w", deadline, err)
+	}
+
+	return day.AddDate(0, 0, 1), nil
+}
+
+// DeadlineEvent is an action due for an open order: a reminder in its thread
+// while time is left, or closing the order once the deadline has passed.
+type DeadlineEvent struct {
+	ThreadID string
+	At       time.Time
+	Left     time.Duration // time left until the deadline at At; zero closes the order
+}
+
+// IsClose reports whether the event closes the order rather than reminding.
+func (e DeadlineEvent) IsClose() bool {
+	return e.Left == 0
+}
+
+// OrderRollup totals the records linked to an order.
+type OrderRollup struct {
+	Order        Order
+	Participants []string // member names, sorted
+	Lines        []OrderLine
+	Items        int
+	TotalJPY     float64  // owed to the shop
+	TotalTWD     float64  // collected from members
+	Unreadable   []string // members whose database could not be read, left out of the totals
+}
+
+// OrderLine is a single record linked to an order.
+type OrderLine struct {
+	Participant string
+	ItemName    string
+	JPYAmount   float64
+	TWDAmount   float64
 }
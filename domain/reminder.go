@@ -0,0 +1,68 @@
+package domain
+
+// ReminderOutcome is what a debt reminder run did for one member.
+type ReminderOutcome string
+
+const (
+	ReminderNotified ReminderOutcome = "notified"
+	ReminderSkipped  ReminderOutcome = "skipped"
+	ReminderFailed   ReminderOutcome = "failed"
+)
+
+// ReminderResult records the outcome of a debt reminder run for one member.
+type ReminderResult struct {
+	User      User
+	Amount    float64 // unpaid total in the member's currency
+	Threshold float64
+	Outcome   ReminderOutcome
+	Reason    string // why the member was skipped or what failed; empty when notified
+}
+
+// ReminderThresholds are the unpaid totals above which the debt reminder DMs a
+// member, unless the member's TBL-001 row sets its own.
+type ReminderThresholds struct {
+	ByCurrency map[Currency]float64
+	Others     float64 // members whose ledger is the shared TBL-003
+}
+
+// ReminderRun holds the options of one debt reminder run.
+type ReminderRun struct {
+	Debug bool
+	// MinAmount, when set, replaces every member's threshold for this run only.
+	// It is read in each member's own currency, and owing exactly MinAmount is
+	// reminded.
+	MinAmount *float64
+}
+
+// ReminderReport is the result of one debt reminder run, one entry per member in
+// TBL-001 order.
+type ReminderReport struct {
+	ReminderRun
+	Results []ReminderResult
+}
+
+// Count returns how many members had the given outcome.
+func (r ReminderReport) Count(o ReminderOutcome) int {
+	n := 0
+
+	for _, res := range r.Results {
+		if res.Outcome == o {
+			n++
+		}
+	}
+
+	return n
+}
+
+// Failed returns the results of the members whose reminder failed.
+func (r ReminderReport) Failed() []ReminderResult {
+	var failed []ReminderResult
+
+	for _, res := range r.Results {
+		if res.Outcome == ReminderFailed {
+			failed = append(failed, res)
+		}
+	}
+
+	return failed
+}
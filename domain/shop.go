@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"net/url"
+	"strings"
+)
+
+// shopHosts maps store domains to their 購買途徑 select value.
+var shopHosts = map[string]string{
+	"asobistore.jp":         "阿搜比",
+	"hmv.co.jp":             "HMV",
+	"aniplexplus.com":       "aniplex+",
+	"store.nintendo.co.jp":  "任天堂官網",
+	"animate-onlineshop.jp": "アニメイトストア",
+	"jp.mercari.com":        "メルカリ",
+	"auctions.yahoo.co.jp":  "ヤフオク",
+	"booth.pm":              "Booth",
+	"suruga-ya.jp":          "駿河屋オンラインショップ",
+	"amiami.jp":             "あみあみオンラインショップ",
+	"cystore.com":           "CyStore",
+}
+
+// ShopFromURL returns the 購買途徑 for a store link, or "" when the store is unknown.
+// Subdomains match their parent domain, e.g. "shop.asobistore.jp" is 阿搜比.
+func ShopFromURL(rawURL string) string {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return ""
+	}
+
+	host := strings.ToLower(u.Hostname())
+
+	for host != "" {
+		if shop, ok := shopHosts[host]; ok {
+			return shop
+		}
+
+		_, parent, found := strings.Cut(host, ".")
+		if !found {
+			break
+		}
+
+		host = parent
+	}
+
+	return ""
+}
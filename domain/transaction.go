@@ -1,16 +1,111 @@
 package domain
 
-// Transaction represents a buy record to be inserted into a member's TBL-002.
+import "time"
+
+// Transaction represents a buy record to be inserted into a member's TBL-002,
+// or into the shared TBL-003 for members whose notion_id points there.
 type Transaction struct {
 	ItemName   string  // 品項: thread title
 	JPYAmount  float64 // 日幣: user-input JPY amount
 	TWDAmount  float64 // 台幣: JPY × exchange rate
 	DatabaseID string  // target member's TBL-002 database ID (from TBL-001 notion_id)
+	BuyerName  string  // 購買人: TBL-001 name, set only for TBL-003 rows
+	OrderID    string  // 訂單: TBL-004 page of the order thread, may be empty
+
+	ItemStatus      ItemStatus // 物品狀況
+	Shop            string     // 購買途徑, may be empty
+	Link            string     // 連結, may be empty
+	Note            string     // 備註, may be empty
+	ExpectedArrival string     // 預計到貨: ISO-8601 date, may be empty
+}
+
+// BuyRequest is the input of a /buy registration.
+type BuyRequest struct {
+	TargetDiscordID string
+	ThreadID        string // order thread the record was registered in
+	JPYAmount       float64
+	ItemName        string
+	Link            string
+	Note            string
+	ExpectedArrival string // ISO-8601 date, may be empty
 }
 
 // BuyResult contains the result of a successful buy record registration.
 type BuyResult struct {
-	DisplayAmount float64
-	Currency      Currency
-	ItemName      string
+	PageID          string
+	TargetDiscordID string // member the record was registered for
+	DisplayAmount   float64
+	Currency        Currency
+	ItemName        string
+	ItemStatus      ItemStatus
+}
+
+// BuyRevision identifies a /buy record someone asks to undo or edit, together
+// with who registered it and when, as recorded on the /buy success message.
+type BuyRevision struct {
+	PageID       string
+	RegisteredBy string    // Discord ID of the member who submitted the /buy modal
+	RegisteredAt time.Time // when the success message was posted
+	ActorID      string    // Discord ID of the member asking for the change
+	ActorIsAdmin bool
+	At           time.Time
+}
+
+// UnpaidItem is a single unpaid row in a member's TBL-002 or the shared TBL-003.
+type UnpaidItem struct {
+	PageID     string
+	ItemName   string     // 品項
+	TWDAmount  float64    // 台幣
+	JPYAmount  float64    // 日幣
+	ItemStatus ItemStatus // 物品狀況, may be empty
+	Shop       string     // 購買途徑, may be empty
+	CreatedAt  time.Time  // 建立時間
+	URL        string     // Notion page URL
+}
+
+// Amount returns the item's amount in the given currency.
+func (i UnpaidItem) Amount(c Currency) float64 {
+	if c == CurrencyJPY {
+		return i.JPYAmount
+	}
+
+	return i.TWDAmount
+}
+
+// UnpaidLedger is a member's unpaid items with their total in the member's currency.
+type UnpaidLedger struct {
+	User   User
+	Items  []UnpaidItem
+	Total  float64
+	Shared bool // read from the shared TBL-003 rather than the member's own TBL-002
+}
+
+// SettleReport is the outcome of marking a member's items as paid.
+type SettleReport struct {
+	Settled   int // items now marked 已付款
+	Requested int // items that were still unpaid and owned by the member
+}
+
+// Balance is a member's unpaid ledger together with the total above which the
+// debt reminder DMs them.
+type Balance struct {
+	UnpaidLedger
+	Threshold float64
+}
+
+// OverThreshold reports whether the debt reminder would DM the member.
+func (b Balance) OverThreshold() bool {
+	return b.Total > b.Threshold
+}
+
+// Debts lists the balances of every member who owes money.
+type Debts struct {
+	Balances []*Balance
+	Failed   []DebtFailure // members whose unpaid items could not be read
+}
+
+// DebtFailure is a member left out of Debts because their ledger failed to load.
+type DebtFailure struct {
+	User   User
+	Reason string
 }
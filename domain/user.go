@@ -12,4 +12,8 @@ type User struct {
 	Name      string
 	NotionID  string
 	Currency  Currency
+
+	// ReminderThreshold overrides the debt reminder threshold for this member,
+	// in their currency. nil means the configured default applies.
+	ReminderThreshold *float64
 }
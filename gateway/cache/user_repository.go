@@ -0,0 +1,117 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/xgnid-tw/gx5/domain"
+	"github.com/xgnid-tw/gx5/port"
+)
+
+// UserRepository decorates a port.UserRepository with an in-memory member
+// directory keyed by Discord ID. Unpaid amount queries are passed through.
+type UserRepository struct {
+	port.UserRepository
+
+	ttl time.Duration
+	now func() time.Time
+
+	mu       sync.Mutex
+	users    []*domain.User
+	byID     map[string]*domain.User
+	loadedAt time.Time
+
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+func NewUserRepository(next port.UserRepository, ttl time.Duration) *UserRepository {
+	return &UserRepository{UserRepository: next, ttl: ttl, now: time.Now}
+}
+
+func (r *UserRepository) GetUsers(ctx context.Context) ([]*domain.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	err := r.ensureLoaded(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]*domain.User, len(r.users))
+	for i, u := range r.users {
+		c := *u
+		users[i] = &c
+	}
+
+	return users, nil
+}
+
+func (r *UserRepository) GetUserByDiscordID(ctx context.Context, discordID string) (*domain.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	err := r.ensureLoaded(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	u, ok := r.byID[discordID]
+	if !ok {
+		return nil, fmt.Errorf("%w for discord_id: %s", port.ErrUserNotFound, discordID)
+	}
+
+	c := *u
+
+	return &c, nil
+}
+
+// Refresh implements port.MemberDirectory.
+func (r *UserRepository) Refresh(ctx context.Context) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.loadedAt = time.Time{}
+
+	err := r.ensureLoaded(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(r.users), nil
+}
+
+// Stats implements port.MemberDirectory.
+func (r *UserRepository) Stats() port.CacheStats {
+	return port.CacheStats{Hits: r.hits.Load(), Misses: r.misses.Load()}
+}
+
+// ensureLoaded reloads the directory when it is empty or older than the TTL.
+// The caller must hold r.mu.
+func (r *UserRepository) ensureLoaded(ctx context.Context) error {
+	if !r.loadedAt.IsZero() && r.now().Sub(r.loadedAt) < r.ttl {
+		r.hits.Add(1)
+		return nil
+	}
+
+	r.misses.Add(1)
+
+	users, err := r.UserRepository.GetUsers(ctx)
+	if err != nil {
+		return err
+	}
+
+	byID := make(map[string]*domain.User, len(users))
+	for _, u := range users {
+		byID[u.DiscordID] = u
+	}
+
+	r.users = users
+	r.byID = byID
+	r.loadedAt = r.now()
+
+	return nil
+}
@@ -0,0 +1,146 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/xgnid-tw/gx5/domain"
+	"github.com/xgnid-tw/gx5/mocks"
+	"github.com/xgnid-tw/gx5/port"
+)
+
+var testUsers = []*domain.User{
+	{DiscordID: "111", Name: "Alice", NotionID: "abc", Currency: domain.CurrencyTWD},
+	{DiscordID: "222", Name: "Bob", NotionID: "def", Currency: domain.CurrencyJPY},
+}
+
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) now() time.Time { return c.t }
+
+func newTestUserRepository(next *mocks.UserRepository, ttl time.Duration) (*UserRepository, *fakeClock) {
+	clock := &fakeClock{t: time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)}
+	r := NewUserRepository(next, ttl)
+	r.now = clock.now
+
+	return r, clock
+}
+
+func TestGetUserByDiscordID_CachedWithinTTL(t *testing.T) {
+	next := mocks.NewUserRepository(t)
+	next.On("GetUsers", mock.Anything).Return(testUsers, nil).Once()
+
+	r, clock := newTestUserRepository(next, time.Minute)
+
+	u, err := r.GetUserByDiscordID(context.Background(), "111")
+	require.NoError(t, err)
+	require.Equal(t, "Alice", u.Name)
+
+	clock.t = clock.t.Add(30 * time.Second)
+
+	u, err = r.GetUserByDiscordID(context.Background(), "222")
+	require.NoError(t, err)
+	require.Equal(t, "Bob", u.Name)
+
+	require.Equal(t, uint64(1), r.Stats().Hits)
+	require.Equal(t, uint64(1), r.Stats().Misses)
+}
+
+func TestGetUserByDiscordID_ReloadsAfterTTL(t *testing.T) {
+	next := mocks.NewUserRepository(t)
+	next.On("GetUsers", mock.Anything).Return(testUsers, nil).Twice()
+
+	r, clock := newTestUserRepository(next, time.Minute)
+
+	_, err := r.GetUserByDiscordID(context.Background(), "111")
+	require.NoError(t, err)
+
+	clock.t = clock.t.Add(time.Minute)
+
+	_, err = r.GetUserByDiscordID(context.Background(), "111")
+	require.NoError(t, err)
+
+	require.Equal(t, uint64(2), r.Stats().Misses)
+}
+
+func TestGetUserByDiscordID_NotFound(t *testing.T) {
+	next := mocks.NewUserRepository(t)
+	next.On("GetUsers", mock.Anything).Return(testUsers, nil).Once()
+
+	r, _ := newTestUserRepository(next, time.Minute)
+
+	_, err := r.GetUserByDiscordID(context.Background(), "999")
+
+	require.ErrorIs(t, err, port.ErrUserNotFound)
+	require.ErrorContains(t, err, "user not found for discord_id")
+}
+
+func TestGetUserByDiscordID_LoadErrorNotCached(t *testing.T) {
+	next := mocks.NewUserRepository(t)
+	next.On("GetUsers", mock.Anything).Return(nil, errors.New("api down")).Once()
+	next.On("GetUsers", mock.Anything).Return(testUsers, nil).Once()
+
+	r, _ := newTestUserRepository(next, time.Minute)
+
+	_, err := r.GetUserByDiscordID(context.Background(), "111")
+	require.ErrorContains(t, err, "api down")
+
+	u, err := r.GetUserByDiscordID(context.Background(), "111")
+	require.NoError(t, err)
+	require.Equal(t, "Alice", u.Name)
+}
+
+func TestGetUsers_ReturnsCopies(t *testing.T) {
+	next := mocks.NewUserRepository(t)
+	next.On("GetUsers", mock.Anything).Return(testUsers, nil).Once()
+
+	r, _ := newTestUserRepository(next, time.Minute)
+
+	users, err := r.GetUsers(context.Background())
+	require.NoError(t, err)
+	require.Len(t, users, 2)
+
+	users[0].Name = "Mallory"
+
+	u, err := r.GetUserByDiscordID(context.Background(), "111")
+	require.NoError(t, err)
+	require.Equal(t, "Alice", u.Name)
+}
+
+func TestRefresh_ReloadsWithinTTL(t *testing.T) {
+	next := mocks.NewUserRepository(t)
+	next.On("GetUsers", mock.Anything).Return(testUsers[:1], nil).Once()
+	next.On("GetUsers", mock.Anything).Return(testUsers, nil).Once()
+
+	r, _ := newTestUserRepository(next, time.Hour)
+
+	_, err := r.GetUserByDiscordID(context.Background(), "222")
+	require.Error(t, err)
+
+	n, err := r.Refresh(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+
+	u, err := r.GetUserByDiscordID(context.Background(), "222")
+	require.NoError(t, err)
+	require.Equal(t, "Bob", u.Name)
+}
+
+func TestGetUnpaidItems_PassesThrough(t *testing.T) {
+	items := []domain.UnpaidItem{{PageID: "p1", TWDAmount: 1500}}
+
+	next := mocks.NewUserRepository(t)
+	next.On("GetUnpaidItems", mock.Anything, "abc").Return(items, nil)
+
+	r, _ := newTestUserRepository(next, time.Minute)
+
+	got, err := r.GetUnpaidItems(context.Background(), "abc")
+
+	require.NoError(t, err)
+	require.Equal(t, items, got)
+}
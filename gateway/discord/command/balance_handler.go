@@ -0,0 +1,133 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/xgnid-tw/gx5/domain"
+	"github.com/xgnid-tw/gx5/port"
+)
+
+const (
+	balanceCommandName     = "balance"
+	balanceUserCommandName = "查看欠款"
+
+	maxEmbedDescriptionLen = 4096 // Discord limit per embed description
+	balanceEmbedColor      = 0xF1C40F
+)
+
+// RegisterBalanceCommand registers the /balance slash command, with which members
+// look up their own unpaid items, and the 查看欠款 user command with which admins
+// look up anyone's. Both reply ephemerally.
+func RegisterBalanceCommand(ch *Handler, uc port.BalanceChecker) {
+	adminPerm := int64(discordgo.PermissionAdministrator)
+
+	ch.RegisterCommand(&discordgo.ApplicationCommand{
+		Name:        balanceCommandName,
+		Description: "查看自己的未付款項目",
+	}, func(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+		handleBalance(ctx, s, i, uc, interactionUserID(i), true)
+	})
+
+	ch.RegisterCommand(&discordgo.ApplicationCommand{
+		Name:                     balanceUserCommandName,
+		Type:                     discordgo.UserApplicationCommand,
+		DefaultMemberPermissions: &adminPerm,
+	}, func(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+		handleBalance(ctx, s, i, uc, i.ApplicationCommandData().TargetID, false)
+	})
+}
+
+func handleBalance(
+	ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate,
+	uc port.BalanceChecker, discordID string, self bool,
+) {
+	if discordID == "" {
+		respondError(ctx, s, i, "無法取得成員")
+		return
+	}
+
+	respondDeferredEphemeral(ctx, s, i)
+
+	balance, err := uc.Balance(ctx, discordID)
+
+	switch {
+	case errors.Is(err, port.ErrUserNotFound) && self:
+		editDeferredResponse(ctx, s, i, "你不在成員名單中，請聯絡管理員")
+		return
+	case errors.Is(err, port.ErrUserNotFound):
+		editDeferredResponse(ctx, s, i, fmt.Sprintf("<@%s> 不在成員名單中", discordID))
+		return
+	case err != nil:
+		logf(ctx, "check balance failed: %s", err)
+		editDeferredResponse(ctx, s, i, failureMessage(err, "無法取得未付款項目"))
+
+		return
+	}
+
+	if len(balance.Items) == 0 && self {
+		editDeferredResponse(ctx, s, i, "你目前沒有未付款項目")
+		return
+	}
+
+	if len(balance.Items) == 0 {
+		editDeferredResponse(ctx, s, i, fmt.Sprintf("<@%s> 目前沒有未付款項目", discordID))
+		return
+	}
+
+	content := ""
+	embeds := []*discordgo.MessageEmbed{balanceEmbed(balance)}
+
+	_, err = s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Content: &content,
+		Embeds:  &embeds,
+	})
+	if err != nil {
+		logf(ctx, "error editing deferred response: %s", err)
+	}
+}
+
+// balanceEmbed lists the unpaid items, linked to their Notion pages, with the
+// total and whether the debt reminder would DM the member.
+func balanceEmbed(b *domain.Balance) *discordgo.MessageEmbed {
+	lines := make([]string, 0, len(b.Items))
+
+	for _, it := range b.Items {
+		name := it.ItemName
+		if name == "" {
+			name = "（未命名）"
+		}
+
+		if it.URL != "" {
+			name = fmt.Sprintf("[%s](%s)", name, it.URL)
+		}
+
+		lines = append(lines, fmt.Sprintf("・%s ¥%.0f / NT$%.0f", name, it.JPYAmount, it.TWDAmount))
+	}
+
+	reminder := "未達催款門檻"
+	if b.OverThreshold() {
+		reminder = "下次催款時會收到提醒"
+	}
+
+	threshold := "任何金額"
+	if b.Threshold > 0 {
+		threshold = "超過 " + formatAmount(b.User.Currency, b.Threshold)
+	}
+
+	return &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("💰 %s 的未付款項目", b.User.Name),
+		Description: truncate(strings.Join(lines, "\n"), maxEmbedDescriptionLen),
+		Color:       balanceEmbedColor,
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "品項", Value: fmt.Sprintf("%d 筆", len(b.Items)), Inline: true},
+			{Name: "合計", Value: formatAmount(b.User.Currency, b.Total), Inline: true},
+			{Name: "催款門檻", Value: threshold, Inline: true},
+		},
+		Footer: &discordgo.MessageEmbedFooter{Text: reminder},
+	}
+}
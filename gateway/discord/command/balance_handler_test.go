@@ -0,0 +1,49 @@
+package command
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/xgnid-tw/gx5/domain"
+)
+
+func TestBalanceEmbed(t *testing.T) {
+	b := &domain.Balance{
+		UnpaidLedger: domain.UnpaidLedger{
+			User: domain.User{Name: "Alice", Currency: domain.CurrencyTWD},
+			Items: []domain.UnpaidItem{
+				{ItemName: "CD", JPYAmount: 3500, TWDAmount: 770, URL: "https://notion.so/p1"},
+				{JPYAmount: 500, TWDAmount: 110},
+			},
+			Total: 880,
+		},
+		Threshold: 2000,
+	}
+
+	embed := balanceEmbed(b)
+
+	require.Equal(t, "・[CD](https://notion.so/p1) ¥3500 / NT$770\n・（未命名） ¥500 / NT$110", embed.Description)
+	require.Equal(t, "NT$880", embed.Fields[1].Value)
+	require.Equal(t, "超過 NT$2000", embed.Fields[2].Value)
+	require.Equal(t, "未達催款門檻", embed.Footer.Text)
+
+	b.Threshold = 0
+	embed = balanceEmbed(b)
+
+	require.Equal(t, "任何金額", embed.Fields[2].Value)
+	require.Equal(t, "下次催款時會收到提醒", embed.Footer.Text)
+}
+
+func TestBalanceEmbed_Truncated(t *testing.T) {
+	items := make([]domain.UnpaidItem, 300)
+	for n := range items {
+		items[n] = domain.UnpaidItem{ItemName: strings.Repeat("品", 20), TWDAmount: 100}
+	}
+
+	embed := balanceEmbed(&domain.Balance{UnpaidLedger: domain.UnpaidLedger{Items: items}})
+
+	require.Len(t, []rune(embed.Description), maxEmbedDescriptionLen)
+	require.True(t, strings.HasSuffix(embed.Description, "…"))
+}
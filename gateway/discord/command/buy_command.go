@@ -2,10 +2,11 @@ package command
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
 
@@ -14,33 +15,57 @@ import (
 )
 
 const (
-	buyCommandName     = "buy"
-	buyModalPrefix     = "buy_modal"
-	amountInputID      = "jpy_amount"
-	itemNameInputID    = "item_name"
-	modalCustomIDParts = 2
+	buyCommandName  = "buy"
+	buyModalPrefix  = "buy_modal"
+	amountInputID   = "jpy_amount"
+	itemNameInputID = "item_name"
+	linkInputID     = "link"
+	arrivalInputID  = "expected_arrival"
+	noteInputID     = "note"
+
+	arrivalLayout = "2006-01-02"
+)
+
+var (
+	errInvalidAmount  = errors.New("無效的日幣金額")
+	errInvalidArrival = errors.New("無效的預計到貨日期，請填寫 YYYY-MM-DD（例: 2026-11-30）")
 )
 
-// RegisterBuyCommand registers the /buy message command and its modal handler.
-func RegisterBuyCommand(ch *Handler, uc port.BuyRecordRegisterer) {
+// RegisterBuyCommand registers the /buy message command, its modal handler and the
+// 撤銷 / 修改 buttons on its result. The order summary pinned in the thread is
+// refreshed after each registration, cancellation and edit.
+func RegisterBuyCommand(
+	ch *Handler, uc port.BuyRecordRegisterer, reviser port.BuyRecordReviser, summary port.OrderSummarizer,
+) {
 	cmd := &discordgo.ApplicationCommand{
 		Name: buyCommandName,
 		Type: discordgo.MessageApplicationCommand,
 	}
 
-	ch.RegisterCommand(cmd, handleBuyCommand)
-
-	ch.RegisterModalHandler(buyModalPrefix, func(s *discordgo.Session, i *discordgo.InteractionCreate) {
-		handleBuyModal(s, i, uc)
+	ch.RegisterCommand(cmd, func(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+		handleBuyCommand(ctx, s, i, uc)
 	})
+
+	// Registering writes to Notion before answering, which can outlast Discord's deadline.
+	ch.RegisterModalHandler(
+		buyModalPrefix,
+		func(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+			handleBuyModal(ctx, s, i, uc, summary)
+		},
+		AutoDefer(DefaultAutoDeferAfter, false),
+	)
+
+	registerBuyRevision(ch, reviser, summary)
 }
 
-func handleBuyCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+func handleBuyCommand(
+	ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, uc port.BuyRecordRegisterer,
+) {
 	data := i.ApplicationCommandData()
 
 	targetMsg, ok := data.Resolved.Messages[data.TargetID]
 	if !ok {
-		respondError(s, i, "無法取得目標訊息")
+		respondError(ctx, s, i, "無法取得目標訊息")
 		return
 	}
 
@@ -48,109 +73,189 @@ func handleBuyCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
 
 	channel, err := s.Channel(i.ChannelID)
 	if err != nil {
-		respondError(s, i, "無法取得頻道資訊")
+		respondError(ctx, s, i, "無法取得頻道資訊")
 		return
 	}
 
 	if !channel.IsThread() {
-		respondError(s, i, "此指令只能在討論串中使用")
+		respondError(ctx, s, i, "此指令只能在討論串中使用")
 		return
 	}
 
 	// Format: buy_modal:<targetDiscordID>
 	customID := fmt.Sprintf("%s:%s", buyModalPrefix, targetDiscordID)
 
-	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-		Type: discordgo.InteractionResponseModal,
-		Data: &discordgo.InteractionResponseData{
-			CustomID: customID,
-			Title:    "確認購買",
+	// The link is only a suggestion, so the modal still opens without it.
+	link, err := uc.OrderShopURL(ctx, channel.ID)
+	if err != nil {
+		logf(ctx, "get order shop url failed: %s", err)
+	}
+
+	respondModal(ctx, s, i, &discordgo.InteractionResponseData{
+		CustomID: customID,
+		Title:    "確認購買",
+		Components: buyModalComponents(map[string]string{
+			itemNameInputID: channel.Name,
+			linkInputID:     link,
+		}),
+	})
+}
+
+// buyModalComponents returns the text inputs of the /buy modal pre-filled with
+// values, keyed by input custom ID. It is shared by the 修改 modal. Discord
+// allows five inputs, so 購買途徑 has none: it is derived from the link.
+func buyModalComponents(values map[string]string) []discordgo.MessageComponent {
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.TextInput{
+					CustomID:    amountInputID,
+					Label:       "日幣",
+					Style:       discordgo.TextInputShort,
+					Placeholder: "例: 3000",
+					Required:    true,
+					Value:       values[amountInputID],
+				},
+			},
+		},
+		discordgo.ActionsRow{
 			Components: []discordgo.MessageComponent{
-				discordgo.ActionsRow{
-					Components: []discordgo.MessageComponent{
-						discordgo.TextInput{
-							CustomID:    amountInputID,
-							Label:       "日幣",
-							Style:       discordgo.TextInputShort,
-							Placeholder: "例: 3000",
-							Required:    true,
-						},
-					},
+				discordgo.TextInput{
+					CustomID: itemNameInputID,
+					Label:    "品項",
+					Style:    discordgo.TextInputShort,
+					Required: true,
+					Value:    values[itemNameInputID],
 				},
-				discordgo.ActionsRow{
-					Components: []discordgo.MessageComponent{
-						discordgo.TextInput{
-							CustomID: itemNameInputID,
-							Label:    "品項",
-							Style:    discordgo.TextInputShort,
-							Required: true,
-							Value:    channel.Name,
-						},
-					},
+			},
+		},
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.TextInput{
+					CustomID: linkInputID,
+					Label:    "連結",
+					Style:    discordgo.TextInputShort,
+					Required: false,
+					Value:    values[linkInputID],
+				},
+			},
+		},
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.TextInput{
+					CustomID:    arrivalInputID,
+					Label:       "預計到貨",
+					Style:       discordgo.TextInputShort,
+					Placeholder: "例: 2026-11-30",
+					Required:    false,
+					Value:       values[arrivalInputID],
+					MaxLength:   len(arrivalLayout),
+				},
+			},
+		},
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.TextInput{
+					CustomID: noteInputID,
+					Label:    "備註",
+					Style:    discordgo.TextInputParagraph,
+					Required: false,
+					Value:    values[noteInputID],
 				},
 			},
 		},
-	})
-	if err != nil {
-		log.Printf("error responding with modal: %s", err)
 	}
 }
 
 func handleBuyModal(
-	s *discordgo.Session, i *discordgo.InteractionCreate, uc port.BuyRecordRegisterer,
+	ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, uc port.BuyRecordRegisterer,
+	summary port.OrderSummarizer,
 ) {
 	data := i.ModalSubmitData()
 
 	// Format: buy_modal:<targetDiscordID>
-	parts := strings.SplitN(data.CustomID, ":", modalCustomIDParts)
-	if len(parts) != modalCustomIDParts {
-		respondError(s, i, "無效的表單資料")
+	targetDiscordID, ok := customIDArg(data.CustomID)
+	if !ok {
+		respondError(ctx, s, i, "無效的表單資料")
 		return
 	}
 
-	targetDiscordID := parts[1]
+	values := modalValues(data)
 
-	var jpyStr string
+	req, err := buyRequest(values)
+	if err != nil {
+		respondError(ctx, s, i, err.Error())
+		return
+	}
 
-	var itemName string
+	req.TargetDiscordID = targetDiscordID
+	req.ThreadID = i.ChannelID
 
-	for _, row := range data.Components {
-		if ar, ok := row.(*discordgo.ActionsRow); ok {
-			for _, comp := range ar.Components {
-				if ti, ok := comp.(*discordgo.TextInput); ok {
-					switch ti.CustomID {
-					case amountInputID:
-						jpyStr = ti.Value
-					case itemNameInputID:
-						itemName = ti.Value
-					}
-				}
-			}
-		}
-	}
+	result, err := uc.Execute(ctx, req)
+	if err != nil {
+		logf(ctx, "register buy record failed: %s", err)
+		respondError(ctx, s, i, failureMessage(err, "登記失敗"))
 
-	jpyAmount, err := strconv.ParseFloat(jpyStr, 64)
-	if err != nil || jpyAmount <= 0 {
-		respondError(s, i, "無效的日幣金額")
 		return
 	}
 
-	result, err := uc.Execute(context.Background(), targetDiscordID, jpyAmount, itemName)
-	if err != nil {
-		log.Printf("register buy record failed: %s", err)
-		respondError(s, i, "登記失敗")
+	respondSuccess(
+		ctx, s, i, formatBuyResult(targetDiscordID, result), buyRecordComponents(result.PageID, result.ItemStatus),
+	)
 
-		return
+	refreshOrderSummary(ctx, summary, i.ChannelID)
+}
+
+// buyRequest reads the /buy modal inputs. Its errors are meant for the user.
+func buyRequest(values map[string]string) (domain.BuyRequest, error) {
+	jpyAmount, err := strconv.ParseFloat(values[amountInputID], 64)
+	if err != nil || jpyAmount <= 0 {
+		return domain.BuyRequest{}, errInvalidAmount
 	}
 
-	respondSuccess(s, i, formatBuyResult(targetDiscordID, result))
+	arrival := strings.TrimSpace(values[arrivalInputID])
+	if arrival != "" {
+		_, err = time.Parse(arrivalLayout, arrival)
+		if err != nil {
+			return domain.BuyRequest{}, errInvalidArrival
+		}
+	}
+
+	return domain.BuyRequest{
+		JPYAmount:       jpyAmount,
+		ItemName:        values[itemNameInputID],
+		Link:            strings.TrimSpace(values[linkInputID]),
+		Note:            strings.TrimSpace(values[noteInputID]),
+		ExpectedArrival: arrival,
+	}, nil
+}
+
+// refreshOrderSummary updates the pinned order summary after the interaction was
+// answered, since recomputing it takes longer than Discord waits for a response.
+func refreshOrderSummary(ctx context.Context, summary port.OrderSummarizer, threadID string) {
+	err := summary.Refresh(ctx, threadID)
+	if err != nil && !errors.Is(err, port.ErrOrderNotFound) {
+		logf(ctx, "refresh order summary failed: %s", err)
+	}
 }
 
 func formatBuyResult(discordID string, r *domain.BuyResult) string {
-	symbol := "NT$"
-	if r.Currency == domain.CurrencyJPY {
-		symbol = "¥"
+	return fmt.Sprintf("登記完畢 <@%s> %s (%s)", discordID, formatAmount(r.Currency, r.DisplayAmount), r.ItemName)
+}
+
+// modalValues returns the submitted text input values keyed by custom ID.
+func modalValues(data discordgo.ModalSubmitInteractionData) map[string]string {
+	values := make(map[string]string)
+
+	for _, row := range data.Components {
+		if ar, ok := row.(*discordgo.ActionsRow); ok {
+			for _, comp := range ar.Components {
+				if ti, ok := comp.(*discordgo.TextInput); ok {
+					values[ti.CustomID] = ti.Value
+				}
+			}
+		}
 	}
 
-	return fmt.Sprintf("登記完畢 <@%s> %s%.0f (%s)", discordID, symbol, r.DisplayAmount, r.ItemName)
+	return values
 }
@@ -0,0 +1,199 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/xgnid-tw/gx5/domain"
+	"github.com/xgnid-tw/gx5/port"
+)
+
+const (
+	buyCancelPrefix = "buy_cancel"
+	buyEditPrefix   = "buy_edit" // used by both the 修改 button and its modal
+)
+
+func registerBuyRevision(ch *Handler, reviser port.BuyRecordReviser, summary port.OrderSummarizer) {
+	ch.RegisterComponentHandler(buyCancelPrefix, func(
+		ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate,
+	) {
+		handleBuyCancel(ctx, s, i, reviser, summary)
+	})
+
+	ch.RegisterComponentHandler(buyEditPrefix, func(
+		ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate,
+	) {
+		handleBuyEditButton(ctx, s, i, reviser)
+	})
+
+	ch.RegisterModalHandler(buyEditPrefix, func(
+		ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate,
+	) {
+		handleBuyEditModal(ctx, s, i, reviser, summary)
+	})
+}
+
+func handleBuyCancel(
+	ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, reviser port.BuyRecordReviser,
+	summary port.OrderSummarizer,
+) {
+	// Format: buy_cancel:<pageID>
+	rev, ok := buyRevision(i, i.MessageComponentData().CustomID)
+	if !ok {
+		respondError(ctx, s, i, "無效的操作")
+		return
+	}
+
+	respondDeferredUpdate(ctx, s, i)
+
+	_, err := reviser.Cancel(ctx, rev)
+	if err != nil {
+		logf(ctx, "cancel buy record failed: %s", err)
+		followupError(ctx, s, i, buyRevisionFailure(err, "撤銷失敗"))
+
+		return
+	}
+
+	editDeferredResponse(ctx, s, i, fmt.Sprintf("~~%s~~\n已由 <@%s> 撤銷", i.Message.Content, rev.ActorID))
+
+	refreshOrderSummary(ctx, summary, i.ChannelID)
+}
+
+func handleBuyEditButton(
+	ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, reviser port.BuyRecordReviser,
+) {
+	// Format: buy_edit:<pageID>
+	rev, ok := buyRevision(i, i.MessageComponentData().CustomID)
+	if !ok {
+		respondError(ctx, s, i, "無效的操作")
+		return
+	}
+
+	// A modal cannot follow a deferred response, so the record is read up front.
+	item, err := reviser.Get(ctx, rev)
+	if err != nil {
+		logf(ctx, "get buy record failed: %s", err)
+		respondError(ctx, s, i, buyRevisionFailure(err, "無法取得登記紀錄"))
+
+		return
+	}
+
+	respondModal(ctx, s, i, &discordgo.InteractionResponseData{
+		CustomID: fmt.Sprintf("%s:%s", buyEditPrefix, rev.PageID),
+		Title:    "修改購買紀錄",
+		Components: buyModalComponents(map[string]string{
+			amountInputID:   strconv.FormatFloat(item.JPYAmount, 'f', -1, 64),
+			itemNameInputID: item.ItemName,
+			linkInputID:     item.Link,
+			arrivalInputID:  item.ExpectedArrival,
+			noteInputID:     item.Note,
+		}),
+	})
+}
+
+func handleBuyEditModal(
+	ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, reviser port.BuyRecordReviser,
+	summary port.OrderSummarizer,
+) {
+	data := i.ModalSubmitData()
+
+	// Format: buy_edit:<pageID>
+	rev, ok := buyRevision(i, data.CustomID)
+	if !ok {
+		respondError(ctx, s, i, "無效的表單資料")
+		return
+	}
+
+	req, err := buyRequest(modalValues(data))
+	if err != nil {
+		respondError(ctx, s, i, err.Error())
+		return
+	}
+
+	respondDeferredUpdate(ctx, s, i)
+
+	result, err := reviser.Edit(ctx, rev, req)
+	if err != nil {
+		logf(ctx, "edit buy record failed: %s", err)
+		followupError(ctx, s, i, buyRevisionFailure(err, "修改失敗"))
+
+		return
+	}
+
+	content := formatBuyResult(result.TargetDiscordID, result) + "（已修改）"
+	components := buyRecordComponents(result.PageID, result.ItemStatus)
+
+	_, err = s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Content:    &content,
+		Components: &components,
+	})
+	if err != nil {
+		logf(ctx, "error editing buy result: %s", err)
+	}
+
+	refreshOrderSummary(ctx, summary, i.ChannelID)
+}
+
+// buyRevision builds the change request for a button on, or a modal opened from,
+// a /buy success message. Who registered the record and when is taken from that
+// message, so it cannot be forged through the custom ID.
+func buyRevision(i *discordgo.InteractionCreate, customID string) (domain.BuyRevision, bool) {
+	pageID, ok := customIDArg(customID)
+	if !ok {
+		return domain.BuyRevision{}, false
+	}
+
+	m := i.Message
+	if m == nil || m.InteractionMetadata == nil || m.InteractionMetadata.User == nil {
+		return domain.BuyRevision{}, false
+	}
+
+	return domain.BuyRevision{
+		PageID:       pageID,
+		RegisteredBy: m.InteractionMetadata.User.ID,
+		RegisteredAt: m.Timestamp,
+		ActorID:      interactionUserID(i),
+		ActorIsAdmin: i.Member != nil && i.Member.Permissions&discordgo.PermissionAdministrator != 0,
+		At:           time.Now(),
+	}, true
+}
+
+// buyRecordComponents returns the buttons of a /buy result: the 物品狀況 button
+// while the item can still advance, and 撤銷 / 修改.
+func buyRecordComponents(pageID string, status domain.ItemStatus) []discordgo.MessageComponent {
+	if pageID == "" {
+		return []discordgo.MessageComponent{}
+	}
+
+	return append(itemStatusComponents(pageID, status), discordgo.ActionsRow{
+		Components: []discordgo.MessageComponent{
+			discordgo.Button{
+				Label:    "撤銷",
+				Style:    discordgo.DangerButton,
+				CustomID: fmt.Sprintf("%s:%s", buyCancelPrefix, pageID),
+			},
+			discordgo.Button{
+				Label:    "修改",
+				Style:    discordgo.SecondaryButton,
+				CustomID: fmt.Sprintf("%s:%s", buyEditPrefix, pageID),
+			},
+		},
+	})
+}
+
+// buyRevisionFailure explains why a record could not be undone or edited.
+func buyRevisionFailure(err error, fallback string) string {
+	switch {
+	case errors.Is(err, port.ErrBuyRevisionDenied):
+		return "只有登記者或管理員可以撤銷或修改此紀錄"
+	case errors.Is(err, port.ErrBuyRevisionExpired):
+		return "已超過可撤銷或修改的時間，請聯絡管理員在 Notion 修改"
+	default:
+		return failureMessage(err, fallback)
+	}
+}
@@ -0,0 +1,93 @@
+package command
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/require"
+
+	"github.com/xgnid-tw/gx5/domain"
+	"github.com/xgnid-tw/gx5/port"
+)
+
+func TestBuyRecordComponents(t *testing.T) {
+	components := buyRecordComponents("p1", domain.ItemStatusNotOrdered)
+
+	require.Len(t, components, 2)
+
+	revise := components[1].(discordgo.ActionsRow).Components
+	require.Equal(t, "buy_cancel:p1", revise[0].(discordgo.Button).CustomID)
+	require.Equal(t, "buy_edit:p1", revise[1].(discordgo.Button).CustomID)
+
+	// Items that cannot advance keep only the 撤銷 / 修改 row.
+	require.Len(t, buyRecordComponents("p1", domain.ItemStatusArrivedTW), 1)
+	require.Empty(t, buyRecordComponents("", domain.ItemStatusNotOrdered))
+}
+
+func TestBuyRevision(t *testing.T) {
+	posted := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+
+	i := &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+		Member: &discordgo.Member{
+			User:        &discordgo.User{ID: "555"},
+			Permissions: discordgo.PermissionAdministrator,
+		},
+		Message: &discordgo.Message{
+			Timestamp:           posted,
+			InteractionMetadata: &discordgo.MessageInteractionMetadata{User: &discordgo.User{ID: "999"}},
+		},
+	}}
+
+	rev, ok := buyRevision(i, "buy_cancel:p1")
+
+	require.True(t, ok)
+	require.Equal(t, "p1", rev.PageID)
+	require.Equal(t, "999", rev.RegisteredBy)
+	require.Equal(t, posted, rev.RegisteredAt)
+	require.Equal(t, "555", rev.ActorID)
+	require.True(t, rev.ActorIsAdmin)
+
+	_, ok = buyRevision(i, "buy_cancel")
+	require.False(t, ok)
+
+	i.Message.InteractionMetadata = nil
+	_, ok = buyRevision(i, "buy_cancel:p1")
+	require.False(t, ok)
+}
+
+func TestBuyRequest(t *testing.T) {
+	req, err := buyRequest(map[string]string{
+		amountInputID: "3500", itemNameInputID: "CD", linkInputID: " https://someone.booth.pm/items/1 ",
+		arrivalInputID: " 2026-11-30 ", noteInputID: "2026/12 左右",
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, domain.BuyRequest{
+		JPYAmount: 3500, ItemName: "CD", Link: "https://someone.booth.pm/items/1",
+		Note: "2026/12 左右", ExpectedArrival: "2026-11-30",
+	}, req, "a note that looks like a date stays a note")
+
+	for _, amount := range []string{"", "abc", "0", "-1"} {
+		_, err := buyRequest(map[string]string{amountInputID: amount})
+		require.ErrorIs(t, err, errInvalidAmount, amount)
+	}
+
+	for _, arrival := range []string{"2026/11/30", "2026-11-31", "2026-1-5", "11 月底"} {
+		_, err := buyRequest(map[string]string{amountInputID: "3500", arrivalInputID: arrival})
+		require.ErrorIs(t, err, errInvalidArrival, arrival)
+	}
+}
+
+func TestBuyRevisionFailure(t *testing.T) {
+	require.Equal(t,
+		"只有登記者或管理員可以撤銷或修改此紀錄",
+		buyRevisionFailure(fmt.Errorf("%w: x", port.ErrBuyRevisionDenied), "撤銷失敗"),
+	)
+	require.Contains(t,
+		buyRevisionFailure(fmt.Errorf("%w: x", port.ErrBuyRevisionExpired), "撤銷失敗"),
+		"已超過可撤銷或修改的時間",
+	)
+	require.Equal(t, "撤銷失敗", buyRevisionFailure(fmt.Errorf("boom"), "撤銷失敗"))
+}
@@ -1,100 +1,134 @@
 package command
 
 import (
-	"fmt"
-	"log"
+	"context"
+	"strings"
 
 	"github.com/bwmarrin/discordgo"
 )
 
 // Handler manages Discord application command registration and dispatch.
 type Handler struct {
-	session    *discordgo.Session
-	appID      string
-	commands   []*discordgo.ApplicationCommand
-	handlers   map[string]func(s *discordgo.Session, i *discordgo.InteractionCreate)
-	registered []*discordgo.ApplicationCommand
+	session     *discordgo.Session
+	appID       string
+	commands    []*discordgo.ApplicationCommand
+	handlers    map[string]HandlerFunc
+	middlewares []Middleware
+	guildID     string // scope of the commands; "" for global commands
 }
 
-func NewHandler(session *discordgo.Session, appID string) *Handler {
+// NewHandler creates a Handler whose commands are synced to guildID, or
+// globally when guildID is empty.
+func NewHandler(session *discordgo.Session, appID string, guildID string) *Handler {
 	h := &Handler{
 		session:  session,
 		appID:    appID,
-		handlers: make(map[string]func(s *discordgo.Session, i *discordgo.InteractionCreate)),
+		handlers: make(map[string]HandlerFunc),
+		guildID:  guildID,
 	}
 
 	session.AddHandler(func(s *discordgo.Session, i *discordgo.InteractionCreate) {
-		switch i.Type {
-		case discordgo.InteractionApplicationCommand:
-			if handler, ok := h.handlers[i.ApplicationCommandData().Name]; ok {
-				handler(s, i)
-			}
-		case discordgo.InteractionModalSubmit:
-			customID := i.ModalSubmitData().CustomID
-			prefix := customID[:modalPrefixLen(customID)]
-
-			if handler, ok := h.handlers["modal:"+prefix]; ok {
-				handler(s, i)
-			}
-		case discordgo.InteractionPing,
-			discordgo.InteractionMessageComponent,
-			discordgo.InteractionApplicationCommandAutocomplete:
-			// not handled
+		if handler := h.route(i); handler != nil {
+			chain(handler, h.middlewares)(context.Background(), s, i)
 		}
 	})
 
 	return h
 }
 
-// RegisterCommand registers an application command with its handler.
-func (h *Handler) RegisterCommand(
-	cmd *discordgo.ApplicationCommand,
-	handler func(s *discordgo.Session, i *discordgo.InteractionCreate),
-) {
+// Use adds middlewares that run around every command, modal, component and
+// autocomplete handler, in the order given and outside any route middlewares.
+func (h *Handler) Use(mws ...Middleware) {
+	h.middlewares = append(h.middlewares, mws...)
+}
+
+// route returns the handler registered for the interaction, or nil when there is none.
+func (h *Handler) route(i *discordgo.InteractionCreate) HandlerFunc {
+	switch i.Type {
+	case discordgo.InteractionApplicationCommand:
+		return h.handlers[i.ApplicationCommandData().Name]
+	case discordgo.InteractionModalSubmit:
+		return h.handlers["modal:"+customIDPrefix(i.ModalSubmitData().CustomID)]
+	case discordgo.InteractionMessageComponent:
+		return h.handlers["component:"+customIDPrefix(i.MessageComponentData().CustomID)]
+	case discordgo.InteractionApplicationCommandAutocomplete:
+		data := i.ApplicationCommandData()
+
+		opt := focusedOption(data.Options)
+		if opt == nil {
+			return nil
+		}
+
+		return h.handlers[autocompleteKey(data.Name, opt.Name)]
+	case discordgo.InteractionPing:
+		// not handled
+	}
+
+	return nil
+}
+
+// RegisterCommand registers an application command with its handler. The
+// middlewares apply to this command only.
+func (h *Handler) RegisterCommand(cmd *discordgo.ApplicationCommand, handler HandlerFunc, mws ...Middleware) {
 	h.commands = append(h.commands, cmd)
-	h.handlers[cmd.Name] = handler
+	h.handlers[cmd.Name] = chain(handler, mws)
 }
 
 // RegisterModalHandler registers a handler for modal submissions with a given prefix.
-func (h *Handler) RegisterModalHandler(
-	prefix string,
-	handler func(s *discordgo.Session, i *discordgo.InteractionCreate),
-) {
-	h.handlers["modal:"+prefix] = handler
+func (h *Handler) RegisterModalHandler(prefix string, handler HandlerFunc, mws ...Middleware) {
+	h.handlers["modal:"+prefix] = chain(handler, mws)
+}
+
+// RegisterComponentHandler registers a handler for message components (buttons,
+// select menus) whose custom ID starts with the given prefix.
+func (h *Handler) RegisterComponentHandler(prefix string, handler HandlerFunc, mws ...Middleware) {
+	h.handlers["component:"+prefix] = chain(handler, mws)
 }
 
-// SyncCommands creates all registered commands with the Discord API.
-func (h *Handler) SyncCommands() error {
-	for _, cmd := range h.commands {
-		registered, err := h.session.ApplicationCommandCreate(h.appID, "", cmd)
-		if err != nil {
-			return fmt.Errorf("create command %q: %w", cmd.Name, err)
+// RegisterAutocomplete registers a handler for autocomplete requests on the given
+// option of a command. Options inside subcommands are matched by name alone.
+func (h *Handler) RegisterAutocomplete(command string, option string, handler HandlerFunc, mws ...Middleware) {
+	h.handlers[autocompleteKey(command, option)] = chain(handler, mws)
+}
+
+func autocompleteKey(command string, option string) string {
+	return "autocomplete:" + command + customIDSeparator + option
+}
+
+// focusedOption returns the option the user is typing in, searching subcommands
+// and subcommand groups, or nil when none is focused.
+func focusedOption(
+	opts []*discordgo.ApplicationCommandInteractionDataOption,
+) *discordgo.ApplicationCommandInteractionDataOption {
+	for _, opt := range opts {
+		if opt.Focused {
+			return opt
 		}
 
-		h.registered = append(h.registered, registered)
+		if found := focusedOption(opt.Options); found != nil {
+			return found
+		}
 	}
 
 	return nil
 }
 
-// UnregisterAll removes all registered commands from the Discord API.
-func (h *Handler) UnregisterAll() {
-	for _, cmd := range h.registered {
-		err := h.session.ApplicationCommandDelete(h.appID, "", cmd.ID)
-		if err != nil {
-			log.Printf("error removing command %s: %s", cmd.Name, err)
-		}
-	}
+// customIDSeparator separates the handler prefix of a modal or component custom
+// ID from its argument, as in "item_status:<pageID>".
+const customIDSeparator = ":"
+
+// customIDPrefix returns the part of a custom ID before the first separator, which
+// selects the handler, or the whole ID when there is no separator.
+func customIDPrefix(customID string) string {
+	prefix, _, _ := strings.Cut(customID, customIDSeparator)
+
+	return prefix
 }
 
-// modalPrefixLen returns the length up to the first ':' separator in a modal custom ID,
-// or the full length if no separator is found.
-func modalPrefixLen(customID string) int {
-	for i, c := range customID {
-		if c == ':' {
-			return i
-		}
-	}
+// customIDArg returns the part of a custom ID after the first separator. It
+// reports false when the ID carries no argument.
+func customIDArg(customID string) (string, bool) {
+	_, arg, found := strings.Cut(customID, customIDSeparator)
 
-	return len(customID)
+	return arg, found && arg != ""
 }
@@ -1,27 +1,166 @@
 package command
 
 import (
+	"context"
 	"testing"
 
+	"github.com/bwmarrin/discordgo"
 	"github.com/stretchr/testify/require"
 )
 
-func TestModalPrefixLen(t *testing.T) {
+func TestCustomIDPrefix(t *testing.T) {
 	tests := []struct {
 		name     string
 		customID string
-		want     int
+		want     string
 	}{
-		{"with colon separator", "buy_modal:123:title", 9},
-		{"no colon", "buy_modal", 9},
-		{"empty string", "", 0},
-		{"colon at start", ":rest", 0},
+		{"with colon separator", "buy_modal:123:title", "buy_modal"},
+		{"no colon", "buy_modal", "buy_modal"},
+		{"empty string", "", ""},
+		{"colon at start", ":rest", ""},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := modalPrefixLen(tt.customID)
+			require.Equal(t, tt.want, customIDPrefix(tt.customID))
+		})
+	}
+}
+
+func TestCustomIDArg(t *testing.T) {
+	tests := []struct {
+		name     string
+		customID string
+		want     string
+		wantOK   bool
+	}{
+		{"single argument", "item_status:p1", "p1", true},
+		{"argument with separator", "buy_modal:123:title", "123:title", true},
+		{"no separator", "item_status", "", false},
+		{"empty argument", "item_status:", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := customIDArg(tt.customID)
+			require.Equal(t, tt.wantOK, ok)
 			require.Equal(t, tt.want, got)
 		})
 	}
 }
+
+// routedTo registers a handler for each key and returns which one, if any, the
+// handler routes the interaction to.
+func routedTo(t *testing.T, i *discordgo.InteractionCreate) string {
+	t.Helper()
+
+	h := NewHandler(&discordgo.Session{}, "app", "guild")
+
+	var called string
+
+	record := func(name string) HandlerFunc {
+		return func(context.Context, *discordgo.Session, *discordgo.InteractionCreate) { called = name }
+	}
+
+	h.RegisterCommand(&discordgo.ApplicationCommand{Name: "order"}, record("command"))
+	h.RegisterModalHandler("buy_modal", record("modal"))
+	h.RegisterComponentHandler("buy_modal", record("component"))
+	h.RegisterAutocomplete("order", "to", record("autocomplete"))
+
+	handler := h.route(i)
+	if handler == nil {
+		return ""
+	}
+
+	handler(context.Background(), nil, i)
+
+	return called
+}
+
+func interaction(typ discordgo.InteractionType, data discordgo.InteractionData) *discordgo.InteractionCreate {
+	return &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{Type: typ, Data: data}}
+}
+
+func TestHandlerRoute(t *testing.T) {
+	tests := []struct {
+		name string
+		i    *discordgo.InteractionCreate
+		want string
+	}{
+		{
+			"command",
+			interaction(discordgo.InteractionApplicationCommand, discordgo.ApplicationCommandInteractionData{
+				Name: "order",
+			}),
+			"command",
+		},
+		{
+			"unknown command",
+			interaction(discordgo.InteractionApplicationCommand, discordgo.ApplicationCommandInteractionData{
+				Name: "buy",
+			}),
+			"",
+		},
+		{
+			"modal by prefix",
+			interaction(discordgo.InteractionModalSubmit, discordgo.ModalSubmitInteractionData{
+				CustomID: "buy_modal:111",
+			}),
+			"modal",
+		},
+		{
+			"component by prefix",
+			interaction(discordgo.InteractionMessageComponent, discordgo.MessageComponentInteractionData{
+				CustomID: "buy_modal:111",
+			}),
+			"component",
+		},
+		{
+			"unknown component",
+			interaction(discordgo.InteractionMessageComponent, discordgo.MessageComponentInteractionData{
+				CustomID: "buy_modalx:111",
+			}),
+			"",
+		},
+		{
+			"autocomplete on focused option in subcommand",
+			interaction(discordgo.InteractionApplicationCommandAutocomplete, discordgo.ApplicationCommandInteractionData{
+				Name: "order",
+				Options: []*discordgo.ApplicationCommandInteractionDataOption{
+					{
+						Name: "status",
+						Type: discordgo.ApplicationCommandOptionSubCommand,
+						Options: []*discordgo.ApplicationCommandInteractionDataOption{
+							{Name: "to", Type: discordgo.ApplicationCommandOptionString, Focused: true},
+						},
+					},
+				},
+			}),
+			"autocomplete",
+		},
+		{
+			"autocomplete on other option",
+			interaction(discordgo.InteractionApplicationCommandAutocomplete, discordgo.ApplicationCommandInteractionData{
+				Name: "order",
+				Options: []*discordgo.ApplicationCommandInteractionDataOption{
+					{Name: "from", Type: discordgo.ApplicationCommandOptionString, Focused: true},
+				},
+			}),
+			"",
+		},
+		{
+			"autocomplete without focused option",
+			interaction(discordgo.InteractionApplicationCommandAutocomplete, discordgo.ApplicationCommandInteractionData{
+				Name: "order",
+			}),
+			"",
+		},
+		{"ping", interaction(discordgo.InteractionPing, nil), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, routedTo(t, tt.i))
+		})
+	}
+}
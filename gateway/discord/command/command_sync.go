@@ -0,0 +1,167 @@
+package command
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// SyncCommands makes the application commands known to Discord in the handler's
+// scope match the registered ones. The whole set is replaced with one bulk
+// overwrite, and only when a command was added, changed or removed, so restarts
+// leave the commands in place instead of re-creating them.
+func (h *Handler) SyncCommands() error {
+	existing, err := h.session.ApplicationCommands(h.appID, h.guildID)
+	if err != nil {
+		return fmt.Errorf("list commands in %s: %w", commandScope(h.guildID), err)
+	}
+
+	changes := diffCommands(existing, h.commands)
+	if len(changes) == 0 {
+		log.Printf("%d commands in %s are up to date", len(h.commands), commandScope(h.guildID))
+		return nil
+	}
+
+	_, err = h.session.ApplicationCommandBulkOverwrite(h.appID, h.guildID, h.commands)
+	if err != nil {
+		return fmt.Errorf("overwrite commands in %s: %w", commandScope(h.guildID), err)
+	}
+
+	log.Printf("commands in %s synced: %s", commandScope(h.guildID), strings.Join(changes, ", "))
+
+	return nil
+}
+
+// ClearCommands removes the application's commands from another scope than the
+// handler's, e.g. the global copies left behind after switching to guild commands,
+// so they are not listed twice. It does nothing when the scope has none.
+func (h *Handler) ClearCommands(guildID string) error {
+	existing, err := h.session.ApplicationCommands(h.appID, guildID)
+	if err != nil {
+		return fmt.Errorf("list commands in %s: %w", commandScope(guildID), err)
+	}
+
+	if len(existing) == 0 {
+		return nil
+	}
+
+	_, err = h.session.ApplicationCommandBulkOverwrite(h.appID, guildID, []*discordgo.ApplicationCommand{})
+	if err != nil {
+		return fmt.Errorf("clear commands in %s: %w", commandScope(guildID), err)
+	}
+
+	log.Printf("removed %d stale commands from %s", len(existing), commandScope(guildID))
+
+	return nil
+}
+
+func commandScope(guildID string) string {
+	if guildID == "" {
+		return "global scope"
+	}
+
+	return "guild " + guildID
+}
+
+// diffCommands lists the commands that differ between existing, as read back from
+// Discord, and wanted, as "+name" for added, "~name" for changed and "-name" for
+// removed commands, sorted by name. It is empty when nothing needs syncing.
+func diffCommands(existing []*discordgo.ApplicationCommand, wanted []*discordgo.ApplicationCommand) []string {
+	current := make(map[string]*discordgo.ApplicationCommand, len(existing))
+	for _, cmd := range existing {
+		current[commandKey(cmd)] = cmd
+	}
+
+	var changes []string
+
+	for _, cmd := range wanted {
+		key := commandKey(cmd)
+
+		old, ok := current[key]
+		delete(current, key)
+
+		switch {
+		case !ok:
+			changes = append(changes, "+"+cmd.Name)
+		case !reflect.DeepEqual(comparableCommand(old), comparableCommand(cmd)):
+			changes = append(changes, "~"+cmd.Name)
+		}
+	}
+
+	for _, cmd := range current {
+		changes = append(changes, "-"+cmd.Name)
+	}
+
+	slices.SortStableFunc(changes, func(a, b string) int { return strings.Compare(a[1:], b[1:]) })
+
+	return changes
+}
+
+// commandKey identifies a command; a slash command and a context menu command
+// may share a name.
+func commandKey(cmd *discordgo.ApplicationCommand) string {
+	return fmt.Sprintf("%d:%s", commandType(cmd), cmd.Name)
+}
+
+func commandType(cmd *discordgo.ApplicationCommand) discordgo.ApplicationCommandType {
+	if cmd.Type == 0 {
+		return discordgo.ChatApplicationCommand
+	}
+
+	return cmd.Type
+}
+
+// comparableCommand keeps the fields this bot sets, with the defaults Discord
+// fills in applied, so a command read back from Discord equals the one it was
+// created from. IDs, versions and localizations are left out.
+func comparableCommand(cmd *discordgo.ApplicationCommand) discordgo.ApplicationCommand {
+	c := discordgo.ApplicationCommand{
+		Type:        commandType(cmd),
+		Name:        cmd.Name,
+		Description: cmd.Description,
+		Options:     comparableOptions(cmd.Options),
+	}
+
+	if cmd.DefaultMemberPermissions != nil {
+		perm := *cmd.DefaultMemberPermissions
+		c.DefaultMemberPermissions = &perm
+	}
+
+	return c
+}
+
+func comparableOptions(opts []*discordgo.ApplicationCommandOption) []*discordgo.ApplicationCommandOption {
+	if len(opts) == 0 {
+		return nil
+	}
+
+	out := make([]*discordgo.ApplicationCommandOption, 0, len(opts))
+
+	for _, opt := range opts {
+		o := *opt
+		o.NameLocalizations = nil
+		o.DescriptionLocalizations = nil
+		o.Options = comparableOptions(opt.Options)
+		o.Choices = nil
+
+		if len(opt.ChannelTypes) == 0 {
+			o.ChannelTypes = nil
+		}
+
+		// Choice values come back from the API as JSON strings or float64.
+		for _, ch := range opt.Choices {
+			o.Choices = append(o.Choices, &discordgo.ApplicationCommandOptionChoice{
+				Name:  ch.Name,
+				Value: fmt.Sprint(ch.Value),
+			})
+		}
+
+		out = append(out, &o)
+	}
+
+	return out
+}
@@ -0,0 +1,168 @@
+package command
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/require"
+)
+
+func orderCommand() *discordgo.ApplicationCommand {
+	adminPerm := int64(discordgo.PermissionAdministrator)
+
+	return &discordgo.ApplicationCommand{
+		Name:                     "order",
+		Description:              "管理訂單",
+		DefaultMemberPermissions: &adminPerm,
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "to",
+				Description: "新狀態",
+				Required:    true,
+				Choices: []*discordgo.ApplicationCommandOptionChoice{
+					{Name: "已下單", Value: "ordered"},
+				},
+			},
+			{Type: discordgo.ApplicationCommandOptionInteger, Name: "count", Description: "數量"},
+		},
+	}
+}
+
+// fromDiscord returns cmd as Discord lists it back, with IDs and defaults filled in.
+func fromDiscord(cmd *discordgo.ApplicationCommand) *discordgo.ApplicationCommand {
+	dmPermission := true
+
+	c := *cmd
+	c.ID, c.ApplicationID, c.Version = "cmd-"+cmd.Name, "app", "1"
+	c.Type = commandType(cmd)
+	c.DMPermission = &dmPermission
+
+	return &c
+}
+
+func TestDiffCommands(t *testing.T) {
+	buy := &discordgo.ApplicationCommand{Name: "登記購買", Type: discordgo.MessageApplicationCommand}
+
+	changed := orderCommand()
+	changed.Options[0].Description = "訂單的新狀態"
+
+	withChoice := orderCommand()
+	withChoice.Options[1].Choices = []*discordgo.ApplicationCommandOptionChoice{{Name: "一", Value: 1}}
+
+	listedChoice := orderCommand()
+	listedChoice.Options[1].Choices = []*discordgo.ApplicationCommandOptionChoice{{Name: "一", Value: float64(1)}}
+
+	tests := []struct {
+		name     string
+		existing []*discordgo.ApplicationCommand
+		wanted   []*discordgo.ApplicationCommand
+		want     []string
+	}{
+		{
+			"unchanged",
+			[]*discordgo.ApplicationCommand{fromDiscord(orderCommand()), fromDiscord(buy)},
+			[]*discordgo.ApplicationCommand{buy, orderCommand()},
+			nil,
+		},
+		{
+			"numeric choice read back as float",
+			[]*discordgo.ApplicationCommand{fromDiscord(listedChoice)},
+			[]*discordgo.ApplicationCommand{withChoice},
+			nil,
+		},
+		{
+			"changed option",
+			[]*discordgo.ApplicationCommand{fromDiscord(orderCommand())},
+			[]*discordgo.ApplicationCommand{changed},
+			[]string{"~order"},
+		},
+		{
+			"added and removed",
+			[]*discordgo.ApplicationCommand{fromDiscord(orderCommand()), fromDiscord(&discordgo.ApplicationCommand{
+				Name: "old", Description: "舊指令",
+			})},
+			[]*discordgo.ApplicationCommand{orderCommand(), buy},
+			[]string{"-old", "+登記購買"},
+		},
+		{
+			"same name with another type",
+			[]*discordgo.ApplicationCommand{fromDiscord(&discordgo.ApplicationCommand{
+				Name: "order", Type: discordgo.UserApplicationCommand,
+			})},
+			[]*discordgo.ApplicationCommand{orderCommand()},
+			[]string{"+order", "-order"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, diffCommands(tt.existing, tt.wanted))
+		})
+	}
+}
+
+// commandsSession answers command listings with listed.
+func commandsSession(t *testing.T, listed string) (*discordgo.Session, *recordingTransport) {
+	t.Helper()
+
+	s, transport := recordingSession(t)
+	transport.reply = func(req *http.Request) string {
+		if req.Method == http.MethodGet {
+			return listed
+		}
+
+		return "[]"
+	}
+
+	return s, transport
+}
+
+func TestSyncCommands_Unchanged(t *testing.T) {
+	s, transport := commandsSession(t, `[{"id":"1","application_id":"app","version":"1","type":1,`+
+		`"name":"order","description":"管理訂單","default_member_permissions":"8","dm_permission":true,`+
+		`"options":[{"type":3,"name":"to","description":"新狀態","required":true,`+
+		`"choices":[{"name":"已下單","value":"ordered"}]},{"type":4,"name":"count","description":"數量"}]}]`)
+
+	h := NewHandler(s, "app", "guild")
+	h.RegisterCommand(orderCommand(), nil)
+
+	require.NoError(t, h.SyncCommands())
+
+	calls := transport.recorded()
+	require.Len(t, calls, 1, "nothing is written when the commands are up to date")
+	require.Equal(t, "/api/v9/applications/app/guilds/guild/commands", calls[0].path)
+}
+
+func TestSyncCommands_Changed(t *testing.T) {
+	s, transport := commandsSession(t, `[]`)
+
+	h := NewHandler(s, "app", "")
+	h.RegisterCommand(orderCommand(), nil)
+
+	require.NoError(t, h.SyncCommands())
+
+	calls := transport.recorded()
+	require.Len(t, calls, 2)
+	require.Equal(t, http.MethodPut, calls[1].method)
+	require.Equal(t, "/api/v9/applications/app/commands", calls[1].path)
+}
+
+func TestClearCommands(t *testing.T) {
+	s, transport := commandsSession(t, `[]`)
+	h := NewHandler(s, "app", "guild")
+
+	require.NoError(t, h.ClearCommands(""))
+	require.Len(t, transport.recorded(), 1, "an empty scope is left alone")
+
+	s, transport = commandsSession(t, `[{"id":"1","name":"order","description":"管理訂單"}]`)
+	h = NewHandler(s, "app", "guild")
+
+	require.NoError(t, h.ClearCommands(""))
+
+	calls := transport.recorded()
+	require.Len(t, calls, 2)
+	require.Equal(t, http.MethodPut, calls[1].method)
+	require.Equal(t, "/api/v9/applications/app/commands", calls[1].path)
+}
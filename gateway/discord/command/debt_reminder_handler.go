@@ -4,11 +4,13 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/go-co-op/gocron/v2"
 
+	"github.com/xgnid-tw/gx5/domain"
 	"github.com/xgnid-tw/gx5/port"
 )
 
@@ -16,13 +18,20 @@ const (
 	debtReminderCommandName = "debt-reminder"
 	debtReminderOptionDays  = "days"
 	debtReminderOptionDebug = "debug"
+	debtReminderOptionMin   = "min_amount"
 	defaultDays             = 15
 	minDays                 = 1
+
+	// The failures listed in the reply are capped to stay within Discord's
+	// 2,000 characters; the log channel report has the rest.
+	maxListedFailures = 10
+	maxFailureLineLen = 150
 )
 
 // RegisterDebtReminderCommand registers the /debt-reminder slash command and its handler.
 func RegisterDebtReminderCommand(ch *Handler, uc port.DebtReminder, scheduler gocron.Scheduler) {
 	adminPerm := int64(discordgo.PermissionAdministrator)
+	minAmount := 0.0
 
 	cmd := &discordgo.ApplicationCommand{
 		Name:                     debtReminderCommandName,
@@ -41,19 +50,26 @@ func RegisterDebtReminderCommand(ch *Handler, uc port.DebtReminder, scheduler go
 				Description: "除錯模式（僅傳送至 log 頻道，不發送 DM）",
 				Required:    false,
 			},
+			{
+				Type:        discordgo.ApplicationCommandOptionNumber,
+				Name:        debtReminderOptionMin,
+				Description: "本次改用的最低催款金額，以各成員自己的幣別計，達到即提醒（僅限立即執行）",
+				Required:    false,
+				MinValue:    &minAmount,
+			},
 		},
 	}
 
-	ch.RegisterCommand(cmd, func(s *discordgo.Session, i *discordgo.InteractionCreate) {
-		handleDebtReminder(s, i, uc, scheduler)
+	ch.RegisterCommand(cmd, func(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+		handleDebtReminder(ctx, s, i, uc, scheduler)
 	})
 }
 
 func handleDebtReminder(
-	s *discordgo.Session, i *discordgo.InteractionCreate,
+	ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate,
 	uc port.DebtReminder, scheduler gocron.Scheduler,
 ) {
-	respondDeferred(s, i)
+	respondDeferred(ctx, s, i)
 
 	opts := i.ApplicationCommandData().Options
 
@@ -68,25 +84,40 @@ func handleDebtReminder(
 	}
 
 	if days < minDays {
-		editDeferredResponse(s, i, fmt.Sprintf("天數必須至少為 %d", minDays))
+		editDeferredResponse(ctx, s, i, fmt.Sprintf("天數必須至少為 %d", minDays))
 		return
 	}
 
-	debug := false
+	var run domain.ReminderRun
 	if v, ok := optMap[debtReminderOptionDebug]; ok {
-		debug = v.BoolValue()
+		run.Debug = v.BoolValue()
+	}
+
+	if v, ok := optMap[debtReminderOptionMin]; ok {
+		amount := v.FloatValue()
+		if amount < 0 {
+			editDeferredResponse(ctx, s, i, "催款門檻不可為負數")
+			return
+		}
+
+		run.MinAmount = &amount
 	}
 
 	// Immediate run
-	err := uc.Execute(context.Background(), debug)
-	if err != nil {
-		log.Printf("debt-reminder immediate run failed: %s", err)
-		editDeferredResponse(s, i, fmt.Sprintf("提醒執行失敗: %s", err))
+	report, err := uc.Execute(ctx, run)
+	if report == nil {
+		logf(ctx, "debt-reminder immediate run failed: %s", err)
+		editDeferredResponse(ctx, s, i, fmt.Sprintf("提醒執行失敗: %s", err))
 
 		return
 	}
 
-	// Schedule delayed production run
+	// Members that failed are listed in the reply.
+	if err != nil {
+		logf(ctx, "debt-reminder immediate run had failures: %s", err)
+	}
+
+	// Schedule delayed production run with the configured thresholds
 	runAt := time.Now().Add(time.Duration(days) * 24 * time.Hour)
 
 	_, err = scheduler.NewJob(
@@ -94,29 +125,60 @@ func handleDebtReminder(
 		gocron.NewTask(func() {
 			log.Print("debt-reminder scheduled run")
 
-			err := uc.Execute(context.Background(), false)
-			if err != nil {
+			report, err := uc.Execute(context.Background(), domain.ReminderRun{})
+			if report == nil {
 				log.Printf("debt-reminder scheduled run failed: %s", err)
+				return
 			}
+
+			if err != nil {
+				log.Printf("debt-reminder scheduled run had failures: %s", err)
+			}
+
+			log.Printf(
+				"debt-reminder scheduled run: %d notified, %d skipped, %d failed",
+				report.Count(domain.ReminderNotified), report.Count(domain.ReminderSkipped),
+				report.Count(domain.ReminderFailed),
+			)
 		}),
 	)
 	if err != nil {
-		log.Printf("debt-reminder schedule failed: %s", err)
-		editDeferredResponse(s, i, fmt.Sprintf(
-			"提醒已執行（模式: %s），但排程失敗: %s",
-			modeLabel(debug), err,
-		))
+		logf(ctx, "debt-reminder schedule failed: %s", err)
+		editDeferredResponse(ctx, s, i, reminderSummary(report)+fmt.Sprintf("\n排程失敗: %s", err))
 
 		return
 	}
 
-	editDeferredResponse(s, i, fmt.Sprintf(
-		"提醒已執行（模式: %s）。下次執行: %s（正式模式）",
-		modeLabel(debug),
-		runAt.Format("2006-01-02 15:04"),
+	editDeferredResponse(ctx, s, i, reminderSummary(report)+fmt.Sprintf(
+		"\n下次執行: %s（正式模式）", runAt.Format("2006-01-02 15:04"),
 	))
 }
 
+// reminderSummary reports the counts of a run and why each failed member was not reminded.
+func reminderSummary(report *domain.ReminderReport) string {
+	mode := "模式: " + modeLabel(report.Debug)
+	if report.MinAmount != nil {
+		mode += fmt.Sprintf("，本次門檻: ≥%.0f（各成員幣別）", *report.MinAmount)
+	}
+
+	lines := []string{fmt.Sprintf(
+		"提醒已執行（%s）：通知 %d 人、略過 %d 人、失敗 %d 人", mode,
+		report.Count(domain.ReminderNotified), report.Count(domain.ReminderSkipped),
+		report.Count(domain.ReminderFailed),
+	)}
+
+	failed := report.Failed()
+	for _, res := range failed[:min(len(failed), maxListedFailures)] {
+		lines = append(lines, truncate(fmt.Sprintf("・%s：%s", res.User.Name, res.Reason), maxFailureLineLen))
+	}
+
+	if len(failed) > maxListedFailures {
+		lines = append(lines, fmt.Sprintf("・…另有 %d 人，詳見 log 頻道", len(failed)-maxListedFailures))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
 func modeLabel(debug bool) string {
 	if debug {
 		return "除錯"
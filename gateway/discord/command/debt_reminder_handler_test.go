@@ -0,0 +1,46 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/xgnid-tw/gx5/domain"
+)
+
+func TestReminderSummary(t *testing.T) {
+	report := &domain.ReminderReport{ReminderRun: domain.ReminderRun{Debug: true}, Results: []domain.ReminderResult{
+		{User: domain.User{Name: "Alice"}, Outcome: domain.ReminderNotified},
+		{User: domain.User{Name: "Bob"}, Outcome: domain.ReminderFailed, Reason: "error sending dm"},
+		{User: domain.User{Name: "Carol"}, Outcome: domain.ReminderSkipped},
+	}}
+
+	require.Equal(t,
+		"提醒已執行（模式: 除錯）：通知 1 人、略過 1 人、失敗 1 人\n・Bob：error sending dm",
+		reminderSummary(report),
+	)
+}
+
+func TestReminderSummary_MinAmount(t *testing.T) {
+	minAmount := 500.0
+	report := &domain.ReminderReport{ReminderRun: domain.ReminderRun{MinAmount: &minAmount}}
+
+	require.Equal(t, "提醒已執行（模式: 正式，本次門檻: ≥500（各成員幣別））：通知 0 人、略過 0 人、失敗 0 人", reminderSummary(report))
+}
+
+func TestReminderSummary_CapsFailures(t *testing.T) {
+	report := &domain.ReminderReport{}
+	for n := range maxListedFailures + 3 {
+		report.Results = append(report.Results, domain.ReminderResult{
+			User: domain.User{Name: fmt.Sprint(n)}, Outcome: domain.ReminderFailed, Reason: strings.Repeat("x", 500),
+		})
+	}
+
+	lines := strings.Split(reminderSummary(report), "\n")
+
+	require.Len(t, lines, maxListedFailures+2)
+	require.Equal(t, "・…另有 3 人，詳見 log 頻道", lines[len(lines)-1])
+	require.Len(t, []rune(lines[1]), maxFailureLineLen)
+}
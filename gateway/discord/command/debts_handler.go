@@ -0,0 +1,269 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/xgnid-tw/gx5/domain"
+	"github.com/xgnid-tw/gx5/port"
+)
+
+const (
+	debtsCommandName = "debts"
+	debtsPagePrefix  = "debts_page"
+	debtsPageSize    = 15
+	debtsEmbedColor  = 0xE67E22
+	maxEmbedFieldLen = 1024 // Discord limit per embed field value
+
+	// utf8BOM makes spreadsheet apps read the CSV's Chinese names as UTF-8.
+	utf8BOM = "\uFEFF"
+)
+
+// RegisterDebtsCommand registers the /debts admin command, which lists every
+// member's unpaid balance a page at a time and attaches the whole table as CSV.
+// The page buttons recompute the balances, so every page reflects Notion.
+// Members whose ledger cannot be read are named on every page.
+func RegisterDebtsCommand(ch *Handler, uc port.DebtLister) {
+	adminPerm := int64(discordgo.PermissionAdministrator)
+
+	cmd := &discordgo.ApplicationCommand{
+		Name:                     debtsCommandName,
+		Description:              "列出所有成員的未付款總額",
+		DefaultMemberPermissions: &adminPerm,
+	}
+
+	ch.RegisterCommand(cmd, func(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+		handleDebts(ctx, s, i, uc)
+	})
+
+	ch.RegisterComponentHandler(debtsPagePrefix, func(
+		ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate,
+	) {
+		handleDebtsPage(ctx, s, i, uc)
+	})
+}
+
+func handleDebts(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, uc port.DebtLister) {
+	respondDeferredEphemeral(ctx, s, i)
+
+	debts, err := uc.ListDebts(ctx)
+	if err != nil {
+		logf(ctx, "list debts failed: %s", err)
+		editDeferredResponse(ctx, s, i, failureMessage(err, "無法取得未付款總額"))
+
+		return
+	}
+
+	logDebtFailures(ctx, debts)
+
+	if len(debts.Balances) == 0 && len(debts.Failed) == 0 {
+		editDeferredResponse(ctx, s, i, "目前沒有成員有未付款項目")
+		return
+	}
+
+	content := ""
+	embeds := []*discordgo.MessageEmbed{debtsEmbed(debts, 0)}
+	components := debtsComponents(0, debtsPages(debts.Balances))
+	edit := &discordgo.WebhookEdit{Content: &content, Embeds: &embeds, Components: &components}
+
+	table, err := debtsCSV(debts.Balances)
+	if err != nil {
+		logf(ctx, "render debts csv failed: %s", err)
+	} else {
+		edit.Files = []*discordgo.File{{
+			Name:        "debts-" + time.Now().Format("20060102") + ".csv",
+			ContentType: "text/csv",
+			Reader:      bytes.NewReader(table),
+		}}
+	}
+
+	_, err = s.InteractionResponseEdit(i.Interaction, edit)
+	if err != nil {
+		logf(ctx, "error editing deferred response: %s", err)
+	}
+}
+
+func handleDebtsPage(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, uc port.DebtLister) {
+	// Format: debts_page:<page>
+	arg, _ := customIDArg(i.MessageComponentData().CustomID)
+
+	page, err := strconv.Atoi(arg)
+	if err != nil || page < 0 {
+		respondError(ctx, s, i, "無效的頁碼")
+		return
+	}
+
+	respondDeferredUpdate(ctx, s, i)
+
+	debts, err := uc.ListDebts(ctx)
+	if err != nil {
+		logf(ctx, "list debts failed: %s", err)
+		followupError(ctx, s, i, failureMessage(err, "無法取得未付款總額"))
+
+		return
+	}
+
+	logDebtFailures(ctx, debts)
+
+	if len(debts.Balances) == 0 && len(debts.Failed) == 0 {
+		content := "目前沒有成員有未付款項目"
+
+		_, err = s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+			Content:    &content,
+			Embeds:     &[]*discordgo.MessageEmbed{},
+			Components: &[]discordgo.MessageComponent{},
+		})
+		if err != nil {
+			logf(ctx, "error editing deferred response: %s", err)
+		}
+
+		return
+	}
+
+	// Balances may have been settled since the page was rendered.
+	pages := debtsPages(debts.Balances)
+	page = min(page, pages-1)
+
+	embeds := []*discordgo.MessageEmbed{debtsEmbed(debts, page)}
+	components := debtsComponents(page, pages)
+
+	// The CSV attached to the message is kept as it was.
+	_, err = s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{Embeds: &embeds, Components: &components})
+	if err != nil {
+		logf(ctx, "error editing deferred response: %s", err)
+	}
+}
+
+// logDebtFailures logs why each failed member was left out; the reply only
+// names them.
+func logDebtFailures(ctx context.Context, debts *domain.Debts) {
+	for _, f := range debts.Failed {
+		logf(ctx, "list debts: %s left out: %s", f.User.Name, f.Reason)
+	}
+}
+
+func debtsPages(debts []*domain.Balance) int {
+	return max(1, (len(debts)+debtsPageSize-1)/debtsPageSize)
+}
+
+// debtsEmbed renders one page of the table, numbered across pages, with 🔔 on
+// members the debt reminder would DM and the totals of all pages per currency.
+// Members whose ledger failed to load are named in a field of their own, since
+// their debts are missing from the totals.
+func debtsEmbed(debts *domain.Debts, page int) *discordgo.MessageEmbed {
+	balances := debts.Balances
+	start := page * debtsPageSize
+	end := min(start+debtsPageSize, len(balances))
+
+	lines := make([]string, 0, end-start)
+
+	for n, b := range balances[start:end] {
+		line := fmt.Sprintf(
+			"`%2d` **%s** %s（%d 筆）", start+n+1, b.User.Name, formatAmount(b.User.Currency, b.Total), len(b.Items),
+		)
+		if b.OverThreshold() {
+			line += " 🔔"
+		}
+
+		lines = append(lines, line)
+	}
+
+	if len(lines) == 0 {
+		lines = append(lines, "目前沒有成員有未付款項目")
+	}
+
+	totals := map[domain.Currency]float64{}
+	for _, b := range balances {
+		totals[b.User.Currency] += b.Total
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       "💰 未付款總覽",
+		Description: strings.Join(lines, "\n"),
+		Color:       debtsEmbedColor,
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "成員", Value: fmt.Sprintf("%d 人", len(balances)), Inline: true},
+			{Name: "台幣合計", Value: formatAmount(domain.CurrencyTWD, totals[domain.CurrencyTWD]), Inline: true},
+			{Name: "日幣合計", Value: formatAmount(domain.CurrencyJPY, totals[domain.CurrencyJPY]), Inline: true},
+		},
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: fmt.Sprintf("第 %d / %d 頁・🔔 下次催款時會收到提醒", page+1, debtsPages(balances)),
+		},
+	}
+
+	if len(debts.Failed) > 0 {
+		names := make([]string, len(debts.Failed))
+		for n, f := range debts.Failed {
+			names[n] = f.User.Name
+		}
+
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  fmt.Sprintf("⚠️ 無法讀取 %d 人（未計入合計）", len(debts.Failed)),
+			Value: truncate(strings.Join(names, "、"), maxEmbedFieldLen),
+		})
+	}
+
+	return embed
+}
+
+// debtsComponents returns the 上一頁 / 下一頁 buttons, or none for a single page.
+func debtsComponents(page int, pages int) []discordgo.MessageComponent {
+	if pages <= 1 {
+		return []discordgo.MessageComponent{}
+	}
+
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{
+					Label:    "上一頁",
+					Style:    discordgo.SecondaryButton,
+					CustomID: fmt.Sprintf("%s:%d", debtsPagePrefix, page-1),
+					Disabled: page == 0,
+				},
+				discordgo.Button{
+					Label:    "下一頁",
+					Style:    discordgo.SecondaryButton,
+					CustomID: fmt.Sprintf("%s:%d", debtsPagePrefix, page+1),
+					Disabled: page >= pages-1,
+				},
+			},
+		},
+	}
+}
+
+// debtsCSV renders the whole table, one member per row, in the order shown.
+func debtsCSV(debts []*domain.Balance) ([]byte, error) {
+	var buf bytes.Buffer
+
+	buf.WriteString(utf8BOM)
+
+	w := csv.NewWriter(&buf)
+
+	rows := [][]string{{"name", "discord_id", "currency", "items", "total", "threshold", "reminded"}}
+	for _, b := range debts {
+		rows = append(rows, []string{
+			b.User.Name,
+			b.User.DiscordID,
+			string(b.User.Currency),
+			strconv.Itoa(len(b.Items)),
+			strconv.FormatFloat(b.Total, 'f', 0, 64),
+			strconv.FormatFloat(b.Threshold, 'f', 0, 64),
+			strconv.FormatBool(b.OverThreshold()),
+		})
+	}
+
+	err := w.WriteAll(rows)
+	if err != nil {
+		return nil, fmt.Errorf("write debts csv: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,98 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/require"
+
+	"github.com/xgnid-tw/gx5/domain"
+)
+
+func testDebts(n int) []*domain.Balance {
+	debts := make([]*domain.Balance, 0, n)
+	for k := range n {
+		debts = append(debts, &domain.Balance{
+			UnpaidLedger: domain.UnpaidLedger{
+				User:  domain.User{DiscordID: fmt.Sprint(k), Name: fmt.Sprintf("m%02d", k), Currency: domain.CurrencyTWD},
+				Items: make([]domain.UnpaidItem, 2),
+				Total: float64(3000 - k*100),
+			},
+			Threshold: 2000,
+		})
+	}
+
+	return debts
+}
+
+func TestDebtsEmbed_Pages(t *testing.T) {
+	debts := &domain.Debts{Balances: testDebts(debtsPageSize + 2)}
+
+	first := debtsEmbed(debts, 0)
+	lines := strings.Split(first.Description, "\n")
+
+	require.Len(t, lines, debtsPageSize)
+	require.Equal(t, "` 1` **m00** NT$3000（2 筆） 🔔", lines[0])
+	require.Equal(t, "第 1 / 2 頁・🔔 下次催款時會收到提醒", first.Footer.Text)
+
+	last := debtsEmbed(debts, 1)
+	lines = strings.Split(last.Description, "\n")
+
+	require.Len(t, lines, 2)
+	require.True(t, strings.HasPrefix(lines[0], fmt.Sprintf("`%d` **m15**", debtsPageSize+1)))
+	require.Equal(t, "NT$37400", last.Fields[1].Value, "totals cover every page")
+	require.Len(t, last.Fields, 3)
+}
+
+func TestDebtsEmbed_Failed(t *testing.T) {
+	debts := &domain.Debts{
+		Balances: testDebts(1),
+		Failed: []domain.DebtFailure{
+			{User: domain.User{Name: "Alice"}, Reason: "get unpaid items for Alice: not found"},
+			{User: domain.User{Name: "Bob"}, Reason: "get unpaid items for Bob: not found"},
+		},
+	}
+
+	embed := debtsEmbed(debts, 0)
+
+	require.Equal(t, "1 人", embed.Fields[0].Value)
+	require.Len(t, embed.Fields, 4)
+	require.Equal(t, "⚠️ 無法讀取 2 人（未計入合計）", embed.Fields[3].Name)
+	require.Equal(t, "Alice、Bob", embed.Fields[3].Value)
+
+	debts.Balances = nil
+	embed = debtsEmbed(debts, 0)
+
+	require.Equal(t, "目前沒有成員有未付款項目", embed.Description)
+	require.Equal(t, "第 1 / 1 頁・🔔 下次催款時會收到提醒", embed.Footer.Text)
+}
+
+func TestDebtsComponents(t *testing.T) {
+	require.Empty(t, debtsComponents(0, 1))
+
+	buttons := debtsComponents(0, 3)[0].(discordgo.ActionsRow).Components
+	prev, next := buttons[0].(discordgo.Button), buttons[1].(discordgo.Button)
+
+	require.True(t, prev.Disabled)
+	require.False(t, next.Disabled)
+	require.Equal(t, "debts_page:1", next.CustomID)
+
+	buttons = debtsComponents(2, 3)[0].(discordgo.ActionsRow).Components
+	require.Equal(t, "debts_page:1", buttons[0].(discordgo.Button).CustomID)
+	require.True(t, buttons[1].(discordgo.Button).Disabled)
+}
+
+func TestDebtsCSV(t *testing.T) {
+	debts := testDebts(2)
+	debts[1].User.Name = "王, 小明"
+
+	table, err := debtsCSV(debts)
+
+	require.NoError(t, err)
+	require.Equal(t, utf8BOM+
+		"name,discord_id,currency,items,total,threshold,reminded\n"+
+		"m00,0,TWD,2,3000,2000,true\n"+
+		"\"王, 小明\",1,TWD,2,2900,2000,true\n", string(table))
+}
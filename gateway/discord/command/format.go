@@ -0,0 +1,29 @@
+package command
+
+import (
+	"fmt"
+
+	"github.com/xgnid-tw/gx5/domain"
+)
+
+func currencySymbol(c domain.Currency) string {
+	if c == domain.CurrencyJPY {
+		return "¥"
+	}
+
+	return "NT$"
+}
+
+func formatAmount(c domain.Currency, amount float64) string {
+	return fmt.Sprintf("%s%.0f", currencySymbol(c), amount)
+}
+
+// truncate shortens s to at most n runes, as required by Discord labels.
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+
+	return string(r[:n-1]) + "…"
+}
@@ -0,0 +1,184 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/xgnid-tw/gx5/domain"
+	"github.com/xgnid-tw/gx5/port"
+)
+
+const (
+	itemStatusCommandName  = "item-status"
+	itemStatusOptionStatus = "status"
+	itemStatusPrefix       = "item_status"
+)
+
+// RegisterItemStatusCommand registers the /item-status slash command, which advances
+// every item linked to the thread's order, and the per-item button on /buy results.
+func RegisterItemStatusCommand(ch *Handler, uc port.ItemStatusTracker) {
+	adminPerm := int64(discordgo.PermissionAdministrator)
+
+	cmd := &discordgo.ApplicationCommand{
+		Name:                     itemStatusCommandName,
+		Description:              "將此討論串訂單的所有物品更新為指定的物品狀況",
+		DefaultMemberPermissions: &adminPerm,
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        itemStatusOptionStatus,
+				Description: "物品狀況",
+				Required:    true,
+				Choices:     itemStatusChoices(),
+			},
+		},
+	}
+
+	ch.RegisterCommand(cmd, func(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+		handleItemStatus(ctx, s, i, uc)
+	})
+
+	ch.RegisterComponentHandler(
+		itemStatusPrefix,
+		func(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+			handleItemStatusButton(ctx, s, i, uc)
+		},
+		RequirePermission(discordgo.PermissionAdministrator, "只有管理員可以更新物品狀況"),
+	)
+}
+
+func handleItemStatus(
+	ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, uc port.ItemStatusTracker,
+) {
+	channel, err := s.Channel(i.ChannelID)
+	if err != nil {
+		respondError(ctx, s, i, "無法取得頻道資訊")
+		return
+	}
+
+	if !channel.IsThread() {
+		respondError(ctx, s, i, "此指令只能在討論串中使用")
+		return
+	}
+
+	respondDeferred(ctx, s, i)
+
+	to := domain.ItemStatus(i.ApplicationCommandData().Options[0].StringValue())
+
+	report, err := uc.AdvanceOrder(ctx, channel.ID, to)
+	if report == nil {
+		logf(ctx, "advance item status failed: %s", err)
+
+		msg := failureMessage(err, "更新物品狀況失敗")
+		if errors.Is(err, port.ErrOrderNotFound) {
+			msg = "此討論串沒有對應的訂單"
+		}
+
+		editDeferredResponse(ctx, s, i, msg)
+
+		return
+	}
+
+	if err == nil && len(report.Advanced) == 0 && report.Skipped == 0 {
+		editDeferredResponse(ctx, s, i, "此訂單中沒有登記的物品")
+		return
+	}
+
+	msg := fmt.Sprintf(
+		"已將 %d 筆物品更新為「%s」，略過 %d 筆",
+		len(report.Advanced), to, report.Skipped,
+	)
+
+	if report.Notified > 0 {
+		msg += fmt.Sprintf("，已通知 %d 位成員取貨", report.Notified)
+	}
+
+	if err != nil {
+		logf(ctx, "advance item status partially failed: %s", err)
+		msg += "\n" + failureMessage(err, "部分物品更新失敗，請查看 log")
+	}
+
+	editDeferredResponse(ctx, s, i, msg)
+}
+
+func handleItemStatusButton(
+	ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, uc port.ItemStatusTracker,
+) {
+	// Format: item_status:<pageID>
+	pageID, ok := customIDArg(i.MessageComponentData().CustomID)
+	if !ok {
+		respondError(ctx, s, i, "無效的操作")
+		return
+	}
+
+	respondDeferredUpdate(ctx, s, i)
+
+	change, err := uc.Advance(ctx, pageID, "")
+	if err != nil {
+		logf(ctx, "advance item status failed: %s", err)
+
+		msg := failureMessage(err, "更新物品狀況失敗")
+		if errors.Is(err, port.ErrItemNotAdvanceable) {
+			msg = "此物品無法再更新物品狀況"
+		}
+
+		followupError(ctx, s, i, msg)
+
+		return
+	}
+
+	components := buyRecordComponents(change.Item.PageID, change.To)
+
+	_, err = s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Components: &components,
+	})
+	if err != nil {
+		logf(ctx, "error editing item status button: %s", err)
+	}
+}
+
+// itemStatusComponents returns the button that advances the item to its next
+// status, or no components when the item cannot advance any further.
+func itemStatusComponents(pageID string, current domain.ItemStatus) []discordgo.MessageComponent {
+	next, ok := current.Next()
+	if !ok || pageID == "" {
+		return []discordgo.MessageComponent{}
+	}
+
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{
+					Label:    fmt.Sprintf("物品狀況：%s → %s", displayItemStatus(current), next),
+					Style:    discordgo.SecondaryButton,
+					CustomID: fmt.Sprintf("%s:%s", itemStatusPrefix, pageID),
+				},
+			},
+		},
+	}
+}
+
+func displayItemStatus(s domain.ItemStatus) domain.ItemStatus {
+	if s == "" {
+		return domain.ItemStatusNotOrdered
+	}
+
+	return s
+}
+
+func itemStatusChoices() []*discordgo.ApplicationCommandOptionChoice {
+	progress := domain.ItemProgress()[1:]
+	choices := make([]*discordgo.ApplicationCommandOptionChoice, len(progress))
+
+	for i, st := range progress {
+		choices[i] = &discordgo.ApplicationCommandOptionChoice{
+			Name:  string(st),
+			Value: string(st),
+		}
+	}
+
+	return choices
+}
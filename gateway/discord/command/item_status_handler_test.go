@@ -0,0 +1,24 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/require"
+
+	"github.com/xgnid-tw/gx5/domain"
+)
+
+func TestItemStatusComponents(t *testing.T) {
+	components := itemStatusComponents("p1", domain.ItemStatusOrdered)
+
+	require.Len(t, components, 1)
+
+	btn, ok := components[0].(discordgo.ActionsRow).Components[0].(discordgo.Button)
+	require.True(t, ok)
+	require.Equal(t, "item_status:p1", btn.CustomID)
+	require.Equal(t, "物品狀況：已下訂 → 已到貨", btn.Label)
+
+	require.Empty(t, itemStatusComponents("p1", domain.ItemStatusArrivedTW))
+	require.Empty(t, itemStatusComponents("p1", domain.ItemStatusShipping))
+}
@@ -0,0 +1,65 @@
+package command
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/xgnid-tw/gx5/port"
+)
+
+const (
+	membersCommandName       = "members"
+	membersSubcommandRefresh = "refresh"
+)
+
+// RegisterMembersCommand registers the /members admin command for maintaining the member cache.
+func RegisterMembersCommand(ch *Handler, dir port.MemberDirectory) {
+	adminPerm := int64(discordgo.PermissionAdministrator)
+
+	cmd := &discordgo.ApplicationCommand{
+		Name:                     membersCommandName,
+		Description:              "成員名單管理",
+		DefaultMemberPermissions: &adminPerm,
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        membersSubcommandRefresh,
+				Description: "清除快取並重新從 Notion 載入成員名單",
+			},
+		},
+	}
+
+	ch.RegisterCommand(cmd, func(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+		handleMembers(ctx, s, i, dir)
+	})
+}
+
+func handleMembers(
+	ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, dir port.MemberDirectory,
+) {
+	opts := i.ApplicationCommandData().Options
+	if len(opts) == 0 || opts[0].Name != membersSubcommandRefresh {
+		respondError(ctx, s, i, "未知的子指令")
+		return
+	}
+
+	respondDeferred(ctx, s, i)
+
+	count, err := dir.Refresh(ctx)
+	if err != nil {
+		logf(ctx, "members refresh failed: %s", err)
+		editDeferredResponse(ctx, s, i, failureMessage(err, "成員名單重新載入失敗"))
+
+		return
+	}
+
+	stats := dir.Stats()
+	logf(ctx, "member cache refreshed: %d members (hits %d, misses %d)", count, stats.Hits, stats.Misses)
+
+	editDeferredResponse(ctx, s, i, fmt.Sprintf(
+		"已重新載入 %d 位成員（快取命中 %d 次 / 未命中 %d 次）",
+		count, stats.Hits, stats.Misses,
+	))
+}
@@ -0,0 +1,223 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// DefaultInteractionTimeout bounds the work done for one interaction, including
+	// Notion calls made after the response was deferred.
+	DefaultInteractionTimeout = 2 * time.Minute
+
+	// DefaultAutoDeferAfter leaves a margin before Discord's 3 second response deadline.
+	DefaultAutoDeferAfter = 2 * time.Second
+)
+
+// HandlerFunc handles one interaction. ctx carries the interaction's deadline and
+// correlation ID and is cancelled once the handler returns.
+type HandlerFunc func(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate)
+
+// Middleware wraps a HandlerFunc with behaviour shared by several handlers.
+type Middleware func(next HandlerFunc) HandlerFunc
+
+// chain wraps h so that the first middleware is the outermost.
+func chain(h HandlerFunc, mws []Middleware) HandlerFunc {
+	for _, mw := range slices.Backward(mws) {
+		h = mw(h)
+	}
+
+	return h
+}
+
+type ctxKey int
+
+const (
+	correlationIDKey ctxKey = iota
+	interactionStateKey
+)
+
+// interactionState records how the interaction was first answered, so that the
+// handler and AutoDefer never both send the initial response.
+type interactionState struct {
+	mu           sync.Mutex
+	answered     bool
+	autoDeferred bool
+	ephemeral    bool // flag of the auto-deferred response
+}
+
+func stateFrom(ctx context.Context) *interactionState {
+	st, _ := ctx.Value(interactionStateKey).(*interactionState)
+
+	return st
+}
+
+// CorrelationID returns the ID tagging log lines of the interaction being handled,
+// or "" outside InteractionContext.
+func CorrelationID(ctx context.Context) string {
+	id, _ := ctx.Value(correlationIDKey).(string)
+
+	return id
+}
+
+// logf logs with the correlation ID of the interaction, if any.
+func logf(ctx context.Context, format string, args ...any) {
+	if id := CorrelationID(ctx); id != "" {
+		format = "[" + id + "] " + format
+	}
+
+	log.Printf(format, args...)
+}
+
+// InteractionContext gives each interaction a context bounded by timeout and
+// tagged with the interaction ID as correlation ID. It must be the outermost middleware.
+func InteractionContext(timeout time.Duration) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			ctx = context.WithValue(ctx, correlationIDKey, i.ID)
+			ctx = context.WithValue(ctx, interactionStateKey, &interactionState{})
+
+			next(ctx, s, i)
+		}
+	}
+}
+
+// Recover turns a panic in a handler into a logged error and an ephemeral reply,
+// instead of letting it take down the bot.
+func Recover() Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+			defer func() {
+				r := recover()
+				if r == nil {
+					return
+				}
+
+				logf(ctx, "panic handling %s: %v\n%s", interactionName(i), r, debug.Stack())
+				respondError(ctx, s, i, "發生未預期的錯誤，請稍後再試")
+			}()
+
+			next(ctx, s, i)
+		}
+	}
+}
+
+// LogLatency logs how long each interaction took to handle.
+func LogLatency() Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+			start := time.Now()
+
+			next(ctx, s, i)
+
+			logf(ctx, "%s by %s handled in %s", interactionName(i), interactionUserID(i), time.Since(start))
+		}
+	}
+}
+
+// AutoDefer defers the response when the handler has not answered within after.
+// Replies sent later by the respond helpers are then delivered as edits or
+// followups. Handlers that open a modal must not use it.
+func AutoDefer(after time.Duration, ephemeral bool) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+			st := stateFrom(ctx)
+			if st == nil {
+				next(ctx, s, i)
+				return
+			}
+
+			timer := time.AfterFunc(after, func() {
+				st.mu.Lock()
+				defer st.mu.Unlock()
+
+				if st.answered {
+					return
+				}
+
+				err := s.InteractionRespond(i.Interaction, deferredResponse(i, ephemeral))
+				if err != nil {
+					logf(ctx, "error auto-deferring interaction response: %s", err)
+					return
+				}
+
+				st.answered, st.autoDeferred, st.ephemeral = true, true, ephemeral
+			})
+			defer timer.Stop()
+
+			next(ctx, s, i)
+		}
+	}
+}
+
+// deferredResponse acknowledges a component by updating its message later, and
+// anything else with a message that is sent later.
+func deferredResponse(i *discordgo.InteractionCreate, ephemeral bool) *discordgo.InteractionResponse {
+	if i.Type == discordgo.InteractionMessageComponent {
+		return &discordgo.InteractionResponse{Type: discordgo.InteractionResponseDeferredMessageUpdate}
+	}
+
+	resp := &discordgo.InteractionResponse{Type: discordgo.InteractionResponseDeferredChannelMessageWithSource}
+	if ephemeral {
+		resp.Data = &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral}
+	}
+
+	return resp
+}
+
+// RequirePermission only lets guild members holding perm through; others get msg.
+func RequirePermission(perm int64, msg string) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+			if i.Member == nil || i.Member.Permissions&perm == 0 {
+				respondError(ctx, s, i, msg)
+				return
+			}
+
+			next(ctx, s, i)
+		}
+	}
+}
+
+// RequireRole only lets guild members with one of roleIDs through; others get msg.
+func RequireRole(msg string, roleIDs ...string) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+			if i.Member == nil || !slices.ContainsFunc(i.Member.Roles, func(r string) bool {
+				return slices.Contains(roleIDs, r)
+			}) {
+				respondError(ctx, s, i, msg)
+				return
+			}
+
+			next(ctx, s, i)
+		}
+	}
+}
+
+// interactionName describes the interaction for logs, e.g. "command /order" or "component item_status".
+func interactionName(i *discordgo.InteractionCreate) string {
+	switch i.Type {
+	case discordgo.InteractionApplicationCommand:
+		return "command /" + i.ApplicationCommandData().Name
+	case discordgo.InteractionApplicationCommandAutocomplete:
+		return "autocomplete /" + i.ApplicationCommandData().Name
+	case discordgo.InteractionModalSubmit:
+		return "modal " + customIDPrefix(i.ModalSubmitData().CustomID)
+	case discordgo.InteractionMessageComponent:
+		return "component " + customIDPrefix(i.MessageComponentData().CustomID)
+	case discordgo.InteractionPing:
+	}
+
+	return fmt.Sprintf("interaction type %d", i.Type)
+}
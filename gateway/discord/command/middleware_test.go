@@ -0,0 +1,233 @@
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/require"
+)
+
+// apiCall is a Discord REST request captured by recordingTransport.
+type apiCall struct {
+	method string
+	path   string
+	body   map[string]any
+}
+
+// recordingTransport answers every Discord API request with an empty object, or
+// with what reply returns for it, and records it, so respond helpers can be
+// exercised without a connection.
+type recordingTransport struct {
+	mu    sync.Mutex
+	calls []apiCall
+	reply func(req *http.Request) string
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	call := apiCall{method: req.Method, path: req.URL.Path}
+
+	if req.Body != nil {
+		data, _ := io.ReadAll(req.Body)
+		_ = json.Unmarshal(data, &call.body)
+	}
+
+	t.mu.Lock()
+	t.calls = append(t.calls, call)
+	t.mu.Unlock()
+
+	body := "{}"
+	if t.reply != nil {
+		body = t.reply(req)
+	}
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+func (t *recordingTransport) recorded() []apiCall {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return append([]apiCall(nil), t.calls...)
+}
+
+func recordingSession(t *testing.T) (*discordgo.Session, *recordingTransport) {
+	t.Helper()
+
+	s, err := discordgo.New("Bot test")
+	require.NoError(t, err)
+
+	transport := &recordingTransport{}
+	s.Client = &http.Client{Transport: transport}
+
+	return s, transport
+}
+
+func commandInteraction() *discordgo.InteractionCreate {
+	return &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+		ID: "int-1", AppID: "app", Token: "tok",
+		Type:   discordgo.InteractionApplicationCommand,
+		Data:   discordgo.ApplicationCommandInteractionData{Name: "order"},
+		Member: &discordgo.Member{User: &discordgo.User{ID: "111"}},
+	}}
+}
+
+// callbackType returns the response type of an interaction callback request.
+func callbackType(c apiCall) discordgo.InteractionResponseType {
+	typ, _ := c.body["type"].(float64)
+
+	return discordgo.InteractionResponseType(typ)
+}
+
+func TestChain_Order(t *testing.T) {
+	var trace []string
+
+	mw := func(name string) Middleware {
+		return func(next HandlerFunc) HandlerFunc {
+			return func(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+				trace = append(trace, name)
+				next(ctx, s, i)
+			}
+		}
+	}
+
+	h := chain(func(context.Context, *discordgo.Session, *discordgo.InteractionCreate) {
+		trace = append(trace, "handler")
+	}, []Middleware{mw("outer"), mw("inner")})
+
+	h(context.Background(), nil, commandInteraction())
+
+	require.Equal(t, []string{"outer", "inner", "handler"}, trace)
+}
+
+func TestInteractionContext(t *testing.T) {
+	var (
+		id          string
+		hasDeadline bool
+		ctx         context.Context
+	)
+
+	h := InteractionContext(time.Minute)(func(c context.Context, _ *discordgo.Session, _ *discordgo.InteractionCreate) {
+		ctx = c
+		id = CorrelationID(c)
+		_, hasDeadline = c.Deadline()
+	})
+
+	h(context.Background(), nil, commandInteraction())
+
+	require.Equal(t, "int-1", id)
+	require.True(t, hasDeadline)
+	require.ErrorIs(t, ctx.Err(), context.Canceled, "context is released once the handler returns")
+}
+
+func TestRecover(t *testing.T) {
+	s, transport := recordingSession(t)
+
+	h := chain(func(context.Context, *discordgo.Session, *discordgo.InteractionCreate) {
+		panic("boom")
+	}, []Middleware{InteractionContext(time.Minute), Recover()})
+
+	require.NotPanics(t, func() { h(context.Background(), s, commandInteraction()) })
+
+	calls := transport.recorded()
+	require.Len(t, calls, 1)
+	require.Equal(t, discordgo.InteractionResponseChannelMessageWithSource, callbackType(calls[0]))
+}
+
+func TestRecover_AfterDeferral(t *testing.T) {
+	s, transport := recordingSession(t)
+
+	h := chain(func(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+		respondDeferred(ctx, s, i)
+		panic("boom")
+	}, []Middleware{InteractionContext(time.Minute), Recover()})
+
+	h(context.Background(), s, commandInteraction())
+
+	calls := transport.recorded()
+	require.Len(t, calls, 2)
+	require.Equal(t, discordgo.InteractionResponseDeferredChannelMessageWithSource, callbackType(calls[0]))
+	require.Equal(t, http.MethodPost, calls[1].method)
+	require.Contains(t, calls[1].path, "/webhooks/app/tok", "the error is sent as a followup")
+}
+
+func TestAutoDefer_SlowHandler(t *testing.T) {
+	s, transport := recordingSession(t)
+
+	h := chain(func(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+		time.Sleep(50 * time.Millisecond)
+		respondSuccess(ctx, s, i, "登記完畢", nil)
+	}, []Middleware{InteractionContext(time.Minute), AutoDefer(time.Millisecond, false)})
+
+	h(context.Background(), s, commandInteraction())
+
+	calls := transport.recorded()
+	require.Len(t, calls, 2)
+	require.Equal(t, discordgo.InteractionResponseDeferredChannelMessageWithSource, callbackType(calls[0]))
+	require.Equal(t, http.MethodPatch, calls[1].method)
+	require.Contains(t, calls[1].path, "/messages/@original")
+	require.Equal(t, "登記完畢", calls[1].body["content"])
+}
+
+func TestAutoDefer_FastHandler(t *testing.T) {
+	s, transport := recordingSession(t)
+
+	h := chain(func(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+		respondSuccess(ctx, s, i, "登記完畢", nil)
+	}, []Middleware{InteractionContext(time.Minute), AutoDefer(time.Second, false)})
+
+	h(context.Background(), s, commandInteraction())
+
+	calls := transport.recorded()
+	require.Len(t, calls, 1)
+	require.Equal(t, discordgo.InteractionResponseChannelMessageWithSource, callbackType(calls[0]))
+}
+
+func TestRequirePermission(t *testing.T) {
+	s, transport := recordingSession(t)
+
+	called := false
+	h := RequirePermission(discordgo.PermissionAdministrator, "只有管理員可以使用")(
+		func(context.Context, *discordgo.Session, *discordgo.InteractionCreate) { called = true },
+	)
+
+	i := commandInteraction()
+	h(context.Background(), s, i)
+
+	require.False(t, called)
+	require.Len(t, transport.recorded(), 1)
+
+	i.Member.Permissions = discordgo.PermissionAdministrator
+	h(context.Background(), s, i)
+
+	require.True(t, called)
+}
+
+func TestRequireRole(t *testing.T) {
+	s, _ := recordingSession(t)
+
+	called := false
+	h := RequireRole("需要團主身分", "role-1")(
+		func(context.Context, *discordgo.Session, *discordgo.InteractionCreate) { called = true },
+	)
+
+	i := commandInteraction()
+	i.Member.Roles = []string{"role-2"}
+	h(context.Background(), s, i)
+	require.False(t, called)
+
+	i.Member.Roles = []string{"role-2", "role-1"}
+	h(context.Background(), s, i)
+	require.True(t, called)
+}
@@ -2,7 +2,6 @@ package command
 
 import (
 	"context"
-	"log"
 
 	"github.com/bwmarrin/discordgo"
 
@@ -51,15 +50,15 @@ func RegisterNewOrderCommand(ch *Handler, uc port.OrderCreator) {
 		},
 	}
 
-	ch.RegisterCommand(cmd, func(s *discordgo.Session, i *discordgo.InteractionCreate) {
-		handleNewOrder(s, i, uc)
+	ch.RegisterCommand(cmd, func(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+		handleNewOrder(ctx, s, i, uc)
 	})
 }
 
 func handleNewOrder(
-	s *discordgo.Session, i *discordgo.InteractionCreate, uc port.OrderCreator,
+	ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, uc port.OrderCreator,
 ) {
-	respondDeferred(s, i)
+	respondDeferred(ctx, s, i)
 
 	opts := i.ApplicationCommandData().Options
 	optMap := make(map[string]*discordgo.ApplicationCommandInteractionDataOption, len(opts))
@@ -68,7 +67,7 @@ func handleNewOrder(
 		optMap[opt.Name] = opt
 	}
 
-	order := domain.Order{}
+	order := domain.Order{CreatedBy: interactionUserID(i)}
 
 	if v, ok := optMap["ordertitle"]; ok {
 		order.ThreadName = v.StringValue()
@@ -86,15 +85,15 @@ func handleNewOrder(
 		order.Tag = domain.Tag(v.StringValue())
 	}
 
-	err := uc.Execute(context.Background(), i.ChannelID, order)
+	err := uc.Execute(ctx, i.ChannelID, order)
 	if err != nil {
-		log.Printf("create order failed: %s", err)
-		editDeferredResponse(s, i, "建立訂單失敗")
+		logf(ctx, "create order failed: %s", err)
+		editDeferredResponse(ctx, s, i, failureMessage(err, "建立訂單失敗"))
 
 		return
 	}
 
-	editDeferredResponse(s, i, "訂單已建立: "+order.ThreadName)
+	editDeferredResponse(ctx, s, i, "訂單已建立: "+order.ThreadName)
 }
 
 func tagChoices() []*discordgo.ApplicationCommandOptionChoice {
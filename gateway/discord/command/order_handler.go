@@ -0,0 +1,213 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/xgnid-tw/gx5/domain"
+	"github.com/xgnid-tw/gx5/port"
+)
+
+const (
+	orderCommandName       = "order"
+	orderSubcommandStatus  = "status"
+	orderSubcommandSummary = "summary"
+	orderStatusOptionValue = "to"
+)
+
+// RegisterOrderCommand registers the /order slash command. Its status subcommand
+// moves the order of the current thread to the next lifecycle status, and its
+// summary subcommand totals the records linked to the order.
+func RegisterOrderCommand(ch *Handler, statusUC port.OrderStatusUpdater, summaryUC port.OrderSummarizer) {
+	adminPerm := int64(discordgo.PermissionAdministrator)
+
+	cmd := &discordgo.ApplicationCommand{
+		Name:                     orderCommandName,
+		Description:              "管理此討論串的團購訂單",
+		DefaultMemberPermissions: &adminPerm,
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        orderSubcommandStatus,
+				Description: "變更訂單狀態",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        orderStatusOptionValue,
+						Description: "新的訂單狀態",
+						Required:    true,
+						Choices:     orderStatusChoices(),
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        orderSubcommandSummary,
+				Description: "統計此訂單的參加人數與金額",
+			},
+		},
+	}
+
+	ch.RegisterCommand(cmd, func(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+		sub := i.ApplicationCommandData().Options
+		if len(sub) == 0 {
+			respondError(ctx, s, i, "無效的指令")
+			return
+		}
+
+		switch {
+		case sub[0].Name == orderSubcommandStatus && len(sub[0].Options) > 0:
+			handleOrderStatus(ctx, s, i, statusUC, domain.OrderStatus(sub[0].Options[0].StringValue()))
+		case sub[0].Name == orderSubcommandSummary:
+			handleOrderSummary(ctx, s, i, summaryUC)
+		default:
+			respondError(ctx, s, i, "無效的指令")
+		}
+	})
+}
+
+// orderThreadID returns the ID of the thread the interaction came from, or
+// responds with an error and returns false outside a thread.
+func orderThreadID(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) (string, bool) {
+	channel, err := s.Channel(i.ChannelID)
+	if err != nil {
+		respondError(ctx, s, i, "無法取得頻道資訊")
+		return "", false
+	}
+
+	if !channel.IsThread() {
+		respondError(ctx, s, i, "此指令只能在訂單討論串中使用")
+		return "", false
+	}
+
+	return channel.ID, true
+}
+
+func handleOrderStatus(
+	ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate,
+	uc port.OrderStatusUpdater, to domain.OrderStatus,
+) {
+	threadID, ok := orderThreadID(ctx, s, i)
+	if !ok {
+		return
+	}
+
+	respondDeferred(ctx, s, i)
+
+	change, err := uc.Execute(ctx, threadID, to)
+	if change == nil {
+		logf(ctx, "update order status failed: %s", err)
+		editDeferredResponse(ctx, s, i, orderStatusFailure(err, to))
+
+		return
+	}
+
+	msg := fmt.Sprintf("訂單狀態：%s → %s", change.From, change.To)
+
+	if err != nil {
+		logf(ctx, "update order status partially failed: %s", err)
+		msg += "\n" + failureMessage(err, "無法重新命名或鎖定討論串，請手動處理")
+	}
+
+	editDeferredResponse(ctx, s, i, msg)
+}
+
+func handleOrderSummary(
+	ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, uc port.OrderSummarizer,
+) {
+	threadID, ok := orderThreadID(ctx, s, i)
+	if !ok {
+		return
+	}
+
+	respondDeferred(ctx, s, i)
+
+	rollup, err := uc.Execute(ctx, threadID)
+	if err != nil {
+		logf(ctx, "summarize order failed: %s", err)
+
+		msg := failureMessage(err, "無法統計訂單")
+		if errors.Is(err, port.ErrOrderNotFound) {
+			msg = "此討論串沒有對應的訂單"
+		}
+
+		editDeferredResponse(ctx, s, i, msg)
+
+		return
+	}
+
+	editDeferredResponse(ctx, s, i, formatOrderRollup(rollup))
+}
+
+func formatOrderRollup(r *domain.OrderRollup) string {
+	lines := []string{
+		fmt.Sprintf("**%s**（%s）", r.Order.ThreadName, displayOrderStatus(r.Order.Status)),
+		fmt.Sprintf("參加人數：%d 人", len(r.Participants)),
+	}
+
+	if len(r.Participants) > 0 {
+		lines = append(lines, strings.Join(r.Participants, "、"))
+	}
+
+	lines = append(lines,
+		fmt.Sprintf("品項：%d 筆", r.Items),
+		"應付店家："+formatAmount(domain.CurrencyJPY, r.TotalJPY),
+		"應收台幣："+formatAmount(domain.CurrencyTWD, r.TotalTWD),
+	)
+
+	if len(r.Unreadable) > 0 {
+		lines = append(lines, "未計入（無法讀取）："+strings.Join(r.Unreadable, "、"))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func displayOrderStatus(s domain.OrderStatus) domain.OrderStatus {
+	if s == "" {
+		return domain.OrderStatusOpen
+	}
+
+	return s
+}
+
+func orderStatusFailure(err error, to domain.OrderStatus) string {
+	switch {
+	case errors.Is(err, port.ErrOrderNotFound):
+		return "此討論串沒有對應的訂單"
+	case errors.Is(err, port.ErrInvalidOrderTransition):
+		return fmt.Sprintf("無法變更為「%s」，訂單狀態須依序變更：%s", to, orderLifecycleText())
+	default:
+		return failureMessage(err, "變更訂單狀態失敗")
+	}
+}
+
+func orderLifecycleText() string {
+	lifecycle := domain.OrderLifecycle()
+	names := make([]string, len(lifecycle))
+
+	for i, st := range lifecycle {
+		names[i] = string(st)
+	}
+
+	return strings.Join(names, " → ")
+}
+
+// orderStatusChoices lists every status an order can be moved to; 開放中 is
+// only ever set when the order is created.
+func orderStatusChoices() []*discordgo.ApplicationCommandOptionChoice {
+	lifecycle := domain.OrderLifecycle()[1:]
+	choices := make([]*discordgo.ApplicationCommandOptionChoice, len(lifecycle))
+
+	for i, st := range lifecycle {
+		choices[i] = &discordgo.ApplicationCommandOptionChoice{
+			Name:  string(st),
+			Value: string(st),
+		}
+	}
+
+	return choices
+}
@@ -0,0 +1,53 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/xgnid-tw/gx5/domain"
+	"github.com/xgnid-tw/gx5/port"
+)
+
+func TestOrderStatusChoices(t *testing.T) {
+	choices := orderStatusChoices()
+
+	require.Len(t, choices, 5)
+	require.Equal(t, "已截止", choices[0].Value)
+	require.Equal(t, "已結清", choices[4].Value)
+}
+
+func TestOrderStatusFailure(t *testing.T) {
+	require.Equal(t, "此討論串沒有對應的訂單",
+		orderStatusFailure(fmt.Errorf("get order: %w", port.ErrOrderNotFound), domain.OrderStatusClosed))
+	require.Equal(t,
+		"無法變更為「已下訂」，訂單狀態須依序變更：開放中 → 已截止 → 已下訂 → 已出貨 → 已到貨 → 已結清",
+		orderStatusFailure(port.ErrInvalidOrderTransition, domain.OrderStatusOrdered))
+	require.Equal(t, "變更訂單狀態失敗",
+		orderStatusFailure(errors.New("boom"), domain.OrderStatusOrdered))
+}
+
+func TestFormatOrderRollup(t *testing.T) {
+	msg := formatOrderRollup(&domain.OrderRollup{
+		Order:        domain.Order{ThreadName: "test order"},
+		Participants: []string{"Alice", "Bob"},
+		Items:        3,
+		TotalJPY:     6000,
+		TotalTWD:     1440,
+	})
+
+	require.Equal(t,
+		"**test order**（開放中）\n參加人數：2 人\nAlice、Bob\n品項：3 筆\n應付店家：¥6000\n應收台幣：NT$1440",
+		msg)
+}
+
+func TestFormatOrderRollup_Unreadable(t *testing.T) {
+	msg := formatOrderRollup(&domain.OrderRollup{
+		Order:      domain.Order{ThreadName: "test order"},
+		Unreadable: []string{"Bob"},
+	})
+
+	require.Contains(t, msg, "\n未計入（無法讀取）：Bob")
+}
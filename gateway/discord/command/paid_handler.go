@@ -0,0 +1,208 @@
+package command
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/xgnid-tw/gx5/domain"
+	"github.com/xgnid-tw/gx5/port"
+)
+
+const (
+	paidCommandName   = "paid"
+	paidOptionMember  = "member"
+	paidSelectPrefix  = "paid_select"
+	paidAllPrefix     = "paid_all"
+	maxSelectOptions  = 25  // Discord limit per select menu
+	maxSelectLabelLen = 100 // Discord limit per select option label
+)
+
+// RegisterPaidCommand registers the /paid slash command and its select menu and button handlers.
+func RegisterPaidCommand(ch *Handler, uc port.PaymentSettler) {
+	adminPerm := int64(discordgo.PermissionAdministrator)
+
+	cmd := &discordgo.ApplicationCommand{
+		Name:                     paidCommandName,
+		Description:              "將成員的未付款項目標記為已付款",
+		DefaultMemberPermissions: &adminPerm,
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionUser,
+				Name:        paidOptionMember,
+				Description: "付款的成員",
+				Required:    true,
+			},
+		},
+	}
+
+	ch.RegisterCommand(cmd, func(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+		handlePaid(ctx, s, i, uc)
+	})
+
+	ch.RegisterComponentHandler(paidSelectPrefix, func(
+		ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate,
+	) {
+		handlePaidComponent(ctx, s, i, uc, i.MessageComponentData().Values)
+	})
+
+	// 全部結清 settles the items listed in the menu when /paid replied, not whatever
+	// is unpaid when it is pressed.
+	ch.RegisterComponentHandler(paidAllPrefix, func(
+		ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate,
+	) {
+		handlePaidComponent(ctx, s, i, uc, listedPageIDs(i.Message.Components))
+	})
+}
+
+func handlePaid(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, uc port.PaymentSettler) {
+	respondDeferredEphemeral(ctx, s, i)
+
+	opts := i.ApplicationCommandData().Options
+	if len(opts) == 0 {
+		editDeferredResponse(ctx, s, i, "請指定成員")
+		return
+	}
+
+	member := opts[0].UserValue(nil)
+
+	ledger, err := uc.ListUnpaid(ctx, member.ID)
+	if err != nil {
+		logf(ctx, "list unpaid items failed: %s", err)
+		editDeferredResponse(ctx, s, i, failureMessage(err, "無法取得未付款項目"))
+
+		return
+	}
+
+	if len(ledger.Items) == 0 {
+		editDeferredResponse(ctx, s, i, fmt.Sprintf("<@%s> 沒有未付款項目", member.ID))
+		return
+	}
+
+	content := formatUnpaidSummary(ledger)
+	components := paidComponents(&ledger.User, ledger.Items)
+
+	_, err = s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Content:    &content,
+		Components: &components,
+	})
+	if err != nil {
+		logf(ctx, "error editing deferred response: %s", err)
+	}
+}
+
+func handlePaidComponent(
+	ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, uc port.PaymentSettler, pageIDs []string,
+) {
+	// Format: paid_select:<discordID> / paid_all:<discordID>
+	discordID, ok := customIDArg(i.MessageComponentData().CustomID)
+	if !ok {
+		respondError(ctx, s, i, "無效的操作")
+		return
+	}
+
+	respondDeferredUpdate(ctx, s, i)
+
+	report, err := uc.Settle(ctx, discordID, pageIDs)
+	if report == nil {
+		logf(ctx, "settle payment failed: %s", err)
+		editDeferredResponse(ctx, s, i, failureMessage(err, "標記已付款失敗"))
+
+		return
+	}
+
+	msg := fmt.Sprintf("已將 <@%s> 的 %d 筆項目標記為已付款", discordID, report.Settled)
+
+	if err != nil {
+		logf(ctx, "settle payment partially failed: %s", err)
+		msg = fmt.Sprintf(
+			"已將 <@%s> 的 %d / %d 筆項目標記為已付款", discordID, report.Settled, report.Requested,
+		) + "\n" + failureMessage(err, "其餘項目標記失敗，請查看 log")
+	}
+
+	editDeferredResponse(ctx, s, i, msg)
+}
+
+// listedPageIDs returns the page IDs offered in the /paid select menu.
+func listedPageIDs(components []discordgo.MessageComponent) []string {
+	var ids []string
+
+	for _, c := range components {
+		row, ok := c.(*discordgo.ActionsRow)
+		if !ok {
+			continue
+		}
+
+		for _, rc := range row.Components {
+			menu, ok := rc.(*discordgo.SelectMenu)
+			if !ok || customIDPrefix(menu.CustomID) != paidSelectPrefix {
+				continue
+			}
+
+			for _, opt := range menu.Options {
+				ids = append(ids, opt.Value)
+			}
+		}
+	}
+
+	return ids
+}
+
+func formatUnpaidSummary(ledger *domain.UnpaidLedger) string {
+	msg := fmt.Sprintf(
+		"<@%s> 共有 %d 筆未付款項目，合計 %s。請選擇已付款的項目，或全部結清。",
+		ledger.User.DiscordID, len(ledger.Items), formatAmount(ledger.User.Currency, ledger.Total),
+	)
+
+	if len(ledger.Items) > maxSelectOptions {
+		msg += fmt.Sprintf("\n（選單與全部結清只包含前 %d 筆）", maxSelectOptions)
+	}
+
+	return msg
+}
+
+func paidComponents(user *domain.User, items []domain.UnpaidItem) []discordgo.MessageComponent {
+	listed := items[:min(len(items), maxSelectOptions)]
+	options := make([]discordgo.SelectMenuOption, 0, len(listed))
+
+	for _, it := range listed {
+		name := it.ItemName
+		if name == "" {
+			name = "（未命名）"
+		}
+
+		label := fmt.Sprintf("%s — %s", name, formatAmount(user.Currency, it.Amount(user.Currency)))
+
+		options = append(options, discordgo.SelectMenuOption{
+			Label: truncate(label, maxSelectLabelLen),
+			Value: it.PageID,
+		})
+	}
+
+	minValues := 1
+
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.SelectMenu{
+					MenuType:    discordgo.StringSelectMenu,
+					CustomID:    fmt.Sprintf("%s:%s", paidSelectPrefix, user.DiscordID),
+					Placeholder: "選擇已付款的項目",
+					MinValues:   &minValues,
+					MaxValues:   len(options),
+					Options:     options,
+				},
+			},
+		},
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{
+					Label:    fmt.Sprintf("全部結清（%d 筆）", len(listed)),
+					Style:    discordgo.SuccessButton,
+					CustomID: fmt.Sprintf("%s:%s", paidAllPrefix, user.DiscordID),
+				},
+			},
+		},
+	}
+}
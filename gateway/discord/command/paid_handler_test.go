@@ -0,0 +1,42 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/require"
+
+	"github.com/xgnid-tw/gx5/domain"
+)
+
+func TestListedPageIDs(t *testing.T) {
+	user := &domain.User{DiscordID: "111", Currency: domain.CurrencyTWD}
+	items := make([]domain.UnpaidItem, maxSelectOptions+2)
+
+	for i := range items {
+		items[i] = domain.UnpaidItem{PageID: string(rune('a' + i)), ItemName: "CD", TWDAmount: 100}
+	}
+
+	// Components read back from a message arrive as pointers.
+	menu := paidComponents(user, items)[0].(discordgo.ActionsRow).Components[0].(discordgo.SelectMenu)
+	components := []discordgo.MessageComponent{
+		&discordgo.ActionsRow{Components: []discordgo.MessageComponent{&menu}},
+		&discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+			&discordgo.Button{CustomID: "paid_all:111"},
+		}},
+	}
+
+	ids := listedPageIDs(components)
+
+	require.Len(t, ids, maxSelectOptions)
+	require.Equal(t, "a", ids[0])
+}
+
+func TestPaidComponents_SettleAllCoversListed(t *testing.T) {
+	user := &domain.User{DiscordID: "111", Currency: domain.CurrencyTWD}
+	items := make([]domain.UnpaidItem, maxSelectOptions+2)
+
+	btn := paidComponents(user, items)[1].(discordgo.ActionsRow).Components[0].(discordgo.Button)
+
+	require.Equal(t, "全部結清（25 筆）", btn.Label)
+}
@@ -1,13 +1,101 @@
 package command
 
 import (
-	"log"
+	"context"
+	"errors"
 
 	"github.com/bwmarrin/discordgo"
+
+	"github.com/xgnid-tw/gx5/port"
 )
 
-func respondError(s *discordgo.Session, i *discordgo.InteractionCreate, msg string) {
-	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+var errModalAfterDefer = errors.New("cannot open a modal after the response was deferred")
+
+// respond sends the initial response to the interaction. When AutoDefer already
+// answered it, resp is delivered as an edit of the deferred response or as a
+// followup instead; a second explicit deferral is then a no-op.
+func respond(
+	ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, resp *discordgo.InteractionResponse,
+) error {
+	st := stateFrom(ctx)
+	if st == nil {
+		return s.InteractionRespond(i.Interaction, resp)
+	}
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	if !st.answered {
+		err := s.InteractionRespond(i.Interaction, resp)
+		st.answered = err == nil
+
+		return err
+	}
+
+	return deliverAfterDefer(s, i, st, resp)
+}
+
+func deliverAfterDefer(
+	s *discordgo.Session, i *discordgo.InteractionCreate, st *interactionState, resp *discordgo.InteractionResponse,
+) error {
+	data := resp.Data
+	if data == nil {
+		data = &discordgo.InteractionResponseData{}
+	}
+
+	ephemeral := data.Flags&discordgo.MessageFlagsEphemeral != 0
+	pendingMessage := st.autoDeferred && i.Type != discordgo.InteractionMessageComponent
+
+	switch resp.Type {
+	case discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		discordgo.InteractionResponseDeferredMessageUpdate:
+		return nil
+	case discordgo.InteractionResponseModal:
+		return errModalAfterDefer
+	case discordgo.InteractionResponseUpdateMessage:
+		return editResponse(s, i, data)
+	case discordgo.InteractionResponseChannelMessageWithSource:
+		// The placeholder of a deferred message can be edited into the reply
+		// unless the reply's visibility differs from it.
+		if pendingMessage && ephemeral == st.ephemeral {
+			return editResponse(s, i, data)
+		}
+
+		_, err := s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
+			Content:    data.Content,
+			Components: data.Components,
+			Embeds:     data.Embeds,
+			Flags:      data.Flags,
+		})
+		if err != nil || !pendingMessage {
+			return err
+		}
+
+		return s.InteractionResponseDelete(i.Interaction)
+	case discordgo.InteractionResponsePong,
+		discordgo.InteractionApplicationCommandAutocompleteResult:
+	}
+
+	return s.InteractionRespond(i.Interaction, resp)
+}
+
+func editResponse(s *discordgo.Session, i *discordgo.InteractionCreate, data *discordgo.InteractionResponseData) error {
+	edit := &discordgo.WebhookEdit{Content: &data.Content}
+	if data.Components != nil {
+		edit.Components = &data.Components
+	}
+
+	if data.Embeds != nil {
+		edit.Embeds = &data.Embeds
+	}
+
+	_, err := s.InteractionResponseEdit(i.Interaction, edit)
+
+	return err
+}
+
+func respondError(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, msg string) {
+	err := respond(ctx, s, i, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseChannelMessageWithSource,
 		Data: &discordgo.InteractionResponseData{
 			Content: msg,
@@ -15,36 +103,112 @@ func respondError(s *discordgo.Session, i *discordgo.InteractionCreate, msg stri
 		},
 	})
 	if err != nil {
-		log.Printf("error responding with error message: %s", err)
+		logf(ctx, "error responding with error message: %s", err)
 	}
 }
 
-func respondSuccess(s *discordgo.Session, i *discordgo.InteractionCreate, msg string) {
-	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+func respondSuccess(
+	ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate,
+	msg string, components []discordgo.MessageComponent,
+) {
+	err := respond(ctx, s, i, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseChannelMessageWithSource,
 		Data: &discordgo.InteractionResponseData{
-			Content: msg,
+			Content:    msg,
+			Components: components,
 		},
 	})
 	if err != nil {
-		log.Printf("error responding to interaction: %s", err)
+		logf(ctx, "error responding to interaction: %s", err)
+	}
+}
+
+// respondModal opens a modal; it has to be the initial response.
+func respondModal(
+	ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, data *discordgo.InteractionResponseData,
+) {
+	err := respond(ctx, s, i, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseModal,
+		Data: data,
+	})
+	if err != nil {
+		logf(ctx, "error responding with modal: %s", err)
+	}
+}
+
+func respondDeferred(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+	err := respond(ctx, s, i, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+	})
+	if err != nil {
+		logf(ctx, "error deferring interaction response: %s", err)
 	}
 }
 
-func respondDeferred(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+func respondDeferredEphemeral(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+	err := respond(ctx, s, i, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Flags: discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		logf(ctx, "error deferring interaction response: %s", err)
+	}
+}
+
+// respondDeferredUpdate acknowledges a component interaction; the message it
+// belongs to is then changed with editDeferredResponse.
+func respondDeferredUpdate(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+	err := respond(ctx, s, i, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredMessageUpdate,
 	})
 	if err != nil {
-		log.Printf("error deferring interaction response: %s", err)
+		logf(ctx, "error deferring component update: %s", err)
 	}
 }
 
-func editDeferredResponse(s *discordgo.Session, i *discordgo.InteractionCreate, msg string) {
+func editDeferredResponse(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, msg string) {
+	// Clearing components also removes buttons and menus when editing a component's message.
 	_, err := s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
-		Content: &msg,
+		Content:    &msg,
+		Components: &[]discordgo.MessageComponent{},
 	})
 	if err != nil {
-		log.Printf("error editing deferred response: %s", err)
+		logf(ctx, "error editing deferred response: %s", err)
 	}
 }
+
+// followupError sends an ephemeral message after the interaction was already acknowledged.
+func followupError(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, msg string) {
+	_, err := s.FollowupMessageCreate(i.Interaction, false, &discordgo.WebhookParams{
+		Content: msg,
+		Flags:   discordgo.MessageFlagsEphemeral,
+	})
+	if err != nil {
+		logf(ctx, "error sending followup message: %s", err)
+	}
+}
+
+// interactionUserID returns the ID of the user who triggered the interaction,
+// which Discord reports under Member in guilds and under User in DMs.
+func interactionUserID(i *discordgo.InteractionCreate) string {
+	if i.Member != nil && i.Member.User != nil {
+		return i.Member.User.ID
+	}
+
+	if i.User != nil {
+		return i.User.ID
+	}
+
+	return ""
+}
+
+// failureMessage appends a retry hint to msg when err was caused by Notion rate limiting.
+func failureMessage(err error, msg string) string {
+	if errors.Is(err, port.ErrRateLimited) {
+		return msg + "（Notion 忙碌中，請稍後再試）"
+	}
+
+	return msg
+}
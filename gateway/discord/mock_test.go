@@ -1,6 +1,10 @@
 package discord
 
-import "github.com/bwmarrin/discordgo"
+import (
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
 
 type mockDiscordSession struct {
 	userChannelCreateFn func(
@@ -9,7 +13,13 @@ type mockDiscordSession struct {
 	channelMessageSendFn func(
 		channelID string, content string, options ...discordgo.RequestOption,
 	) (*discordgo.Message, error)
-	sentMessages []struct{ channelID, content string }
+	sentMessages []sentMessage
+}
+
+type sentMessage struct {
+	channelID string
+	content   string
+	embeds    []*discordgo.MessageEmbed
 }
 
 func (m *mockDiscordSession) UserChannelCreate(
@@ -21,14 +31,28 @@ func (m *mockDiscordSession) UserChannelCreate(
 func (m *mockDiscordSession) ChannelMessageSend(
 	channelID string, content string, options ...discordgo.RequestOption,
 ) (*discordgo.Message, error) {
-	m.sentMessages = append(
-		m.sentMessages,
-		struct{ channelID, content string }{channelID, content},
-	)
+	m.sentMessages = append(m.sentMessages, sentMessage{channelID: channelID, content: content})
 
 	return m.channelMessageSendFn(channelID, content, options...)
 }
 
+// ChannelMessageSendComplex is answered by channelMessageSendFn with the content.
+func (m *mockDiscordSession) ChannelMessageSendComplex(
+	channelID string, data *discordgo.MessageSend, options ...discordgo.RequestOption,
+) (*discordgo.Message, error) {
+	m.sentMessages = append(m.sentMessages, sentMessage{channelID: channelID, content: data.Content, embeds: data.Embeds})
+
+	return m.channelMessageSendFn(channelID, data.Content, options...)
+}
+
 func newTestNotifier(s discordSession, logChannelID string) *Notifier {
-	return &Notifier{s: s, logChannelID: logChannelID}
+	reminders, err := LoadReminderTemplates("")
+	if err != nil {
+		panic(err)
+	}
+
+	return &Notifier{
+		s: s, logChannelID: logChannelID, reminders: reminders,
+		now: func() time.Time { return time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC) },
+	}
 }
@@ -4,6 +4,9 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/bwmarrin/discordgo"
 
@@ -13,34 +16,166 @@ import (
 type discordSession interface {
 	UserChannelCreate(recipientID string, options ...discordgo.RequestOption) (*discordgo.Channel, error)
 	ChannelMessageSend(channelID string, content string, options ...discordgo.RequestOption) (*discordgo.Message, error)
+	ChannelMessageSendComplex(
+		channelID string, data *discordgo.MessageSend, options ...discordgo.RequestOption,
+	) (*discordgo.Message, error)
 }
 
 // Notifier implements port.Notifier using Discord DMs.
 type Notifier struct {
 	s            discordSession
 	logChannelID string
+	reminders    *ReminderTemplates
+	paymentInfo  string
+	now          func() time.Time
 }
 
-func NewNotifier(s *discordgo.Session, logChannelID string) *Notifier {
-	return &Notifier{s: s, logChannelID: logChannelID}
+func NewNotifier(
+	s *discordgo.Session, logChannelID string, reminders *ReminderTemplates, paymentInfo string,
+) *Notifier {
+	return &Notifier{
+		s: s, logChannelID: logChannelID,
+		reminders: reminders, paymentInfo: paymentInfo, now: time.Now,
+	}
 }
 
-func (n *Notifier) Notify(_ context.Context, user domain.User, debug bool) error {
-	message := fmt.Sprintf(
-		"[欠費提醒] https://www.notion.so/%s (如果有漏登聯絡一下XG) ",
-		user.NotionID,
+// Notify DMs the member their reminder embed and posts a copy to the log channel.
+func (n *Notifier) Notify(_ context.Context, balance domain.Balance, debug bool) error {
+	embed, err := n.reminders.Render(balance, n.paymentInfo, n.now())
+	if err != nil {
+		return fmt.Errorf("error rendering reminder: %w", err)
+	}
+
+	embeds := []*discordgo.MessageEmbed{embed}
+
+	return n.send(
+		balance.User.DiscordID,
+		&discordgo.MessageSend{Content: "[欠費提醒] " + balance.User.Name, Embeds: embeds},
+		&discordgo.MessageSend{Embeds: embeds},
+		debug,
 	)
+}
+
+func (n *Notifier) NotifyItemsArrived(_ context.Context, user domain.User, items []domain.ItemRecord) error {
+	lines := make([]string, 0, len(items)+1)
+	lines = append(lines, "[到貨通知] 以下物品已回台，可以準備取貨囉：")
+
+	for _, it := range items {
+		lines = append(lines, "- "+it.ItemName)
+	}
+
+	return n.sendDM(user.DiscordID, strings.Join(lines, "\n"), false)
+}
 
-	return n.sendDM(user.DiscordID, message, debug)
+// ReportReminder posts the counts of a debt reminder run to the log channel,
+// followed by every member who owes money or whose reminder failed. A report
+// longer than a Discord message is posted as several messages.
+func (n *Notifier) ReportReminder(_ context.Context, report domain.ReminderReport) error {
+	for _, msg := range splitLines(reminderReportLines(report), maxMessageLen) {
+		_, err := n.s.ChannelMessageSend(n.logChannelID, msg)
+		if err != nil {
+			return fmt.Errorf("error sending to log channel: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func reminderReportLines(report domain.ReminderReport) []string {
+	mode := "正式模式"
+	if report.Debug {
+		mode = "除錯模式"
+	}
+
+	if report.MinAmount != nil {
+		mode += fmt.Sprintf("，本次門檻 ≥%.0f（各成員幣別）", *report.MinAmount)
+	}
+
+	lines := []string{fmt.Sprintf(
+		"[欠費提醒報告]（%s）通知 %d 人、略過 %d 人、失敗 %d 人", mode,
+		report.Count(domain.ReminderNotified), report.Count(domain.ReminderSkipped), report.Count(domain.ReminderFailed),
+	)}
+
+	for _, res := range report.Results {
+		if res.Amount <= 0 && res.Outcome != domain.ReminderFailed {
+			continue
+		}
+
+		line := fmt.Sprintf(
+			"%s %s %s（門檻 %s）", reminderOutcomeMark[res.Outcome], res.User.Name,
+			reminderAmount(res.User.Currency, res.Amount), reminderAmount(res.User.Currency, res.Threshold),
+		)
+		if res.Reason != "" {
+			line += "：" + res.Reason
+		}
+
+		lines = append(lines, line)
+	}
+
+	return lines
+}
+
+// splitLines joins lines into messages of at most limit runes, breaking only
+// between lines. A single line longer than limit is truncated.
+func splitLines(lines []string, limit int) []string {
+	var (
+		msgs []string
+		cur  []string
+		size int
+	)
+
+	for _, line := range lines {
+		if runes := []rune(line); len(runes) > limit {
+			line = string(runes[:limit-1]) + "…"
+		}
+
+		n := utf8.RuneCountInString(line)
+		if len(cur) > 0 && size+1+n > limit {
+			msgs = append(msgs, strings.Join(cur, "\n"))
+			cur, size = nil, 0
+		}
+
+		if len(cur) > 0 {
+			size++ // the newline joining it to the previous line
+		}
+
+		cur = append(cur, line)
+		size += n
+	}
+
+	if len(cur) > 0 {
+		msgs = append(msgs, strings.Join(cur, "\n"))
+	}
+
+	return msgs
+}
+
+var reminderOutcomeMark = map[domain.ReminderOutcome]string{
+	domain.ReminderNotified: "✅",
+	domain.ReminderSkipped:  "⏭️",
+	domain.ReminderFailed:   "❌",
+}
+
+func reminderAmount(c domain.Currency, amount float64) string {
+	return fmt.Sprintf("%s%.0f", currencySymbol(c), amount)
 }
 
 func (n *Notifier) sendDM(discordID string, message string, debug bool) error {
+	msg := &discordgo.MessageSend{Content: message}
+
+	return n.send(discordID, msg, msg, debug)
+}
+
+// send posts logMsg to the log channel and, unless debug, dm to the member.
+func (n *Notifier) send(
+	discordID string, logMsg *discordgo.MessageSend, dm *discordgo.MessageSend, debug bool,
+) error {
 	channel, err := n.s.UserChannelCreate(discordID)
 	if err != nil {
 		return fmt.Errorf("error creating channel: %w", err)
 	}
 
-	_, err = n.s.ChannelMessageSend(n.logChannelID, message)
+	_, err = n.s.ChannelMessageSendComplex(n.logChannelID, logMsg)
 	if err != nil {
 		return fmt.Errorf("error sending to log channel: %w", err)
 	}
@@ -50,7 +185,7 @@ func (n *Notifier) sendDM(discordID string, message string, debug bool) error {
 		return nil
 	}
 
-	_, err = n.s.ChannelMessageSend(channel.ID, message)
+	_, err = n.s.ChannelMessageSendComplex(channel.ID, dm)
 	if err != nil {
 		return fmt.Errorf("error sending dm: %w", err)
 	}
@@ -3,7 +3,10 @@ package discord
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
+	"unicode/utf8"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/stretchr/testify/require"
@@ -13,6 +16,15 @@ import (
 
 var testUser = domain.User{DiscordID: "111", Name: "Alice", NotionID: "abc"}
 
+var testBalance = domain.Balance{
+	UnpaidLedger: domain.UnpaidLedger{
+		User:  testUser,
+		Items: []domain.UnpaidItem{{ItemName: "CD", TWDAmount: 2500}},
+		Total: 2500,
+	},
+	Threshold: 2000,
+}
+
 func TestNotify_DebugMode_SkipsDM(t *testing.T) {
 	m := &mockDiscordSession{
 		userChannelCreateFn: func(string, ...discordgo.RequestOption) (*discordgo.Channel, error) {
@@ -24,7 +36,7 @@ func TestNotify_DebugMode_SkipsDM(t *testing.T) {
 	}
 
 	n := newTestNotifier(m, "log-chan")
-	err := n.Notify(context.Background(), testUser, true)
+	err := n.Notify(context.Background(), testBalance, true)
 
 	require.NoError(t, err)
 	require.Len(t, m.sentMessages, 1)
@@ -42,13 +54,40 @@ func TestNotify_NormalMode_SendsDM(t *testing.T) {
 	}
 
 	n := newTestNotifier(m, "log-chan")
-	err := n.Notify(context.Background(), testUser, false)
+	err := n.Notify(context.Background(), testBalance, false)
 
 	require.NoError(t, err)
 	require.Len(t, m.sentMessages, 2)
 	require.Equal(t, "log-chan", m.sentMessages[0].channelID)
 	require.Equal(t, "dm-chan", m.sentMessages[1].channelID)
-	require.Contains(t, m.sentMessages[1].content, testUser.NotionID)
+	require.Equal(t, "[欠費提醒] Alice", m.sentMessages[0].content)
+	require.Equal(t, m.sentMessages[0].embeds, m.sentMessages[1].embeds, "the log channel gets a copy of the DM")
+	require.Len(t, m.sentMessages[1].embeds, 1)
+	require.Equal(t, "https://www.notion.so/abc", m.sentMessages[1].embeds[0].URL)
+}
+
+func TestNotify_SharedLedger_NoDatabaseLink(t *testing.T) {
+	m := &mockDiscordSession{
+		userChannelCreateFn: func(string, ...discordgo.RequestOption) (*discordgo.Channel, error) {
+			return &discordgo.Channel{ID: "dm-chan"}, nil
+		},
+		channelMessageSendFn: func(string, string, ...discordgo.RequestOption) (*discordgo.Message, error) {
+			return &discordgo.Message{}, nil
+		},
+	}
+
+	balance := testBalance
+	balance.User.NotionID = "others-db"
+	balance.Shared = true
+
+	n := newTestNotifier(m, "log-chan")
+	err := n.Notify(context.Background(), balance, false)
+
+	require.NoError(t, err)
+
+	embed := m.sentMessages[1].embeds[0]
+	require.Empty(t, embed.URL)
+	require.NotContains(t, embed.Description, "others-db", "the shared database lists everyone's records")
 }
 
 func TestNotify_UserChannelCreateFails(t *testing.T) {
@@ -62,7 +101,7 @@ func TestNotify_UserChannelCreateFails(t *testing.T) {
 	}
 
 	n := newTestNotifier(m, "log-chan")
-	err := n.Notify(context.Background(), testUser, false)
+	err := n.Notify(context.Background(), testBalance, false)
 
 	require.Error(t, err)
 	require.ErrorContains(t, err, "error creating channel")
@@ -79,7 +118,7 @@ func TestNotify_LogChannelSendFails(t *testing.T) {
 	}
 
 	n := newTestNotifier(m, "log-chan")
-	err := n.Notify(context.Background(), testUser, false)
+	err := n.Notify(context.Background(), testBalance, false)
 
 	require.Error(t, err)
 	require.ErrorContains(t, err, "error sending to log channel")
@@ -102,8 +141,97 @@ func TestNotify_DMSendFails(t *testing.T) {
 	}
 
 	n := newTestNotifier(m, "log-chan")
-	err := n.Notify(context.Background(), testUser, false)
+	err := n.Notify(context.Background(), testBalance, false)
 
 	require.Error(t, err)
 	require.ErrorContains(t, err, "error sending dm")
 }
+
+func TestNotifyItemsArrived_ListsItems(t *testing.T) {
+	m := &mockDiscordSession{
+		userChannelCreateFn: func(string, ...discordgo.RequestOption) (*discordgo.Channel, error) {
+			return &discordgo.Channel{ID: "dm-chan"}, nil
+		},
+		channelMessageSendFn: func(string, string, ...discordgo.RequestOption) (*discordgo.Message, error) {
+			return &discordgo.Message{}, nil
+		},
+	}
+
+	n := newTestNotifier(m, "log-chan")
+	err := n.NotifyItemsArrived(context.Background(), testUser, []domain.ItemRecord{
+		{ItemName: "Acrylic Stand"}, {ItemName: "CD"},
+	})
+
+	require.NoError(t, err)
+	require.Len(t, m.sentMessages, 2)
+	require.Equal(t, "dm-chan", m.sentMessages[1].channelID)
+	require.Contains(t, m.sentMessages[1].content, "已回台")
+	require.Contains(t, m.sentMessages[1].content, "- Acrylic Stand\n- CD")
+}
+
+func TestReportReminder_ListsDebtorsAndFailures(t *testing.T) {
+	m := &mockDiscordSession{
+		channelMessageSendFn: func(string, string, ...discordgo.RequestOption) (*discordgo.Message, error) {
+			return &discordgo.Message{}, nil
+		},
+	}
+
+	report := domain.ReminderReport{Results: []domain.ReminderResult{
+		{User: testUser, Amount: 2500, Threshold: 2000, Outcome: domain.ReminderNotified},
+		{
+			User: domain.User{Name: "Bob", Currency: domain.CurrencyJPY}, Amount: 9000, Threshold: 8000,
+			Outcome: domain.ReminderFailed, Reason: "error sending dm",
+		},
+		{User: domain.User{Name: "Carol"}, Outcome: domain.ReminderSkipped, Reason: "沒有未付款項目"},
+	}}
+
+	n := newTestNotifier(m, "log-chan")
+	err := n.ReportReminder(context.Background(), report)
+
+	require.NoError(t, err)
+	require.Len(t, m.sentMessages, 1)
+	require.Equal(t, "log-chan", m.sentMessages[0].channelID)
+	require.Equal(t,
+		"[欠費提醒報告]（正式模式）通知 1 人、略過 1 人、失敗 1 人\n"+
+			"✅ Alice NT$2500（門檻 NT$2000）\n"+
+			"❌ Bob ¥9000（門檻 ¥8000）：error sending dm",
+		m.sentMessages[0].content,
+	)
+}
+
+func TestReportReminder_SplitsLongReports(t *testing.T) {
+	m := &mockDiscordSession{
+		channelMessageSendFn: func(string, string, ...discordgo.RequestOption) (*discordgo.Message, error) {
+			return &discordgo.Message{}, nil
+		},
+	}
+
+	var results []domain.ReminderResult
+	for k := range 60 {
+		results = append(results, domain.ReminderResult{
+			User: domain.User{Name: fmt.Sprintf("m%02d", k)}, Amount: 2500, Threshold: 2000,
+			Outcome: domain.ReminderFailed, Reason: strings.Repeat("notion error ", 5),
+		})
+	}
+
+	n := newTestNotifier(m, "log-chan")
+	err := n.ReportReminder(context.Background(), domain.ReminderReport{Results: results})
+
+	require.NoError(t, err)
+	require.Greater(t, len(m.sentMessages), 1)
+
+	var all []string
+	for _, msg := range m.sentMessages {
+		require.LessOrEqual(t, utf8.RuneCountInString(msg.content), maxMessageLen)
+		all = append(all, msg.content)
+	}
+
+	lines := strings.Split(strings.Join(all, "\n"), "\n")
+	require.Len(t, lines, 61, "the header and every member, none cut off")
+	require.Equal(t, "❌ m59 NT$2500（門檻 NT$2000）："+strings.Repeat("notion error ", 5), lines[60])
+}
+
+func TestSplitLines(t *testing.T) {
+	require.Equal(t, []string{"aaa\nbb", "cccc", "ddddd…"}, splitLines([]string{"aaa", "bb", "cccc", "ddddddd"}, 6))
+	require.Empty(t, splitLines(nil, 6))
+}
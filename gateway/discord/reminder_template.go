@@ -0,0 +1,242 @@
+package discord
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/xgnid-tw/gx5/domain"
+)
+
+const (
+	reminderTemplate       = "reminder.tmpl"        // members with their own TBL-002
+	sharedReminderTemplate = "reminder_shared.tmpl" // members recorded in the shared TBL-003
+
+	reminderEmbedColor = 0xE74C3C
+	maxEmbedTitleLen   = 256  // Discord limit per embed title
+	maxEmbedFooterLen  = 2048 // Discord limit per embed footer
+)
+
+//go:embed templates/*.tmpl
+var defaultReminderTemplates embed.FS
+
+// ReminderData is what the debt reminder templates are rendered with.
+type ReminderData struct {
+	Name        string // TBL-001 name
+	Currency    domain.Currency
+	Symbol      string // NT$ or ¥
+	Total       float64
+	Threshold   float64
+	Items       []ReminderItem // oldest first
+	OldestDays  int            // age of the oldest unpaid item
+	LedgerURL   string         // the member's TBL-002; empty for TBL-003 members
+	PaymentInfo string         // REMINDER_PAYMENT_INFO, may be empty
+}
+
+// ReminderItem is one unpaid item of ReminderData.
+type ReminderItem struct {
+	Name    string
+	Amount  float64 // in the member's currency
+	URL     string  // Notion page, may be empty
+	AgeDays int
+}
+
+// ReminderTemplates renders debt reminder DMs. Each template file defines a
+// "title" and a "description" template and optionally a "footer".
+type ReminderTemplates struct {
+	personal *template.Template
+	shared   *template.Template
+}
+
+// LoadReminderTemplates parses the reminder templates. A file in dir replaces
+// the built-in template of the same name; dir may be empty to use only the
+// built-in ones. Every template is rendered once against sample data, so a
+// misspelled field fails here rather than on the first reminder.
+func LoadReminderTemplates(dir string) (*ReminderTemplates, error) {
+	personal, err := parseReminderTemplate(dir, reminderTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	shared, err := parseReminderTemplate(dir, sharedReminderTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, tmpl := range []*template.Template{personal, shared} {
+		_, err = renderReminder(tmpl, sampleReminderData())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &ReminderTemplates{personal: personal, shared: shared}, nil
+}
+
+func parseReminderTemplate(dir string, name string) (*template.Template, error) {
+	var (
+		src []byte
+		err error
+	)
+
+	if dir != "" {
+		src, err = os.ReadFile(filepath.Join(dir, name))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("read %s: %w", name, err)
+		}
+	}
+
+	if src == nil {
+		src, err = defaultReminderTemplates.ReadFile("templates/" + name)
+		if err != nil {
+			return nil, fmt.Errorf("read built-in %s: %w", name, err)
+		}
+	}
+
+	tmpl, err := template.New(name).Parse(string(src))
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", name, err)
+	}
+
+	for _, part := range []string{"title", "description"} {
+		if tmpl.Lookup(part) == nil {
+			return nil, fmt.Errorf("%s does not define %q", name, part)
+		}
+	}
+
+	return tmpl, nil
+}
+
+// Render returns the reminder embed for the member's balance, with item ages
+// counted up to now.
+func (t *ReminderTemplates) Render(
+	b domain.Balance, paymentInfo string, now time.Time,
+) (*discordgo.MessageEmbed, error) {
+	tmpl := t.personal
+	if b.Shared {
+		tmpl = t.shared
+	}
+
+	return renderReminder(tmpl, reminderData(b, paymentInfo, now))
+}
+
+func renderReminder(tmpl *template.Template, data ReminderData) (*discordgo.MessageEmbed, error) {
+	part := func(name string, limit int) (string, error) {
+		if tmpl.Lookup(name) == nil {
+			return "", nil
+		}
+
+		var buf bytes.Buffer
+
+		err := tmpl.ExecuteTemplate(&buf, name, data)
+		if err != nil {
+			return "", fmt.Errorf("render %s: %w", tmpl.Name(), err)
+		}
+
+		text := strings.TrimSpace(buf.String())
+		if runes := []rune(text); len(runes) > limit {
+			text = string(runes[:limit-1]) + "…"
+		}
+
+		return text, nil
+	}
+
+	title, err := part("title", maxEmbedTitleLen)
+	if err != nil {
+		return nil, err
+	}
+
+	description, err := part("description", maxEmbedDescriptionLen)
+	if err != nil {
+		return nil, err
+	}
+
+	footer, err := part("footer", maxEmbedFooterLen)
+	if err != nil {
+		return nil, err
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       title,
+		URL:         data.LedgerURL,
+		Description: description,
+		Color:       reminderEmbedColor,
+	}
+	if footer != "" {
+		embed.Footer = &discordgo.MessageEmbedFooter{Text: footer}
+	}
+
+	return embed, nil
+}
+
+// reminderData lists the items oldest first. Members with their own TBL-002 get
+// a link to it; TBL-003 is shared by everyone, so its members only get the
+// links of their own items.
+func reminderData(b domain.Balance, paymentInfo string, now time.Time) ReminderData {
+	data := ReminderData{
+		Name:        b.User.Name,
+		Currency:    b.User.Currency,
+		Symbol:      currencySymbol(b.User.Currency),
+		Total:       b.Total,
+		Threshold:   b.Threshold,
+		PaymentInfo: paymentInfo,
+	}
+
+	if !b.Shared {
+		data.LedgerURL = "https://www.notion.so/" + b.User.NotionID
+	}
+
+	items := append([]domain.UnpaidItem(nil), b.Items...)
+	slices.SortStableFunc(items, func(a, b domain.UnpaidItem) int { return a.CreatedAt.Compare(b.CreatedAt) })
+
+	for _, it := range items {
+		name := it.ItemName
+		if name == "" {
+			name = "（未命名）"
+		}
+
+		age := 0
+		if !it.CreatedAt.IsZero() {
+			age = max(0, int(now.Sub(it.CreatedAt).Hours()/24))
+		}
+
+		data.Items = append(data.Items, ReminderItem{
+			Name: name, Amount: it.Amount(b.User.Currency), URL: it.URL, AgeDays: age,
+		})
+		data.OldestDays = max(data.OldestDays, age)
+	}
+
+	return data
+}
+
+func currencySymbol(c domain.Currency) string {
+	if c == domain.CurrencyJPY {
+		return "¥"
+	}
+
+	return "NT$"
+}
+
+// sampleReminderData is a typical reminder, used to check templates at load.
+func sampleReminderData() ReminderData {
+	return ReminderData{
+		Name: "Alice", Currency: domain.CurrencyTWD, Symbol: "NT$", Total: 2600, Threshold: 2000,
+		Items: []ReminderItem{
+			{Name: "壓克力立牌", Amount: 1200, URL: "https://www.notion.so/page-1", AgeDays: 40},
+			{Name: "CD", Amount: 1400, AgeDays: 3},
+		},
+		OldestDays:  40,
+		LedgerURL:   "https://www.notion.so/abc",
+		PaymentInfo: "轉帳至 000-1234567",
+	}
+}
@@ -0,0 +1,132 @@
+package discord
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/xgnid-tw/gx5/domain"
+)
+
+func TestReminderTemplates_Personal(t *testing.T) {
+	reminders, err := LoadReminderTemplates("")
+	require.NoError(t, err)
+
+	embed, err := renderReminder(reminders.personal, sampleReminderData())
+
+	require.NoError(t, err)
+	require.Equal(t, "💰 欠費提醒：未付款 NT$2600", embed.Title)
+	require.Equal(t, "https://www.notion.so/abc", embed.URL)
+	require.Equal(t,
+		"Alice 你好，目前有 2 筆未付款，合計 **NT$2600**，最早的一筆已經 40 天了。\n\n"+
+			"・[壓克力立牌](https://www.notion.so/page-1) NT$1200（40 天）\n"+
+			"・CD NT$1400（3 天）\n\n"+
+			"**付款方式**\n轉帳至 000-1234567\n\n"+
+			"[完整明細](https://www.notion.so/abc)",
+		embed.Description,
+	)
+	require.Equal(t, "如果有漏登聯絡一下XG", embed.Footer.Text)
+}
+
+func TestReminderTemplates_Shared(t *testing.T) {
+	reminders, err := LoadReminderTemplates("")
+	require.NoError(t, err)
+
+	data := sampleReminderData()
+	data.LedgerURL = ""
+	data.PaymentInfo = ""
+
+	embed, err := renderReminder(reminders.shared, data)
+
+	require.NoError(t, err)
+	require.Equal(t, "💰 欠費提醒：未付款 NT$2600", embed.Title)
+	require.Empty(t, embed.URL)
+	require.Equal(t,
+		"Alice 你好，共用帳本中購買人為你的項目有 2 筆未付款，合計 **NT$2600**，最早的一筆已經 40 天了。\n\n"+
+			"・[壓克力立牌](https://www.notion.so/page-1) NT$1200（40 天）\n"+
+			"・CD NT$1400（3 天）",
+		embed.Description,
+	)
+	require.Equal(t, "如果有漏登聯絡一下XG", embed.Footer.Text)
+}
+
+func writeTemplate(t *testing.T, dir string, name string, content string) {
+	t.Helper()
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+}
+
+func TestLoadReminderTemplates_Override(t *testing.T) {
+	dir := t.TempDir()
+	writeTemplate(t, dir, reminderTemplate,
+		`{{define "title"}}請付款{{end}}{{define "description"}}{{.Name}} {{.Symbol}}{{.Total}}{{end}}`)
+
+	reminders, err := LoadReminderTemplates(dir)
+	require.NoError(t, err)
+
+	embed, err := renderReminder(reminders.personal, sampleReminderData())
+
+	require.NoError(t, err)
+	require.Equal(t, "請付款", embed.Title)
+	require.Equal(t, "Alice NT$2600", embed.Description)
+	require.Nil(t, embed.Footer, "the footer is optional")
+
+	embed, err = renderReminder(reminders.shared, sampleReminderData())
+
+	require.NoError(t, err)
+	require.Equal(t, "💰 欠費提醒：未付款 NT$2600", embed.Title, "files left out keep the built-in template")
+}
+
+func TestLoadReminderTemplates_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"syntax", `{{define "title"}}{{.Name}{{end}}`, "parse reminder.tmpl"},
+		{"missing part", `{{define "title"}}請付款{{end}}`, `reminder.tmpl does not define "description"`},
+		{
+			"unknown field",
+			`{{define "title"}}請付款{{end}}{{define "description"}}{{.Amount}}{{end}}`,
+			"render reminder.tmpl",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeTemplate(t, dir, reminderTemplate, tt.content)
+
+			_, err := LoadReminderTemplates(dir)
+
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestReminderData(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+
+	data := reminderData(domain.Balance{
+		UnpaidLedger: domain.UnpaidLedger{
+			User: domain.User{Name: "Bob", NotionID: "bob-db", Currency: domain.CurrencyJPY},
+			Items: []domain.UnpaidItem{
+				{ItemName: "CD", JPYAmount: 3000, CreatedAt: now.Add(-50 * time.Hour)},
+				{JPYAmount: 6000, URL: "https://www.notion.so/page-2", CreatedAt: now.AddDate(0, 0, -30)},
+			},
+			Total: 9000,
+		},
+		Threshold: 8000,
+	}, "", now)
+
+	require.Equal(t, "¥", data.Symbol)
+	require.Equal(t, "https://www.notion.so/bob-db", data.LedgerURL)
+	require.Equal(t, 30, data.OldestDays)
+	require.Equal(t, []ReminderItem{
+		{Name: "（未命名）", Amount: 6000, URL: "https://www.notion.so/page-2", AgeDays: 30},
+		{Name: "CD", Amount: 3000, AgeDays: 2},
+	}, data.Items, "oldest first, in the member's currency")
+}
@@ -3,8 +3,11 @@ package discord
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/bwmarrin/discordgo"
+
+	"github.com/xgnid-tw/gx5/domain"
 )
 
 type threadSession interface {
@@ -14,8 +17,22 @@ type threadSession interface {
 	ChannelMessageSendComplex(
 		channelID string, data *discordgo.MessageSend, options ...discordgo.RequestOption,
 	) (*discordgo.Message, error)
+	ChannelEdit(
+		channelID string, data *discordgo.ChannelEdit, options ...discordgo.RequestOption,
+	) (*discordgo.Channel, error)
+	ChannelMessageEditComplex(
+		m *discordgo.MessageEdit, options ...discordgo.RequestOption,
+	) (*discordgo.Message, error)
+	ChannelMessagePin(channelID string, messageID string, options ...discordgo.RequestOption) error
 }
 
+const (
+	maxThreadNameLen       = 100  // Discord limit per channel name
+	maxEmbedDescriptionLen = 4096 // Discord limit per embed description
+	maxMessageLen          = 2000 // Discord limit per message content
+	summaryEmbedColor      = 0x5865F2
+)
+
 // ThreadCreator implements port.ThreadCreator using the Discord API.
 type ThreadCreator struct {
 	s threadSession
@@ -58,3 +75,111 @@ func (tc *ThreadCreator) SendThreadMessage(
 
 	return nil
 }
+
+func (tc *ThreadCreator) CloseThread(_ context.Context, threadID string, name string) error {
+	locked := true
+
+	if r := []rune(name); len(r) > maxThreadNameLen {
+		name = string(r[:maxThreadNameLen])
+	}
+
+	_, err := tc.s.ChannelEdit(threadID, &discordgo.ChannelEdit{
+		Name:   name,
+		Locked: &locked,
+	})
+	if err != nil {
+		return fmt.Errorf("error closing thread: %w", err)
+	}
+
+	return nil
+}
+
+func (tc *ThreadCreator) PostSummary(
+	_ context.Context, threadID string, rollup domain.OrderRollup,
+) (string, error) {
+	msg, err := tc.s.ChannelMessageSendComplex(threadID, &discordgo.MessageSend{
+		Embeds: []*discordgo.MessageEmbed{orderSummaryEmbed(rollup)},
+	})
+	if err != nil {
+		return "", fmt.Errorf("error sending order summary: %w", err)
+	}
+
+	err = tc.s.ChannelMessagePin(threadID, msg.ID)
+	if err != nil {
+		return msg.ID, fmt.Errorf("error pinning order summary: %w", err)
+	}
+
+	return msg.ID, nil
+}
+
+func (tc *ThreadCreator) UpdateSummary(
+	_ context.Context, threadID string, messageID string, rollup domain.OrderRollup,
+) error {
+	embeds := []*discordgo.MessageEmbed{orderSummaryEmbed(rollup)}
+
+	_, err := tc.s.ChannelMessageEditComplex(&discordgo.MessageEdit{
+		Channel: threadID,
+		ID:      messageID,
+		Embeds:  &embeds,
+	})
+	if err != nil {
+		return fmt.Errorf("error editing order summary: %w", err)
+	}
+
+	return nil
+}
+
+// orderSummaryEmbed lists every record of the order grouped by participant,
+// with the totals owed to the shop and collected from members.
+func orderSummaryEmbed(r domain.OrderRollup) *discordgo.MessageEmbed {
+	var (
+		lines []string
+		last  string
+	)
+
+	for _, l := range r.Lines {
+		if l.Participant != last || len(lines) == 0 {
+			lines = append(lines, fmt.Sprintf("**%s**", participantName(l.Participant)))
+			last = l.Participant
+		}
+
+		lines = append(lines, fmt.Sprintf("・%s ¥%.0f / NT$%.0f", l.ItemName, l.JPYAmount, l.TWDAmount))
+	}
+
+	description := "尚無登記"
+	if len(lines) > 0 {
+		description = strings.Join(lines, "\n")
+	}
+
+	if runes := []rune(description); len(runes) > maxEmbedDescriptionLen {
+		description = string(runes[:maxEmbedDescriptionLen-1]) + "…"
+	}
+
+	fields := []*discordgo.MessageEmbedField{
+		{Name: "參加人數", Value: fmt.Sprintf("%d 人", len(r.Participants)), Inline: true},
+		{Name: "品項", Value: fmt.Sprintf("%d 筆", r.Items), Inline: true},
+		{Name: "應付店家", Value: fmt.Sprintf("¥%.0f", r.TotalJPY), Inline: true},
+		{Name: "應收台幣", Value: fmt.Sprintf("NT$%.0f", r.TotalTWD), Inline: true},
+	}
+
+	if len(r.Unreadable) > 0 {
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name: "未計入（無法讀取）", Value: strings.Join(r.Unreadable, "、"),
+		})
+	}
+
+	return &discordgo.MessageEmbed{
+		Title:       "📋 " + r.Order.ThreadName,
+		Description: description,
+		Color:       summaryEmbedColor,
+		Fields:      fields,
+	}
+}
+
+func participantName(name string) string {
+	if name == "" {
+		return "（未指定）"
+	}
+
+	return name
+}
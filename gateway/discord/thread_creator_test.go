@@ -0,0 +1,53 @@
+package discord
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/xgnid-tw/gx5/domain"
+)
+
+func TestOrderSummaryEmbed(t *testing.T) {
+	embed := orderSummaryEmbed(domain.OrderRollup{
+		Order:        domain.Order{ThreadName: "test order"},
+		Participants: []string{"Alice", "Bob"},
+		Lines: []domain.OrderLine{
+			{Participant: "Alice", ItemName: "Photo", JPYAmount: 3000, TWDAmount: 720},
+			{Participant: "Bob", ItemName: "Badge", JPYAmount: 1000, TWDAmount: 240},
+			{Participant: "Bob", ItemName: "Acrylic", JPYAmount: 2000, TWDAmount: 480},
+		},
+		Items:    3,
+		TotalJPY: 6000,
+		TotalTWD: 1440,
+	})
+
+	require.Equal(t, "📋 test order", embed.Title)
+	require.Equal(t,
+		"**Alice**\n・Photo ¥3000 / NT$720\n**Bob**\n・Badge ¥1000 / NT$240\n・Acrylic ¥2000 / NT$480",
+		embed.Description)
+	require.Equal(t, "2 人", embed.Fields[0].Value)
+	require.Equal(t, "3 筆", embed.Fields[1].Value)
+	require.Equal(t, "¥6000", embed.Fields[2].Value)
+	require.Equal(t, "NT$1440", embed.Fields[3].Value)
+}
+
+func TestOrderSummaryEmbed_Empty(t *testing.T) {
+	embed := orderSummaryEmbed(domain.OrderRollup{Order: domain.Order{ThreadName: "test order"}})
+
+	require.Equal(t, "尚無登記", embed.Description)
+	require.Equal(t, "0 人", embed.Fields[0].Value)
+}
+
+func TestOrderSummaryEmbed_Truncated(t *testing.T) {
+	lines := make([]domain.OrderLine, 500)
+	for i := range lines {
+		lines[i] = domain.OrderLine{Participant: "Alice", ItemName: strings.Repeat("品", 20)}
+	}
+
+	embed := orderSummaryEmbed(domain.OrderRollup{Lines: lines})
+
+	require.Len(t, []rune(embed.Description), maxEmbedDescriptionLen)
+	require.True(t, strings.HasSuffix(embed.Description, "…"))
+}
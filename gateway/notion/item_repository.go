@@ -0,0 +1,100 @@
+package notion
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jomei/notionapi"
+
+	"github.com/xgnid-tw/gx5/domain"
+)
+
+// ItemRepository implements port.ItemRepository using the Notion API.
+type ItemRepository struct {
+	page notionapi.PageService
+	db   notionapi.DatabaseService
+	cols TransactionColumns
+}
+
+func NewItemRepository(page notionapi.PageService, db notionapi.DatabaseService, schema Schema) *ItemRepository {
+	return &ItemRepository{page: page, db: db, cols: schema.Transactions}
+}
+
+func (r *ItemRepository) GetItem(ctx context.Context, pageID string) (*domain.ItemRecord, error) {
+	p, err := r.page.Get(ctx, notionapi.PageID(pageID))
+	if err != nil {
+		return nil, fmt.Errorf("notion page get failed: %w", err)
+	}
+
+	item := r.toItemRecord(*p)
+
+	return &item, nil
+}
+
+func (r *ItemRepository) GetOrderItems(
+	ctx context.Context, databaseID string, orderID string,
+) ([]domain.ItemRecord, error) {
+	pages, err := queryAll(ctx, r.db, notionapi.DatabaseID(databaseID), &notionapi.DatabaseQueryRequest{
+		Filter: notionapi.PropertyFilter{
+			Property: r.cols.Order,
+			Relation: &notionapi.RelationFilterCondition{Contains: orderID},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]domain.ItemRecord, 0, len(pages))
+	for _, p := range pages {
+		items = append(items, r.toItemRecord(p))
+	}
+
+	return items, nil
+}
+
+func (r *ItemRepository) toItemRecord(p notionapi.Page) domain.ItemRecord {
+	name, _ := getTitleContent(p.Properties[r.cols.ItemName])
+	status, _ := getSelectContent(p.Properties[r.cols.ItemStatus])
+	buyer, _ := getSelectContent(p.Properties[r.cols.Buyer])
+	jpy, _ := getNumberContent(p.Properties[r.cols.JPYAmount])
+	twd, _ := getNumberContent(p.Properties[r.cols.TWDAmount])
+	shop, _ := getSelectContent(p.Properties[r.cols.Shop])
+	link, _ := getURLContent(p.Properties[r.cols.Link])
+	note, _ := getRichTextContent(p.Properties[r.cols.Note])
+
+	var arrival string
+	if d, ok := getDateContent(p.Properties[r.cols.ExpectedArrival]); ok {
+		arrival = d.Format("2006-01-02")
+	}
+
+	return domain.ItemRecord{
+		PageID:     string(p.ID),
+		ItemName:   name,
+		Status:     r.cols.itemStatus(status),
+		DatabaseID: string(p.Parent.DatabaseID),
+		BuyerName:  buyer,
+		JPYAmount:  jpy,
+		TWDAmount:  twd,
+		Shop:       shop,
+		Link:       link,
+		Note:       note,
+
+		ExpectedArrival: arrival,
+	}
+}
+
+func (r *ItemRepository) UpdateItemStatus(ctx context.Context, pageID string, status domain.ItemStatus) error {
+	_, err := r.page.Update(ctx, notionapi.PageID(pageID), &notionapi.PageUpdateRequest{
+		Properties: notionapi.Properties{
+			r.cols.ItemStatus: notionapi.SelectProperty{
+				Type:   notionapi.PropertyTypeSelect,
+				Select: notionapi.Option{Name: r.cols.itemStatusOption(status)},
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("notion page update failed for %s: %w", pageID, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,138 @@
+package notion
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jomei/notionapi"
+	"github.com/stretchr/testify/require"
+
+	"github.com/xgnid-tw/gx5/domain"
+)
+
+func TestGetItem_Success(t *testing.T) {
+	p := makeItemPage("p1", "Badge", 240, 1000)
+	p.Parent = notionapi.Parent{Type: notionapi.ParentTypeDatabaseID, DatabaseID: "others-db"}
+	p.Properties["物品狀況"] = &notionapi.SelectProperty{Select: notionapi.Option{Name: "已到貨"}}
+	p.Properties["購買人"] = &notionapi.SelectProperty{Select: notionapi.Option{Name: "Carol"}}
+	arrival := notionapi.Date(time.Date(2026, 11, 30, 0, 0, 0, 0, time.UTC))
+	p.Properties["預計到貨"] = &notionapi.DateProperty{Date: &notionapi.DateObject{Start: &arrival}}
+
+	page := &mockPageService{
+		getFn: func(_ context.Context, id notionapi.PageID) (*notionapi.Page, error) {
+			require.Equal(t, notionapi.PageID("p1"), id)
+			return &p, nil
+		},
+	}
+
+	repo := NewItemRepository(page, &mockDatabaseService{}, DefaultSchema())
+	item, err := repo.GetItem(context.Background(), "p1")
+
+	require.NoError(t, err)
+	require.Equal(t, &domain.ItemRecord{
+		PageID: "p1", ItemName: "Badge", Status: domain.ItemStatusArrivedJP,
+		DatabaseID: "others-db", BuyerName: "Carol", JPYAmount: 1000, TWDAmount: 240,
+		ExpectedArrival: "2026-11-30",
+	}, item)
+}
+
+func TestGetItem_Error(t *testing.T) {
+	page := &mockPageService{
+		getFn: func(context.Context, notionapi.PageID) (*notionapi.Page, error) {
+			return nil, errors.New("api down")
+		},
+	}
+
+	repo := NewItemRepository(page, &mockDatabaseService{}, DefaultSchema())
+	_, err := repo.GetItem(context.Background(), "p1")
+
+	require.ErrorContains(t, err, "notion page get failed")
+}
+
+func TestUpdateItemStatus_Success(t *testing.T) {
+	page := &mockPageService{
+		updateFn: func(
+			_ context.Context, id notionapi.PageID, req *notionapi.PageUpdateRequest,
+		) (*notionapi.Page, error) {
+			require.Equal(t, notionapi.PageID("p1"), id)
+
+			status, ok := req.Properties["物品狀況"].(notionapi.SelectProperty)
+			require.True(t, ok)
+			require.Equal(t, "已回台", status.Select.Name)
+
+			return &notionapi.Page{}, nil
+		},
+	}
+
+	repo := NewItemRepository(page, &mockDatabaseService{}, DefaultSchema())
+	err := repo.UpdateItemStatus(context.Background(), "p1", domain.ItemStatusArrivedTW)
+
+	require.NoError(t, err)
+}
+
+func TestUpdateItemStatus_MappedOption(t *testing.T) {
+	page := &mockPageService{
+		updateFn: func(
+			_ context.Context, _ notionapi.PageID, req *notionapi.PageUpdateRequest,
+		) (*notionapi.Page, error) {
+			status, ok := req.Properties["物品狀況"].(notionapi.SelectProperty)
+			require.True(t, ok)
+			require.Equal(t, "到台", status.Select.Name)
+
+			return &notionapi.Page{}, nil
+		},
+	}
+
+	schema := DefaultSchema()
+	schema.Transactions.ItemArrivedTW = "到台"
+
+	repo := NewItemRepository(page, &mockDatabaseService{}, schema)
+	err := repo.UpdateItemStatus(context.Background(), "p1", domain.ItemStatusArrivedTW)
+
+	require.NoError(t, err)
+}
+
+func TestGetOrderItems(t *testing.T) {
+	db := &mockDatabaseService{
+		queryFn: func(
+			_ context.Context, id notionapi.DatabaseID, req *notionapi.DatabaseQueryRequest,
+		) (*notionapi.DatabaseQueryResponse, error) {
+			require.Equal(t, notionapi.DatabaseID("member-db"), id)
+
+			filter, ok := req.Filter.(notionapi.PropertyFilter)
+			require.True(t, ok)
+			require.Equal(t, "訂單", filter.Property)
+			require.Equal(t, "order-page", filter.Relation.Contains)
+
+			return &notionapi.DatabaseQueryResponse{Results: []notionapi.Page{
+				makeItemPage("p1", "Badge", 240, 1000),
+				makeItemPage("p2", "Acrylic", 480, 2000),
+			}}, nil
+		},
+	}
+
+	repo := NewItemRepository(&mockPageService{}, db, DefaultSchema())
+	items, err := repo.GetOrderItems(context.Background(), "member-db", "order-page")
+
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	require.Equal(t, "p2", items[1].PageID)
+	require.InDelta(t, 2000, items[1].JPYAmount, 0)
+}
+
+func TestGetOrderItems_Error(t *testing.T) {
+	db := &mockDatabaseService{
+		queryFn: func(
+			context.Context, notionapi.DatabaseID, *notionapi.DatabaseQueryRequest,
+		) (*notionapi.DatabaseQueryResponse, error) {
+			return nil, errors.New("api down")
+		},
+	}
+
+	repo := NewItemRepository(&mockPageService{}, db, DefaultSchema())
+	_, err := repo.GetOrderItems(context.Background(), "member-db", "order-page")
+
+	require.ErrorContains(t, err, "notion database query failed")
+}
@@ -0,0 +1,250 @@
+package notion
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+
+	"github.com/xgnid-tw/gx5/domain"
+)
+
+// Schema maps the Notion property names and select values used by the gateway.
+// Any field can be overridden by the schema mapping file read with LoadSchema.
+type Schema struct {
+	Users        UserColumns        `json:"users"`
+	Transactions TransactionColumns `json:"transactions"`
+	Orders       OrderColumns       `json:"orders"`
+}
+
+// UserColumns maps TBL-001.
+type UserColumns struct {
+	DiscordID string `json:"discord_id"`
+	Name      string `json:"name"`
+	NotionID  string `json:"notion_id"`
+	Currency  string `json:"currency"`
+	Threshold string `json:"reminder_threshold" schema:"optional"` // empty disables per-member thresholds
+}
+
+// TransactionColumns maps TBL-002 and TBL-003, which share the same layout.
+type TransactionColumns struct {
+	ItemName        string `json:"item_name"`
+	TWDAmount       string `json:"twd_amount"`
+	JPYAmount       string `json:"jpy_amount"`
+	PaymentStatus   string `json:"payment_status"`
+	Buyer           string `json:"buyer"` // TBL-003 only
+	ItemStatus      string `json:"item_status"`
+	Shop            string `json:"shop"`
+	Link            string `json:"link"`
+	Note            string `json:"note"`
+	ExpectedArrival string `json:"expected_arrival"`
+	Order           string `json:"order"`
+	CreatedTime     string `json:"created_time"`
+	StatusUnpaid    string `json:"status_unpaid"`
+	StatusPaid      string `json:"status_paid"`
+
+	// 物品狀況 select values, translated to and from domain.ItemStatus.
+	ItemNotOrdered   string `json:"item_not_ordered"`
+	ItemOrdered      string `json:"item_ordered"`
+	ItemArrivedJP    string `json:"item_arrived_jp"`
+	ItemArrivedTW    string `json:"item_arrived_tw"`
+	ItemPaidOnBehalf string `json:"item_paid_on_behalf"`
+	ItemShipping     string `json:"item_shipping"`
+}
+
+// OrderColumns maps TBL-004.
+type OrderColumns struct {
+	ThreadName string `json:"thread_name"`
+	Deadline   string `json:"deadline"`
+	Tags       string `json:"tags"`
+	ThreadID   string `json:"thread_id"`
+	ChannelID  string `json:"channel_id"`
+	ShopURL    string `json:"shop_url"`
+	CreatedBy  string `json:"created_by"`
+	Status     string `json:"status"`
+	SummaryID  string `json:"summary_id"`
+
+	// status select values, translated to and from domain.OrderStatus.
+	StatusOpen    string `json:"status_open"`
+	StatusClosed  string `json:"status_closed"`
+	StatusOrdered string `json:"status_ordered"`
+	StatusShipped string `json:"status_shipped"`
+	StatusArrived string `json:"status_arrived"`
+	StatusSettled string `json:"status_settled"`
+}
+
+// DefaultSchema returns the mapping documented in designDocs/defination/tables.
+func DefaultSchema() Schema {
+	return Schema{
+		Users: UserColumns{
+			DiscordID: "discord_id",
+			Name:      "name",
+			NotionID:  "notion_id",
+			Currency:  "currency",
+			Threshold: "reminder_threshold",
+		},
+		Transactions: TransactionColumns{
+			ItemName:        "品項",
+			TWDAmount:       "台幣",
+			JPYAmount:       "日幣",
+			PaymentStatus:   "付款狀況",
+			Buyer:           "購買人",
+			ItemStatus:      "物品狀況",
+			Shop:            "購買途徑",
+			Link:            "連結",
+			Note:            "備註",
+			ExpectedArrival: "預計到貨",
+			Order:           "訂單",
+			CreatedTime:     "建立時間",
+			StatusUnpaid:    "尚未付款",
+			StatusPaid:      "已付款",
+
+			ItemNotOrdered:   string(domain.ItemStatusNotOrdered),
+			ItemOrdered:      string(domain.ItemStatusOrdered),
+			ItemArrivedJP:    string(domain.ItemStatusArrivedJP),
+			ItemArrivedTW:    string(domain.ItemStatusArrivedTW),
+			ItemPaidOnBehalf: string(domain.ItemStatusPaidOnBehalf),
+			ItemShipping:     string(domain.ItemStatusShipping),
+		},
+		Orders: OrderColumns{
+			ThreadName: "threadName",
+			Deadline:   "deadline",
+			Tags:       "tags",
+			ThreadID:   "threadID",
+			ChannelID:  "channelID",
+			ShopURL:    "shopURL",
+			CreatedBy:  "createdBy",
+			Status:     "status",
+			SummaryID:  "summaryMessageID",
+
+			StatusOpen:    string(domain.OrderStatusOpen),
+			StatusClosed:  string(domain.OrderStatusClosed),
+			StatusOrdered: string(domain.OrderStatusOrdered),
+			StatusShipped: string(domain.OrderStatusShipped),
+			StatusArrived: string(domain.OrderStatusArrived),
+			StatusSettled: string(domain.OrderStatusSettled),
+		},
+	}
+}
+
+// itemStatusNames pairs each domain.ItemStatus with its 物品狀況 option in Notion.
+func (c TransactionColumns) itemStatusNames() map[domain.ItemStatus]string {
+	return map[domain.ItemStatus]string{
+		domain.ItemStatusNotOrdered:   c.ItemNotOrdered,
+		domain.ItemStatusOrdered:      c.ItemOrdered,
+		domain.ItemStatusArrivedJP:    c.ItemArrivedJP,
+		domain.ItemStatusArrivedTW:    c.ItemArrivedTW,
+		domain.ItemStatusPaidOnBehalf: c.ItemPaidOnBehalf,
+		domain.ItemStatusShipping:     c.ItemShipping,
+	}
+}
+
+// itemStatusOption returns the 物品狀況 option written for s.
+func (c TransactionColumns) itemStatusOption(s domain.ItemStatus) string {
+	if name, ok := c.itemStatusNames()[s]; ok {
+		return name
+	}
+
+	return string(s)
+}
+
+// itemStatus returns the domain status of a 物品狀況 option. Unmapped options are
+// kept as they are.
+func (c TransactionColumns) itemStatus(option string) domain.ItemStatus {
+	for s, name := range c.itemStatusNames() {
+		if name == option {
+			return s
+		}
+	}
+
+	return domain.ItemStatus(option)
+}
+
+// statusNames pairs each domain.OrderStatus with its status option in Notion.
+func (c OrderColumns) statusNames() map[domain.OrderStatus]string {
+	return map[domain.OrderStatus]string{
+		domain.OrderStatusOpen:    c.StatusOpen,
+		domain.OrderStatusClosed:  c.StatusClosed,
+		domain.OrderStatusOrdered: c.StatusOrdered,
+		domain.OrderStatusShipped: c.StatusShipped,
+		domain.OrderStatusArrived: c.StatusArrived,
+		domain.OrderStatusSettled: c.StatusSettled,
+	}
+}
+
+// statusOption returns the status option written for s.
+func (c OrderColumns) statusOption(s domain.OrderStatus) string {
+	if name, ok := c.statusNames()[s]; ok {
+		return name
+	}
+
+	return string(s)
+}
+
+// status returns the domain status of a status option. Unmapped options are kept
+// as they are.
+func (c OrderColumns) status(option string) domain.OrderStatus {
+	for s, name := range c.statusNames() {
+		if name == option {
+			return s
+		}
+	}
+
+	return domain.OrderStatus(option)
+}
+
+// LoadSchema reads the JSON mapping file at path on top of DefaultSchema, so the
+// file only needs to list the names that differ. An empty path keeps the defaults.
+func LoadSchema(path string) (Schema, error) {
+	schema := DefaultSchema()
+	if path == "" {
+		return schema, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Schema{}, fmt.Errorf("read schema file: %w", err)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+
+	err = dec.Decode(&schema)
+	if err != nil {
+		return Schema{}, fmt.Errorf("parse schema file: %w", err)
+	}
+
+	err = schema.Validate()
+	if err != nil {
+		return Schema{}, fmt.Errorf("invalid schema file: %w", err)
+	}
+
+	return schema, nil
+}
+
+// Validate reports the first required mapping entry that is empty. Entries tagged
+// schema:"optional" may be emptied to leave their column out.
+func (s Schema) Validate() error {
+	v := reflect.ValueOf(s)
+
+	for i := range v.NumField() {
+		section := v.Field(i)
+
+		for j := range section.NumField() {
+			field := section.Type().Field(j)
+			if field.Tag.Get("schema") == "optional" {
+				continue
+			}
+
+			if section.Field(j).String() == "" {
+				return fmt.Errorf(
+					"%s.%s must not be empty",
+					v.Type().Field(i).Tag.Get("json"), field.Tag.Get("json"),
+				)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package notion
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/xgnid-tw/gx5/domain"
+)
+
+func writeSchemaFile(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "schema.json")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	return path
+}
+
+func TestLoadSchema_NoFileUsesDefault(t *testing.T) {
+	got, err := LoadSchema("")
+
+	require.NoError(t, err)
+	require.Equal(t, DefaultSchema(), got)
+}
+
+func TestLoadSchema_OverridesOnlyGivenFields(t *testing.T) {
+	path := writeSchemaFile(t, `{
+		"transactions": {"payment_status": "狀態", "status_unpaid": "未付"},
+		"orders": {"tags": "系列"}
+	}`)
+
+	got, err := LoadSchema(path)
+
+	require.NoError(t, err)
+	require.Equal(t, "狀態", got.Transactions.PaymentStatus)
+	require.Equal(t, "未付", got.Transactions.StatusUnpaid)
+	require.Equal(t, "系列", got.Orders.Tags)
+	require.Equal(t, "品項", got.Transactions.ItemName)
+	require.Equal(t, "discord_id", got.Users.DiscordID)
+}
+
+func TestLoadSchema_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"invalid json", `{"users": `, "parse schema file"},
+		{"unknown key", `{"users": {"discord": "id"}}`, "parse schema file"},
+		{"empty value", `{"users": {"name": ""}}`, "users.name must not be empty"},
+		{"empty buyer", `{"transactions": {"buyer": ""}}`, "transactions.buyer must not be empty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadSchema(writeSchemaFile(t, tt.content))
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestLoadSchema_MissingFile(t *testing.T) {
+	_, err := LoadSchema(filepath.Join(t.TempDir(), "missing.json"))
+
+	require.ErrorContains(t, err, "read schema file")
+}
+
+func TestLoadSchema_OptionalFieldMayBeEmpty(t *testing.T) {
+	got, err := LoadSchema(writeSchemaFile(t, `{"users": {"reminder_threshold": ""}}`))
+
+	require.NoError(t, err)
+	require.Empty(t, got.Users.Threshold)
+}
+
+func TestTransactionColumns_ItemStatus(t *testing.T) {
+	cols := DefaultSchema().Transactions
+	cols.ItemOrdered = "訂了"
+
+	require.Equal(t, "訂了", cols.itemStatusOption(domain.ItemStatusOrdered))
+	require.Equal(t, domain.ItemStatusOrdered, cols.itemStatus("訂了"))
+	require.Equal(t, domain.ItemStatusArrivedTW, cols.itemStatus("已回台"))
+	require.Equal(t, domain.ItemStatus("不明"), cols.itemStatus("不明"))
+	require.Equal(t, domain.ItemStatus(""), cols.itemStatus(""))
+}
+
+func TestOrderColumns_Status(t *testing.T) {
+	cols := DefaultSchema().Orders
+	cols.StatusOpen = "募集中"
+
+	require.Equal(t, "募集中", cols.statusOption(domain.OrderStatusOpen))
+	require.Equal(t, domain.OrderStatusOpen, cols.status("募集中"))
+	require.Equal(t, domain.OrderStatusSettled, cols.status("已結清"))
+	require.Equal(t, []string{"募集中", "已截止", "已下訂", "已出貨", "已到貨", "已結清"}, cols.statusOptions())
+}
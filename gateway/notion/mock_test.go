@@ -2,6 +2,8 @@ package notion
 
 import (
 	"context"
+	"fmt"
+	"strconv"
 
 	"github.com/jomei/notionapi"
 )
@@ -10,6 +12,7 @@ type mockDatabaseService struct {
 	queryFn func(
 		ctx context.Context, id notionapi.DatabaseID, req *notionapi.DatabaseQueryRequest,
 	) (*notionapi.DatabaseQueryResponse, error)
+	getFn func(ctx context.Context, id notionapi.DatabaseID) (*notionapi.Database, error)
 }
 
 func (m *mockDatabaseService) Query(
@@ -25,9 +28,9 @@ func (m *mockDatabaseService) Create(
 }
 
 func (m *mockDatabaseService) Get(
-	context.Context, notionapi.DatabaseID,
+	ctx context.Context, id notionapi.DatabaseID,
 ) (*notionapi.Database, error) {
-	panic("not implemented")
+	return m.getFn(ctx, id)
 }
 
 func (m *mockDatabaseService) Update(
@@ -36,11 +39,41 @@ func (m *mockDatabaseService) Update(
 	panic("not implemented")
 }
 
+// pagedQueryFn returns a queryFn that serves each batch as a separate page of
+// results, chaining them with cursors of the form "cursor-<n>".
+func pagedQueryFn(batches ...[]notionapi.Page) func(
+	context.Context, notionapi.DatabaseID, *notionapi.DatabaseQueryRequest,
+) (*notionapi.DatabaseQueryResponse, error) {
+	return func(
+		_ context.Context, _ notionapi.DatabaseID, req *notionapi.DatabaseQueryRequest,
+	) (*notionapi.DatabaseQueryResponse, error) {
+		idx := 0
+
+		if req.StartCursor != "" {
+			n, err := strconv.Atoi(string(req.StartCursor[len("cursor-"):]))
+			if err != nil {
+				return nil, fmt.Errorf("unexpected cursor %q", req.StartCursor)
+			}
+
+			idx = n
+		}
+
+		res := &notionapi.DatabaseQueryResponse{Results: batches[idx]}
+		if idx+1 < len(batches) {
+			res.HasMore = true
+			res.NextCursor = notionapi.Cursor(fmt.Sprintf("cursor-%d", idx+1))
+		}
+
+		return res, nil
+	}
+}
+
 func newTestRepository(db notionapi.DatabaseService, userDBID string) *Repository {
 	return &Repository{
 		db:         db,
 		userDBID:   notionapi.DatabaseID(userDBID),
 		othersDBID: notionapi.DatabaseID("others-db"),
+		schema:     DefaultSchema(),
 	}
 }
 
@@ -71,6 +104,8 @@ func makeUserPage(discordID, name, notionID, currency string) notionapi.Page {
 
 type mockPageService struct {
 	createFn func(ctx context.Context, req *notionapi.PageCreateRequest) (*notionapi.Page, error)
+	getFn    func(ctx context.Context, id notionapi.PageID) (*notionapi.Page, error)
+	updateFn func(ctx context.Context, id notionapi.PageID, req *notionapi.PageUpdateRequest) (*notionapi.Page, error)
 }
 
 func (m *mockPageService) Create(
@@ -79,14 +114,14 @@ func (m *mockPageService) Create(
 	return m.createFn(ctx, req)
 }
 
-func (m *mockPageService) Get(context.Context, notionapi.PageID) (*notionapi.Page, error) {
-	panic("not implemented")
+func (m *mockPageService) Get(ctx context.Context, id notionapi.PageID) (*notionapi.Page, error) {
+	return m.getFn(ctx, id)
 }
 
 func (m *mockPageService) Update(
-	context.Context, notionapi.PageID, *notionapi.PageUpdateRequest,
+	ctx context.Context, id notionapi.PageID, req *notionapi.PageUpdateRequest,
 ) (*notionapi.Page, error) {
-	panic("not implemented")
+	return m.updateFn(ctx, id, req)
 }
 
 func makeAmountPage(column string, amount float64) notionapi.Page {
@@ -96,3 +131,16 @@ func makeAmountPage(column string, amount float64) notionapi.Page {
 		},
 	}
 }
+
+func makeItemPage(id string, name string, twd float64, jpy float64) notionapi.Page {
+	return notionapi.Page{
+		ID: notionapi.ObjectID(id),
+		Properties: notionapi.Properties{
+			"品項": &notionapi.TitleProperty{
+				Title: []notionapi.RichText{{Text: &notionapi.Text{Content: name}}},
+			},
+			"台幣": &notionapi.NumberProperty{Number: twd},
+			"日幣": &notionapi.NumberProperty{Number: jpy},
+		},
+	}
+}
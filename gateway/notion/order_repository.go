@@ -8,24 +8,33 @@ import (
 	"github.com/jomei/notionapi"
 
 	"github.com/xgnid-tw/gx5/domain"
+	"github.com/xgnid-tw/gx5/port"
 )
 
+const orderDateLayout = "2006-01-02"
+
 // OrderRepository implements port.OrderRepository using the Notion API.
 type OrderRepository struct {
 	page      notionapi.PageService
+	db        notionapi.DatabaseService
 	orderDBID notionapi.DatabaseID
+	cols      OrderColumns
 }
 
-func NewOrderRepository(page notionapi.PageService, orderDBID string) *OrderRepository {
+func NewOrderRepository(
+	page notionapi.PageService, db notionapi.DatabaseService, orderDBID string, schema Schema,
+) *OrderRepository {
 	return &OrderRepository{
 		page:      page,
+		db:        db,
 		orderDBID: notionapi.DatabaseID(orderDBID),
+		cols:      schema.Orders,
 	}
 }
 
 func (r *OrderRepository) CreateOrder(ctx context.Context, order domain.Order) error {
 	props := notionapi.Properties{
-		"threadName": notionapi.TitleProperty{
+		r.cols.ThreadName: notionapi.TitleProperty{
 			Title: []notionapi.RichText{
 				{Text: &notionapi.Text{Content: order.ThreadName}},
 			},
@@ -33,23 +42,44 @@ func (r *OrderRepository) CreateOrder(ctx context.Context, order domain.Order) e
 	}
 
 	if order.Deadline != "" {
-		t, err := time.Parse("2006-01-02", order.Deadline)
+		t, err := time.Parse(orderDateLayout, order.Deadline)
 		if err != nil {
 			return fmt.Errorf("invalid deadline format: %w", err)
 		}
 
 		d := notionapi.Date(t)
-		props["deadline"] = notionapi.DateProperty{
+		props[r.cols.Deadline] = notionapi.DateProperty{
 			Date: &notionapi.DateObject{Start: &d},
 		}
 	}
 
 	if order.Tag != "" {
-		props["tags"] = notionapi.SelectProperty{
+		props[r.cols.Tags] = notionapi.SelectProperty{
 			Select: notionapi.Option{Name: string(order.Tag)},
 		}
 	}
 
+	if order.Status != "" {
+		props[r.cols.Status] = notionapi.SelectProperty{
+			Select: notionapi.Option{Name: r.cols.statusOption(order.Status)},
+		}
+	}
+
+	if order.ShopURL != "" {
+		props[r.cols.ShopURL] = notionapi.URLProperty{URL: order.ShopURL}
+	}
+
+	for col, v := range map[string]string{
+		r.cols.ThreadID:  order.ThreadID,
+		r.cols.ChannelID: order.ChannelID,
+		r.cols.CreatedBy: order.CreatedBy,
+		r.cols.SummaryID: order.SummaryID,
+	} {
+		if v != "" {
+			props[col] = richTextProperty(v)
+		}
+	}
+
 	_, err := r.page.Create(ctx, &notionapi.PageCreateRequest{
 		Parent: notionapi.Parent{
 			DatabaseID: r.orderDBID,
@@ -62,3 +92,96 @@ func (r *OrderRepository) CreateOrder(ctx context.Context, order domain.Order) e
 
 	return nil
 }
+
+func (r *OrderRepository) GetOrderByThreadID(ctx context.Context, threadID string) (*domain.Order, error) {
+	res, err := r.db.Query(ctx, r.orderDBID, &notionapi.DatabaseQueryRequest{
+		Filter: &notionapi.PropertyFilter{
+			Property: r.cols.ThreadID,
+			RichText: &notionapi.TextFilterCondition{Equals: threadID},
+		},
+		PageSize: 1,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("notion database query failed: %w", err)
+	}
+
+	if len(res.Results) == 0 {
+		return nil, fmt.Errorf("%w: thread %s", port.ErrOrderNotFound, threadID)
+	}
+
+	return r.toOrder(res.Results[0]), nil
+}
+
+// GetOpenOrders returns every order whose status is 開放中 or still unset.
+func (r *OrderRepository) GetOpenOrders(ctx context.Context) ([]domain.Order, error) {
+	pages, err := queryAll(ctx, r.db, r.orderDBID, &notionapi.DatabaseQueryRequest{
+		Filter: notionapi.OrCompoundFilter{
+			notionapi.PropertyFilter{
+				Property: r.cols.Status,
+				Select:   &notionapi.SelectFilterCondition{Equals: r.cols.StatusOpen},
+			},
+			notionapi.PropertyFilter{
+				Property: r.cols.Status,
+				Select:   &notionapi.SelectFilterCondition{IsEmpty: true},
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	orders := make([]domain.Order, 0, len(pages))
+	for _, p := range pages {
+		orders = append(orders, *r.toOrder(p))
+	}
+
+	return orders, nil
+}
+
+func (r *OrderRepository) UpdateOrderStatus(ctx context.Context, pageID string, status domain.OrderStatus) error {
+	_, err := r.page.Update(ctx, notionapi.PageID(pageID), &notionapi.PageUpdateRequest{
+		Properties: notionapi.Properties{
+			r.cols.Status: notionapi.SelectProperty{
+				Select: notionapi.Option{Name: r.cols.statusOption(status)},
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("notion page update failed for %s: %w", pageID, err)
+	}
+
+	return nil
+}
+
+func (r *OrderRepository) toOrder(p notionapi.Page) *domain.Order {
+	order := &domain.Order{PageID: string(p.ID)}
+
+	order.ThreadName, _ = getTitleContent(p.Properties[r.cols.ThreadName])
+	order.ThreadID, _ = getRichTextContent(p.Properties[r.cols.ThreadID])
+	order.ChannelID, _ = getRichTextContent(p.Properties[r.cols.ChannelID])
+	order.CreatedBy, _ = getRichTextContent(p.Properties[r.cols.CreatedBy])
+	order.SummaryID, _ = getRichTextContent(p.Properties[r.cols.SummaryID])
+	order.ShopURL, _ = getURLContent(p.Properties[r.cols.ShopURL])
+
+	if tag, ok := getSelectContent(p.Properties[r.cols.Tags]); ok {
+		order.Tag = domain.Tag(tag)
+	}
+
+	if st, ok := getSelectContent(p.Properties[r.cols.Status]); ok {
+		order.Status = r.cols.status(st)
+	}
+
+	if d, ok := getDateContent(p.Properties[r.cols.Deadline]); ok {
+		order.Deadline = d.Format(orderDateLayout)
+	}
+
+	return order
+}
+
+func richTextProperty(content string) notionapi.RichTextProperty {
+	return notionapi.RichTextProperty{
+		RichText: []notionapi.RichText{
+			{Text: &notionapi.Text{Content: content}},
+		},
+	}
+}
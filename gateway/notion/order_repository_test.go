@@ -0,0 +1,224 @@
+package notion
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jomei/notionapi"
+	"github.com/stretchr/testify/require"
+
+	"github.com/xgnid-tw/gx5/domain"
+	"github.com/xgnid-tw/gx5/port"
+)
+
+func TestCreateOrder_PersistsThread(t *testing.T) {
+	var capturedReq *notionapi.PageCreateRequest
+
+	page := &mockPageService{
+		createFn: func(_ context.Context, req *notionapi.PageCreateRequest) (*notionapi.Page, error) {
+			capturedReq = req
+			return &notionapi.Page{}, nil
+		},
+	}
+
+	repo := NewOrderRepository(page, &mockDatabaseService{}, "order-db", DefaultSchema())
+	err := repo.CreateOrder(context.Background(), domain.Order{
+		ThreadName: "test order",
+		Deadline:   "2026-04-01",
+		Tag:        domain.Tag315Pro,
+		ShopURL:    "https://shop.example.com",
+		ThreadID:   "thread-1",
+		ChannelID:  "ch-1",
+		CreatedBy:  "999",
+		Status:     domain.OrderStatusOpen,
+		SummaryID:  "msg-1",
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, notionapi.DatabaseID("order-db"), capturedReq.Parent.DatabaseID)
+
+	status, ok := capturedReq.Properties["status"].(notionapi.SelectProperty)
+	require.True(t, ok)
+	require.Equal(t, "開放中", status.Select.Name)
+
+	shop, ok := capturedReq.Properties["shopURL"].(notionapi.URLProperty)
+	require.True(t, ok)
+	require.Equal(t, "https://shop.example.com", shop.URL)
+
+	for col, want := range map[string]string{
+		"threadID": "thread-1", "channelID": "ch-1", "createdBy": "999", "summaryMessageID": "msg-1",
+	} {
+		rt, ok := capturedReq.Properties[col].(notionapi.RichTextProperty)
+		require.True(t, ok, col)
+		require.Equal(t, want, rt.RichText[0].Text.Content, col)
+	}
+}
+
+func TestCreateOrder_InvalidDeadline(t *testing.T) {
+	repo := NewOrderRepository(&mockPageService{}, &mockDatabaseService{}, "order-db", DefaultSchema())
+	err := repo.CreateOrder(context.Background(), domain.Order{ThreadName: "test order", Deadline: "4/1"})
+
+	require.ErrorContains(t, err, "invalid deadline format")
+}
+
+func TestGetOrderByThreadID_Success(t *testing.T) {
+	deadline := notionapi.Date(time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC))
+
+	db := &mockDatabaseService{
+		queryFn: func(
+			_ context.Context, id notionapi.DatabaseID, req *notionapi.DatabaseQueryRequest,
+		) (*notionapi.DatabaseQueryResponse, error) {
+			require.Equal(t, notionapi.DatabaseID("order-db"), id)
+
+			filter, ok := req.Filter.(*notionapi.PropertyFilter)
+			require.True(t, ok)
+			require.Equal(t, "threadID", filter.Property)
+			require.Equal(t, "thread-1", filter.RichText.Equals)
+
+			return &notionapi.DatabaseQueryResponse{Results: []notionapi.Page{{
+				ID: "order-page",
+				Properties: notionapi.Properties{
+					"threadName": &notionapi.TitleProperty{
+						Title: []notionapi.RichText{{Text: &notionapi.Text{Content: "test order"}}},
+					},
+					"deadline":  &notionapi.DateProperty{Date: &notionapi.DateObject{Start: &deadline}},
+					"tags":      &notionapi.SelectProperty{Select: notionapi.Option{Name: "315pro"}},
+					"status":    &notionapi.SelectProperty{Select: notionapi.Option{Name: "已截止"}},
+					"shopURL":   &notionapi.URLProperty{URL: "https://shop.example.com"},
+					"threadID":  &notionapi.RichTextProperty{RichText: []notionapi.RichText{{Text: &notionapi.Text{Content: "thread-1"}}}},
+					"channelID": &notionapi.RichTextProperty{RichText: []notionapi.RichText{{Text: &notionapi.Text{Content: "ch-1"}}}},
+					"createdBy": &notionapi.RichTextProperty{RichText: []notionapi.RichText{{Text: &notionapi.Text{Content: "999"}}}},
+					"summaryMessageID": &notionapi.RichTextProperty{
+						RichText: []notionapi.RichText{{Text: &notionapi.Text{Content: "msg-1"}}},
+					},
+				},
+			}}}, nil
+		},
+	}
+
+	repo := NewOrderRepository(&mockPageService{}, db, "order-db", DefaultSchema())
+	order, err := repo.GetOrderByThreadID(context.Background(), "thread-1")
+
+	require.NoError(t, err)
+	require.Equal(t, &domain.Order{
+		PageID:     "order-page",
+		ThreadName: "test order",
+		Deadline:   "2026-04-01",
+		Tag:        domain.Tag315Pro,
+		ShopURL:    "https://shop.example.com",
+		ThreadID:   "thread-1",
+		ChannelID:  "ch-1",
+		CreatedBy:  "999",
+		Status:     domain.OrderStatusClosed,
+		SummaryID:  "msg-1",
+	}, order)
+}
+
+func TestGetOrderByThreadID_NotFound(t *testing.T) {
+	db := &mockDatabaseService{
+		queryFn: func(
+			context.Context, notionapi.DatabaseID, *notionapi.DatabaseQueryRequest,
+		) (*notionapi.DatabaseQueryResponse, error) {
+			return &notionapi.DatabaseQueryResponse{}, nil
+		},
+	}
+
+	repo := NewOrderRepository(&mockPageService{}, db, "order-db", DefaultSchema())
+	_, err := repo.GetOrderByThreadID(context.Background(), "thread-1")
+
+	require.ErrorIs(t, err, port.ErrOrderNotFound)
+}
+
+func TestGetOrderByThreadID_QueryError(t *testing.T) {
+	db := &mockDatabaseService{
+		queryFn: func(
+			context.Context, notionapi.DatabaseID, *notionapi.DatabaseQueryRequest,
+		) (*notionapi.DatabaseQueryResponse, error) {
+			return nil, errors.New("api down")
+		},
+	}
+
+	repo := NewOrderRepository(&mockPageService{}, db, "order-db", DefaultSchema())
+	_, err := repo.GetOrderByThreadID(context.Background(), "thread-1")
+
+	require.ErrorContains(t, err, "notion database query failed")
+}
+
+func TestUpdateOrderStatus(t *testing.T) {
+	var (
+		capturedID  notionapi.PageID
+		capturedReq *notionapi.PageUpdateRequest
+	)
+
+	page := &mockPageService{
+		updateFn: func(
+			_ context.Context, id notionapi.PageID, req *notionapi.PageUpdateRequest,
+		) (*notionapi.Page, error) {
+			capturedID, capturedReq = id, req
+			return &notionapi.Page{}, nil
+		},
+	}
+
+	repo := NewOrderRepository(page, &mockDatabaseService{}, "order-db", DefaultSchema())
+	err := repo.UpdateOrderStatus(context.Background(), "order-page", domain.OrderStatusShipped)
+
+	require.NoError(t, err)
+	require.Equal(t, notionapi.PageID("order-page"), capturedID)
+
+	status, ok := capturedReq.Properties["status"].(notionapi.SelectProperty)
+	require.True(t, ok)
+	require.Equal(t, "已出貨", status.Select.Name)
+}
+
+func TestUpdateOrderStatus_Error(t *testing.T) {
+	page := &mockPageService{
+		updateFn: func(
+			context.Context, notionapi.PageID, *notionapi.PageUpdateRequest,
+		) (*notionapi.Page, error) {
+			return nil, errors.New("api down")
+		},
+	}
+
+	repo := NewOrderRepository(page, &mockDatabaseService{}, "order-db", DefaultSchema())
+	err := repo.UpdateOrderStatus(context.Background(), "order-page", domain.OrderStatusShipped)
+
+	require.ErrorContains(t, err, "notion page update failed for order-page")
+}
+
+func TestGetOpenOrders(t *testing.T) {
+	var calls int
+
+	db := &mockDatabaseService{
+		queryFn: func(
+			_ context.Context, _ notionapi.DatabaseID, req *notionapi.DatabaseQueryRequest,
+		) (*notionapi.DatabaseQueryResponse, error) {
+			calls++
+
+			filter, ok := req.Filter.(notionapi.OrCompoundFilter)
+			require.True(t, ok)
+			require.Len(t, filter, 2)
+			require.Equal(t, "開放中", filter[0].(notionapi.PropertyFilter).Select.Equals)
+			require.True(t, filter[1].(notionapi.PropertyFilter).Select.IsEmpty)
+
+			if req.StartCursor == "" {
+				return &notionapi.DatabaseQueryResponse{
+					Results:    []notionapi.Page{{ID: "order-1"}},
+					HasMore:    true,
+					NextCursor: "next",
+				}, nil
+			}
+
+			return &notionapi.DatabaseQueryResponse{Results: []notionapi.Page{{ID: "order-2"}}}, nil
+		},
+	}
+
+	repo := NewOrderRepository(&mockPageService{}, db, "order-db", DefaultSchema())
+	orders, err := repo.GetOpenOrders(context.Background())
+
+	require.NoError(t, err)
+	require.Equal(t, 2, calls)
+	require.Len(t, orders, 2)
+	require.Equal(t, "order-2", orders[1].PageID)
+}
@@ -0,0 +1,47 @@
+package notion
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jomei/notionapi"
+)
+
+// PaymentRepository implements port.PaymentRepository using the Notion API.
+type PaymentRepository struct {
+	page notionapi.PageService
+	cols TransactionColumns
+}
+
+func NewPaymentRepository(page notionapi.PageService, schema Schema) *PaymentRepository {
+	return &PaymentRepository{page: page, cols: schema.Transactions}
+}
+
+// MarkPaid updates every page even if some fail, and reports all failures together
+// along with the pages that were updated.
+func (r *PaymentRepository) MarkPaid(ctx context.Context, pageIDs []string) ([]string, error) {
+	var (
+		updated []string
+		errs    []error
+	)
+
+	for _, id := range pageIDs {
+		_, err := r.page.Update(ctx, notionapi.PageID(id), &notionapi.PageUpdateRequest{
+			Properties: notionapi.Properties{
+				r.cols.PaymentStatus: notionapi.SelectProperty{
+					Type:   notionapi.PropertyTypeSelect,
+					Select: notionapi.Option{Name: r.cols.StatusPaid},
+				},
+			},
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("notion page update failed for %s: %w", id, err))
+			continue
+		}
+
+		updated = append(updated, id)
+	}
+
+	return updated, errors.Join(errs...)
+}
@@ -0,0 +1,59 @@
+package notion
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jomei/notionapi"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarkPaid_Success(t *testing.T) {
+	var updated []notionapi.PageID
+
+	page := &mockPageService{
+		updateFn: func(
+			_ context.Context, id notionapi.PageID, req *notionapi.PageUpdateRequest,
+		) (*notionapi.Page, error) {
+			updated = append(updated, id)
+
+			status, ok := req.Properties["付款狀況"].(notionapi.SelectProperty)
+			require.True(t, ok)
+			require.Equal(t, "已付款", status.Select.Name)
+
+			return &notionapi.Page{}, nil
+		},
+	}
+
+	repo := NewPaymentRepository(page, DefaultSchema())
+	got, err := repo.MarkPaid(context.Background(), []string{"p1", "p2"})
+
+	require.NoError(t, err)
+	require.Equal(t, []notionapi.PageID{"p1", "p2"}, updated)
+	require.Equal(t, []string{"p1", "p2"}, got)
+}
+
+func TestMarkPaid_ContinuesAfterFailure(t *testing.T) {
+	var updated []notionapi.PageID
+
+	page := &mockPageService{
+		updateFn: func(
+			_ context.Context, id notionapi.PageID, _ *notionapi.PageUpdateRequest,
+		) (*notionapi.Page, error) {
+			updated = append(updated, id)
+			if id == "p1" {
+				return nil, errors.New("api down")
+			}
+
+			return &notionapi.Page{}, nil
+		},
+	}
+
+	repo := NewPaymentRepository(page, DefaultSchema())
+	got, err := repo.MarkPaid(context.Background(), []string{"p1", "p2"})
+
+	require.ErrorContains(t, err, "notion page update failed for p1")
+	require.Equal(t, []notionapi.PageID{"p1", "p2"}, updated)
+	require.Equal(t, []string{"p2"}, got)
+}
@@ -0,0 +1,35 @@
+package notion
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jomei/notionapi"
+)
+
+// queryAll runs a database query and follows NextCursor until Notion reports
+// no more results, returning the pages from every response in order.
+func queryAll(
+	ctx context.Context, db notionapi.DatabaseService,
+	id notionapi.DatabaseID, req *notionapi.DatabaseQueryRequest,
+) ([]notionapi.Page, error) {
+	// Copy the request so the caller's cursor is never mutated.
+	q := *req
+
+	var pages []notionapi.Page
+
+	for {
+		res, err := db.Query(ctx, id, &q)
+		if err != nil {
+			return nil, fmt.Errorf("notion database query failed: %w", err)
+		}
+
+		pages = append(pages, res.Results...)
+
+		if !res.HasMore || res.NextCursor == "" {
+			return pages, nil
+		}
+
+		q.StartCursor = res.NextCursor
+	}
+}
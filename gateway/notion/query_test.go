@@ -0,0 +1,105 @@
+package notion
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jomei/notionapi"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryAll_SinglePage(t *testing.T) {
+	calls := 0
+	db := &mockDatabaseService{
+		queryFn: func(
+			context.Context, notionapi.DatabaseID, *notionapi.DatabaseQueryRequest,
+		) (*notionapi.DatabaseQueryResponse, error) {
+			calls++
+
+			return &notionapi.DatabaseQueryResponse{
+				Results: []notionapi.Page{makeAmountPage("台幣", 100)},
+			}, nil
+		},
+	}
+
+	pages, err := queryAll(context.Background(), db, "db", &notionapi.DatabaseQueryRequest{})
+
+	require.NoError(t, err)
+	require.Len(t, pages, 1)
+	require.Equal(t, 1, calls)
+}
+
+func TestQueryAll_FollowsCursor(t *testing.T) {
+	var cursors []notionapi.Cursor
+
+	paged := pagedQueryFn(
+		[]notionapi.Page{makeAmountPage("台幣", 1), makeAmountPage("台幣", 2)},
+		[]notionapi.Page{makeAmountPage("台幣", 3)},
+		[]notionapi.Page{makeAmountPage("台幣", 4)},
+	)
+	db := &mockDatabaseService{
+		queryFn: func(
+			ctx context.Context, id notionapi.DatabaseID, req *notionapi.DatabaseQueryRequest,
+		) (*notionapi.DatabaseQueryResponse, error) {
+			cursors = append(cursors, req.StartCursor)
+			return paged(ctx, id, req)
+		},
+	}
+
+	req := &notionapi.DatabaseQueryRequest{}
+	pages, err := queryAll(context.Background(), db, "db", req)
+
+	require.NoError(t, err)
+	require.Len(t, pages, 4)
+	require.Equal(t, []notionapi.Cursor{"", "cursor-1", "cursor-2"}, cursors)
+	require.Empty(t, req.StartCursor, "caller request must not be mutated")
+}
+
+func TestQueryAll_KeepsFilterAcrossPages(t *testing.T) {
+	filter := &notionapi.PropertyFilter{
+		Property: "付款狀況",
+		Select:   &notionapi.SelectFilterCondition{Equals: "尚未付款"},
+	}
+
+	paged := pagedQueryFn(
+		[]notionapi.Page{makeAmountPage("台幣", 1)},
+		[]notionapi.Page{makeAmountPage("台幣", 2)},
+	)
+	db := &mockDatabaseService{
+		queryFn: func(
+			ctx context.Context, id notionapi.DatabaseID, req *notionapi.DatabaseQueryRequest,
+		) (*notionapi.DatabaseQueryResponse, error) {
+			require.Equal(t, filter, req.Filter)
+			return paged(ctx, id, req)
+		},
+	}
+
+	pages, err := queryAll(context.Background(), db, "db", &notionapi.DatabaseQueryRequest{Filter: filter})
+
+	require.NoError(t, err)
+	require.Len(t, pages, 2)
+}
+
+func TestQueryAll_ErrorOnLaterPage(t *testing.T) {
+	db := &mockDatabaseService{
+		queryFn: func(
+			_ context.Context, _ notionapi.DatabaseID, req *notionapi.DatabaseQueryRequest,
+		) (*notionapi.DatabaseQueryResponse, error) {
+			if req.StartCursor != "" {
+				return nil, errors.New("api down")
+			}
+
+			return &notionapi.DatabaseQueryResponse{
+				Results:    []notionapi.Page{makeAmountPage("台幣", 1)},
+				HasMore:    true,
+				NextCursor: "cursor-1",
+			}, nil
+		},
+	}
+
+	_, err := queryAll(context.Background(), db, "db", &notionapi.DatabaseQueryRequest{})
+
+	require.Error(t, err)
+	require.ErrorContains(t, err, "notion database query failed")
+}
@@ -0,0 +1,103 @@
+package notion
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultRequestsPerSecond is Notion's documented average request budget per integration.
+const DefaultRequestsPerSecond = 3
+
+// Limiter spaces requests evenly so that all goroutines sharing it stay within
+// a fixed request rate. It can also be paused when Notion asks for a cool-down.
+type Limiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+func NewLimiter(perSecond int) *Limiter {
+	return &Limiter{interval: time.Second / time.Duration(perSecond)}
+}
+
+// Wait blocks until the caller may issue its next request or ctx is done.
+func (l *Limiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+
+	now := time.Now()
+
+	at := l.next
+	if at.Before(now) {
+		at = now
+	}
+
+	l.next = at.Add(l.interval)
+	l.mu.Unlock()
+
+	return sleepCtx(ctx, at.Sub(now))
+}
+
+// PauseUntil holds back every request that has not started yet until t.
+func (l *Limiter) PauseUntil(t time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if t.After(l.next) {
+		l.next = t
+	}
+}
+
+// RetryAfterTransport is an http.RoundTripper that reports the Retry-After
+// header of 429 responses to a Limiter, since notionapi does not expose it.
+type RetryAfterTransport struct {
+	next    http.RoundTripper
+	limiter *Limiter
+}
+
+func NewRetryAfterTransport(next http.RoundTripper, limiter *Limiter) *RetryAfterTransport {
+	return &RetryAfterTransport{next: next, limiter: limiter}
+}
+
+func (t *RetryAfterTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	res, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode == http.StatusTooManyRequests {
+		if d, ok := parseRetryAfter(res.Header.Get("Retry-After")); ok {
+			t.limiter.PauseUntil(time.Now().Add(d))
+		}
+	}
+
+	return res, nil
+}
+
+// parseRetryAfter reads a Retry-After value in delta-seconds, the only form Notion sends.
+func parseRetryAfter(v string) (time.Duration, bool) {
+	secs, err := strconv.Atoi(v)
+	if err != nil || secs < 0 {
+		return 0, false
+	}
+
+	return time.Duration(secs) * time.Second, true
+}
+
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
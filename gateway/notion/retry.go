@@ -0,0 +1,224 @@
+package notion
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"time"
+
+	"github.com/jomei/notionapi"
+
+	"github.com/xgnid-tw/gx5/port"
+)
+
+const (
+	defaultMaxAttempts = 5
+	defaultBaseDelay   = 500 * time.Millisecond
+	defaultMaxDelay    = 10 * time.Second
+)
+
+// retrier runs Notion calls through a shared Limiter and retries failures
+// that Notion documents as transient.
+type retrier struct {
+	limiter     *Limiter
+	maxAttempts int
+	baseDelay   time.Duration
+	maxDelay    time.Duration
+}
+
+func newRetrier(limiter *Limiter) retrier {
+	return retrier{
+		limiter:     limiter,
+		maxAttempts: defaultMaxAttempts,
+		baseDelay:   defaultBaseDelay,
+		maxDelay:    defaultMaxDelay,
+	}
+}
+
+// do calls fn until it succeeds, fails permanently or runs out of attempts.
+// Server errors are only retried when idempotent is set, because a 5xx on a
+// write may still have been applied; rate-limit rejections are always safe to retry.
+func (r retrier) do(ctx context.Context, idempotent bool, fn func() error) error {
+	var err error
+
+	for attempt := range r.maxAttempts {
+		if attempt > 0 {
+			sleepErr := sleepCtx(ctx, r.backoff(attempt))
+			if sleepErr != nil {
+				return sleepErr
+			}
+		}
+
+		waitErr := r.limiter.Wait(ctx)
+		if waitErr != nil {
+			return waitErr
+		}
+
+		err = fn()
+		if err == nil {
+			return nil
+		}
+
+		if !isRateLimited(err) && (!idempotent || !isServerError(err)) {
+			return err
+		}
+	}
+
+	if isRateLimited(err) {
+		return fmt.Errorf("%w: %w", port.ErrRateLimited, err)
+	}
+
+	return err
+}
+
+// backoff returns a full-jitter exponential delay for the given retry attempt.
+func (r retrier) backoff(attempt int) time.Duration {
+	d := r.baseDelay << (attempt - 1)
+	if d <= 0 || d > r.maxDelay {
+		d = r.maxDelay
+	}
+
+	return time.Duration(rand.Int64N(int64(d)) + 1)
+}
+
+func isRateLimited(err error) bool {
+	var rle *notionapi.RateLimitedError
+	if errors.As(err, &rle) {
+		return true
+	}
+
+	var apiErr *notionapi.Error
+
+	return errors.As(err, &apiErr) && apiErr.Status == http.StatusTooManyRequests
+}
+
+func isServerError(err error) bool {
+	var apiErr *notionapi.Error
+
+	return errors.As(err, &apiErr) && apiErr.Status >= http.StatusInternalServerError
+}
+
+// RetryingDatabaseService decorates a notionapi.DatabaseService with throttling and retries.
+type RetryingDatabaseService struct {
+	next notionapi.DatabaseService
+	r    retrier
+}
+
+func NewRetryingDatabaseService(next notionapi.DatabaseService, limiter *Limiter) *RetryingDatabaseService {
+	return &RetryingDatabaseService{next: next, r: newRetrier(limiter)}
+}
+
+func (s *RetryingDatabaseService) Create(
+	ctx context.Context, req *notionapi.DatabaseCreateRequest,
+) (*notionapi.Database, error) {
+	var res *notionapi.Database
+
+	err := s.r.do(ctx, false, func() error {
+		var err error
+		res, err = s.next.Create(ctx, req)
+
+		return err
+	})
+
+	return res, err
+}
+
+func (s *RetryingDatabaseService) Query(
+	ctx context.Context, id notionapi.DatabaseID, req *notionapi.DatabaseQueryRequest,
+) (*notionapi.DatabaseQueryResponse, error) {
+	var res *notionapi.DatabaseQueryResponse
+
+	err := s.r.do(ctx, true, func() error {
+		var err error
+		res, err = s.next.Query(ctx, id, req)
+
+		return err
+	})
+
+	return res, err
+}
+
+func (s *RetryingDatabaseService) Get(
+	ctx context.Context, id notionapi.DatabaseID,
+) (*notionapi.Database, error) {
+	var res *notionapi.Database
+
+	err := s.r.do(ctx, true, func() error {
+		var err error
+		res, err = s.next.Get(ctx, id)
+
+		return err
+	})
+
+	return res, err
+}
+
+func (s *RetryingDatabaseService) Update(
+	ctx context.Context, id notionapi.DatabaseID, req *notionapi.DatabaseUpdateRequest,
+) (*notionapi.Database, error) {
+	var res *notionapi.Database
+
+	err := s.r.do(ctx, false, func() error {
+		var err error
+		res, err = s.next.Update(ctx, id, req)
+
+		return err
+	})
+
+	return res, err
+}
+
+// RetryingPageService decorates a notionapi.PageService with throttling and retries.
+type RetryingPageService struct {
+	next notionapi.PageService
+	r    retrier
+}
+
+func NewRetryingPageService(next notionapi.PageService, limiter *Limiter) *RetryingPageService {
+	return &RetryingPageService{next: next, r: newRetrier(limiter)}
+}
+
+func (s *RetryingPageService) Create(
+	ctx context.Context, req *notionapi.PageCreateRequest,
+) (*notionapi.Page, error) {
+	var res *notionapi.Page
+
+	err := s.r.do(ctx, false, func() error {
+		var err error
+		res, err = s.next.Create(ctx, req)
+
+		return err
+	})
+
+	return res, err
+}
+
+func (s *RetryingPageService) Get(ctx context.Context, id notionapi.PageID) (*notionapi.Page, error) {
+	var res *notionapi.Page
+
+	err := s.r.do(ctx, true, func() error {
+		var err error
+		res, err = s.next.Get(ctx, id)
+
+		return err
+	})
+
+	return res, err
+}
+
+func (s *RetryingPageService) Update(
+	ctx context.Context, id notionapi.PageID, req *notionapi.PageUpdateRequest,
+) (*notionapi.Page, error) {
+	var res *notionapi.Page
+
+	err := s.r.do(ctx, false, func() error {
+		var err error
+		res, err = s.next.Update(ctx, id, req)
+
+		return err
+	})
+
+	return res, err
+}
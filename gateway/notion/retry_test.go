@@ -0,0 +1,198 @@
+package notion
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jomei/notionapi"
+	"github.com/stretchr/testify/require"
+
+	"github.com/xgnid-tw/gx5/port"
+)
+
+func newFastRetrier() retrier {
+	r := newRetrier(NewLimiter(1000))
+	r.baseDelay = time.Millisecond
+	r.maxDelay = 2 * time.Millisecond
+
+	return r
+}
+
+func TestRetryingDatabaseService_Query_RetriesServerError(t *testing.T) {
+	calls := 0
+	db := &mockDatabaseService{
+		queryFn: func(
+			context.Context, notionapi.DatabaseID, *notionapi.DatabaseQueryRequest,
+		) (*notionapi.DatabaseQueryResponse, error) {
+			calls++
+			if calls < 3 {
+				return nil, &notionapi.Error{Status: http.StatusBadGateway, Message: "bad gateway"}
+			}
+
+			return &notionapi.DatabaseQueryResponse{}, nil
+		},
+	}
+
+	svc := &RetryingDatabaseService{next: db, r: newFastRetrier()}
+	res, err := svc.Query(context.Background(), "db", &notionapi.DatabaseQueryRequest{})
+
+	require.NoError(t, err)
+	require.NotNil(t, res)
+	require.Equal(t, 3, calls)
+}
+
+func TestRetryingDatabaseService_Query_ClientErrorNotRetried(t *testing.T) {
+	calls := 0
+	db := &mockDatabaseService{
+		queryFn: func(
+			context.Context, notionapi.DatabaseID, *notionapi.DatabaseQueryRequest,
+		) (*notionapi.DatabaseQueryResponse, error) {
+			calls++
+			return nil, &notionapi.Error{Status: http.StatusNotFound, Message: "not found"}
+		},
+	}
+
+	svc := &RetryingDatabaseService{next: db, r: newFastRetrier()}
+	_, err := svc.Query(context.Background(), "db", &notionapi.DatabaseQueryRequest{})
+
+	require.Error(t, err)
+	require.Equal(t, 1, calls)
+	require.NotErrorIs(t, err, port.ErrRateLimited)
+}
+
+func TestRetryingDatabaseService_Query_RateLimitExhausted(t *testing.T) {
+	calls := 0
+	db := &mockDatabaseService{
+		queryFn: func(
+			context.Context, notionapi.DatabaseID, *notionapi.DatabaseQueryRequest,
+		) (*notionapi.DatabaseQueryResponse, error) {
+			calls++
+			return nil, &notionapi.RateLimitedError{Message: "slow down"}
+		},
+	}
+
+	svc := &RetryingDatabaseService{next: db, r: newFastRetrier()}
+	_, err := svc.Query(context.Background(), "db", &notionapi.DatabaseQueryRequest{})
+
+	require.ErrorIs(t, err, port.ErrRateLimited)
+	require.Equal(t, defaultMaxAttempts, calls)
+}
+
+func TestRetryingPageService_Create_RetriesRateLimit(t *testing.T) {
+	calls := 0
+	page := &mockPageService{
+		createFn: func(context.Context, *notionapi.PageCreateRequest) (*notionapi.Page, error) {
+			calls++
+			if calls == 1 {
+				return nil, &notionapi.Error{Status: http.StatusTooManyRequests, Message: "rate limited"}
+			}
+
+			return &notionapi.Page{ID: "page-1"}, nil
+		},
+	}
+
+	svc := &RetryingPageService{next: page, r: newFastRetrier()}
+	res, err := svc.Create(context.Background(), &notionapi.PageCreateRequest{})
+
+	require.NoError(t, err)
+	require.Equal(t, notionapi.ObjectID("page-1"), res.ID)
+	require.Equal(t, 2, calls)
+}
+
+func TestRetryingPageService_Create_ServerErrorNotRetried(t *testing.T) {
+	calls := 0
+	page := &mockPageService{
+		createFn: func(context.Context, *notionapi.PageCreateRequest) (*notionapi.Page, error) {
+			calls++
+			return nil, &notionapi.Error{Status: http.StatusInternalServerError, Message: "boom"}
+		},
+	}
+
+	svc := &RetryingPageService{next: page, r: newFastRetrier()}
+	_, err := svc.Create(context.Background(), &notionapi.PageCreateRequest{})
+
+	require.Error(t, err)
+	require.Equal(t, 1, calls)
+}
+
+func TestRetrier_ContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+
+	err := newFastRetrier().do(ctx, true, func() error {
+		calls++
+		cancel()
+
+		return &notionapi.Error{Status: http.StatusServiceUnavailable}
+	})
+
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, 1, calls)
+}
+
+func TestLimiter_PauseUntil(t *testing.T) {
+	l := NewLimiter(1000)
+	pause := 30 * time.Millisecond
+	l.PauseUntil(time.Now().Add(pause))
+
+	start := time.Now()
+	err := l.Wait(context.Background())
+
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, time.Since(start), pause-5*time.Millisecond)
+}
+
+func TestLimiter_SpacesRequests(t *testing.T) {
+	l := NewLimiter(100)
+
+	start := time.Now()
+
+	for range 3 {
+		require.NoError(t, l.Wait(context.Background()))
+	}
+
+	require.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestRetryAfterTransport_PausesLimiter(t *testing.T) {
+	l := NewLimiter(1000)
+	tr := NewRetryAfterTransport(roundTripFunc(func(*http.Request) (*http.Response, error) {
+		h := http.Header{}
+		h.Set("Retry-After", "2")
+
+		return &http.Response{StatusCode: http.StatusTooManyRequests, Header: h}, nil
+	}), l)
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "https://api.notion.com", nil)
+	require.NoError(t, err)
+
+	res, err := tr.RoundTrip(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusTooManyRequests, res.StatusCode)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	require.ErrorIs(t, l.Wait(ctx), context.DeadlineExceeded)
+}
+
+func TestRetryAfterTransport_PassesThroughErrors(t *testing.T) {
+	tr := NewRetryAfterTransport(roundTripFunc(func(*http.Request) (*http.Response, error) {
+		return nil, errors.New("dial failed")
+	}), NewLimiter(1000))
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "https://api.notion.com", nil)
+	require.NoError(t, err)
+
+	_, err = tr.RoundTrip(req) //nolint:bodyclose // no response on error
+	require.ErrorContains(t, err, "dial failed")
+}
@@ -0,0 +1,293 @@
+package notion
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/jomei/notionapi"
+
+	"github.com/xgnid-tw/gx5/domain"
+)
+
+// columnSpec describes a property the gateway reads or writes.
+type columnSpec struct {
+	name    string
+	typ     notionapi.PropertyConfigType
+	options []string // select options the code filters or writes by
+	// optional columns may be left out of the database, but must have typ when present.
+	optional bool
+}
+
+// tableSchema is the expected shape of one of the databases in designDocs/defination/tables.
+type tableSchema struct {
+	id      string
+	columns []columnSpec
+}
+
+func (s Schema) userTable() tableSchema {
+	c := s.Users
+
+	return tableSchema{
+		id: "TBL-001",
+		columns: []columnSpec{
+			{name: c.DiscordID, typ: notionapi.PropertyConfigTypeTitle},
+			{name: c.Name, typ: notionapi.PropertyConfigTypeRichText},
+			{name: c.NotionID, typ: notionapi.PropertyConfigTypeRichText},
+			{name: c.Currency, typ: notionapi.PropertyConfigTypeSelect},
+			{name: c.Threshold, typ: notionapi.PropertyConfigTypeNumber, optional: true},
+		},
+	}
+}
+
+func (s Schema) personalTxTable() tableSchema {
+	c := s.Transactions
+
+	return tableSchema{
+		id: "TBL-002",
+		columns: []columnSpec{
+			{name: c.ItemName, typ: notionapi.PropertyConfigTypeTitle},
+			{name: c.TWDAmount, typ: notionapi.PropertyConfigTypeNumber},
+			{name: c.JPYAmount, typ: notionapi.PropertyConfigTypeNumber},
+			{
+				name: c.PaymentStatus, typ: notionapi.PropertyConfigTypeSelect,
+				options: []string{c.StatusUnpaid, c.StatusPaid},
+			},
+			{name: c.Order, typ: notionapi.PropertyConfigTypeRelation},
+		},
+	}
+}
+
+func (s Schema) othersTxTable() tableSchema {
+	t := s.personalTxTable()
+	t.id = "TBL-003"
+	t.columns = append(t.columns, columnSpec{name: s.Transactions.Buyer, typ: notionapi.PropertyConfigTypeSelect})
+
+	return t
+}
+
+func (s Schema) orderTable() tableSchema {
+	c := s.Orders
+
+	return tableSchema{
+		id: "TBL-004",
+		columns: []columnSpec{
+			{name: c.ThreadName, typ: notionapi.PropertyConfigTypeTitle},
+			{name: c.Deadline, typ: notionapi.PropertyConfigTypeDate},
+			{name: c.Tags, typ: notionapi.PropertyConfigTypeSelect},
+			{name: c.ThreadID, typ: notionapi.PropertyConfigTypeRichText},
+			{name: c.ChannelID, typ: notionapi.PropertyConfigTypeRichText},
+			{name: c.ShopURL, typ: notionapi.PropertyConfigTypeURL},
+			{name: c.CreatedBy, typ: notionapi.PropertyConfigTypeRichText},
+			{name: c.Status, typ: notionapi.PropertyConfigTypeSelect, options: c.statusOptions()},
+			{name: c.SummaryID, typ: notionapi.PropertyConfigTypeRichText},
+		},
+	}
+}
+
+func (c OrderColumns) statusOptions() []string {
+	lifecycle := domain.OrderLifecycle()
+	options := make([]string, len(lifecycle))
+
+	for i, st := range lifecycle {
+		options[i] = c.statusOption(st)
+	}
+
+	return options
+}
+
+// SchemaProblem is a single mismatch between a Notion database and the expected schema.
+type SchemaProblem struct {
+	Table      string // table ID, e.g. "TBL-002"
+	DatabaseID string
+	Member     string // member name for per-member TBL-002 databases, empty otherwise
+	Detail     string
+}
+
+func (p SchemaProblem) String() string {
+	where := p.Table
+	if p.Member != "" {
+		where += " (" + p.Member + ")"
+	}
+
+	return fmt.Sprintf("%s [%s]: %s", where, p.DatabaseID, p.Detail)
+}
+
+// SchemaReport collects every problem found while verifying the Notion databases.
+type SchemaReport struct {
+	Problems []SchemaProblem
+}
+
+// HasSharedProblems reports whether a database shared by all members (TBL-001/003/004) is broken.
+func (r *SchemaReport) HasSharedProblems() bool {
+	return slices.ContainsFunc(r.Problems, func(p SchemaProblem) bool { return p.Member == "" })
+}
+
+func (r *SchemaReport) String() string {
+	if len(r.Problems) == 0 {
+		return "notion schema OK"
+	}
+
+	lines := make([]string, 0, len(r.Problems)+1)
+	lines = append(lines, fmt.Sprintf("notion schema: %d problem(s)", len(r.Problems)))
+
+	for _, p := range r.Problems {
+		lines = append(lines, "  - "+p.String())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// SchemaValidator checks that the Notion databases have the columns the gateway depends on.
+type SchemaValidator struct {
+	db         notionapi.DatabaseService
+	userDBID   string
+	othersDBID string
+	orderDBID  string
+	schema     Schema
+}
+
+func NewSchemaValidator(
+	db notionapi.DatabaseService, userDBID string, othersDBID string, orderDBID string, schema Schema,
+) *SchemaValidator {
+	return &SchemaValidator{
+		db: db, userDBID: userDBID, othersDBID: othersDBID, orderDBID: orderDBID, schema: schema,
+	}
+}
+
+// Validate verifies the shared databases and the personal TBL-002 database of every
+// member. Problems are collected in the report; only a context error aborts the run.
+func (v *SchemaValidator) Validate(ctx context.Context, users []*domain.User) (*SchemaReport, error) {
+	report := &SchemaReport{}
+
+	shared := []struct {
+		id     string
+		schema tableSchema
+	}{
+		{v.userDBID, v.schema.userTable()},
+		{v.othersDBID, v.schema.othersTxTable()},
+		{v.orderDBID, v.schema.orderTable()},
+	}
+
+	for _, t := range shared {
+		report.Problems = append(report.Problems, v.check(ctx, t.id, t.schema, "")...)
+	}
+
+	for _, u := range users {
+		if u.NotionID == v.othersDBID {
+			continue
+		}
+
+		report.Problems = append(report.Problems, v.check(ctx, u.NotionID, v.schema.personalTxTable(), u.Name)...)
+	}
+
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	return report, nil
+}
+
+func (v *SchemaValidator) check(
+	ctx context.Context, databaseID string, schema tableSchema, member string,
+) []SchemaProblem {
+	problem := func(format string, args ...any) SchemaProblem {
+		return SchemaProblem{
+			Table: schema.id, DatabaseID: databaseID, Member: member,
+			Detail: fmt.Sprintf(format, args...),
+		}
+	}
+
+	if databaseID == "" {
+		return []SchemaProblem{problem("database ID is empty")}
+	}
+
+	db, err := v.db.Get(ctx, notionapi.DatabaseID(databaseID))
+	if err != nil {
+		return []SchemaProblem{problem("cannot read database: %s", err)}
+	}
+
+	return diffSchema(db.Properties, schema, problem)
+}
+
+func diffSchema(
+	props notionapi.PropertyConfigs, schema tableSchema,
+	problem func(format string, args ...any) SchemaProblem,
+) []SchemaProblem {
+	var problems []SchemaProblem
+
+	for _, col := range schema.columns {
+		cfg, ok := props[col.name]
+		if !ok && col.optional {
+			continue
+		}
+
+		if !ok {
+			if candidate := renameCandidate(props, schema, col); candidate != "" {
+				problems = append(problems, problem(
+					"column %q (%s) missing; possibly renamed to %q", col.name, col.typ, candidate,
+				))
+			} else {
+				problems = append(problems, problem("column %q (%s) missing", col.name, col.typ))
+			}
+
+			continue
+		}
+
+		if cfg.GetType() != col.typ {
+			problems = append(problems, problem(
+				"column %q has type %s, expected %s", col.name, cfg.GetType(), col.typ,
+			))
+
+			continue
+		}
+
+		for _, opt := range missingOptions(cfg, col.options) {
+			problems = append(problems, problem("column %q has no select option %q", col.name, opt))
+		}
+	}
+
+	return problems
+}
+
+// renameCandidate returns an unexpected column of the same type as col, which most
+// likely is col after a rename in Notion. It returns "" when there is no single match.
+func renameCandidate(props notionapi.PropertyConfigs, schema tableSchema, col columnSpec) string {
+	var candidates []string
+
+	for name, cfg := range props {
+		if cfg.GetType() != col.typ {
+			continue
+		}
+
+		known := slices.ContainsFunc(schema.columns, func(c columnSpec) bool { return c.name == name })
+		if !known {
+			candidates = append(candidates, name)
+		}
+	}
+
+	if len(candidates) != 1 {
+		return ""
+	}
+
+	return candidates[0]
+}
+
+func missingOptions(cfg notionapi.PropertyConfig, want []string) []string {
+	sc, ok := cfg.(*notionapi.SelectPropertyConfig)
+	if !ok {
+		return nil
+	}
+
+	var missing []string
+
+	for _, w := range want {
+		found := slices.ContainsFunc(sc.Select.Options, func(o notionapi.Option) bool { return o.Name == w })
+		if !found {
+			missing = append(missing, w)
+		}
+	}
+
+	return missing
+}
@@ -0,0 +1,187 @@
+package notion
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jomei/notionapi"
+	"github.com/stretchr/testify/require"
+
+	"github.com/xgnid-tw/gx5/domain"
+)
+
+func schemaProps(schema tableSchema) notionapi.PropertyConfigs {
+	props := notionapi.PropertyConfigs{}
+
+	for _, col := range schema.columns {
+		switch col.typ {
+		case notionapi.PropertyConfigTypeTitle:
+			props[col.name] = &notionapi.TitlePropertyConfig{Type: col.typ}
+		case notionapi.PropertyConfigTypeRichText:
+			props[col.name] = &notionapi.RichTextPropertyConfig{Type: col.typ}
+		case notionapi.PropertyConfigTypeNumber:
+			props[col.name] = &notionapi.NumberPropertyConfig{Type: col.typ}
+		case notionapi.PropertyConfigTypeDate:
+			props[col.name] = &notionapi.DatePropertyConfig{Type: col.typ}
+		case notionapi.PropertyConfigTypeURL:
+			props[col.name] = &notionapi.URLPropertyConfig{Type: col.typ}
+		case notionapi.PropertyConfigTypeRelation:
+			props[col.name] = &notionapi.RelationPropertyConfig{Type: col.typ}
+		case notionapi.PropertyConfigTypeSelect:
+			opts := make([]notionapi.Option, 0, len(col.options))
+			for _, o := range col.options {
+				opts = append(opts, notionapi.Option{Name: o})
+			}
+
+			props[col.name] = &notionapi.SelectPropertyConfig{Type: col.typ, Select: notionapi.Select{Options: opts}}
+		default:
+			panic("unhandled type in test helper: " + string(col.typ))
+		}
+	}
+
+	return props
+}
+
+func newSchemaTestDB(dbs map[notionapi.DatabaseID]notionapi.PropertyConfigs) *mockDatabaseService {
+	return &mockDatabaseService{
+		getFn: func(_ context.Context, id notionapi.DatabaseID) (*notionapi.Database, error) {
+			props, ok := dbs[id]
+			if !ok {
+				return nil, errors.New("Could not find database")
+			}
+
+			return &notionapi.Database{Properties: props}, nil
+		},
+	}
+}
+
+func validSchemaDBs() map[notionapi.DatabaseID]notionapi.PropertyConfigs {
+	return map[notionapi.DatabaseID]notionapi.PropertyConfigs{
+		"user-db":   schemaProps(DefaultSchema().userTable()),
+		"others-db": schemaProps(DefaultSchema().othersTxTable()),
+		"order-db":  schemaProps(DefaultSchema().orderTable()),
+		"alice-db":  schemaProps(DefaultSchema().personalTxTable()),
+	}
+}
+
+var schemaTestUsers = []*domain.User{
+	{Name: "Alice", NotionID: "alice-db"},
+	{Name: "Carol", NotionID: "others-db"},
+}
+
+func TestSchemaValidator_AllValid(t *testing.T) {
+	v := NewSchemaValidator(newSchemaTestDB(validSchemaDBs()), "user-db", "others-db", "order-db", DefaultSchema())
+
+	report, err := v.Validate(context.Background(), schemaTestUsers)
+
+	require.NoError(t, err)
+	require.Empty(t, report.Problems)
+	require.Equal(t, "notion schema OK", report.String())
+}
+
+func TestSchemaValidator_MissingColumn(t *testing.T) {
+	dbs := validSchemaDBs()
+	delete(dbs["order-db"], "deadline")
+
+	v := NewSchemaValidator(newSchemaTestDB(dbs), "user-db", "others-db", "order-db", DefaultSchema())
+
+	report, err := v.Validate(context.Background(), schemaTestUsers)
+
+	require.NoError(t, err)
+	require.Len(t, report.Problems, 1)
+	require.Equal(t, "TBL-004", report.Problems[0].Table)
+	require.Contains(t, report.Problems[0].Detail, `column "deadline" (date) missing`)
+	require.True(t, report.HasSharedProblems())
+}
+
+func TestSchemaValidator_RenamedColumn(t *testing.T) {
+	dbs := validSchemaDBs()
+	dbs["alice-db"]["付款狀態"] = dbs["alice-db"]["付款狀況"]
+	delete(dbs["alice-db"], "付款狀況")
+
+	v := NewSchemaValidator(newSchemaTestDB(dbs), "user-db", "others-db", "order-db", DefaultSchema())
+
+	report, err := v.Validate(context.Background(), schemaTestUsers)
+
+	require.NoError(t, err)
+	require.Len(t, report.Problems, 1)
+	require.Equal(t, "Alice", report.Problems[0].Member)
+	require.Contains(t, report.Problems[0].Detail, `possibly renamed to "付款狀態"`)
+	require.False(t, report.HasSharedProblems())
+}
+
+func TestSchemaValidator_MissingOrderRelation(t *testing.T) {
+	dbs := validSchemaDBs()
+	delete(dbs["alice-db"], "訂單")
+
+	v := NewSchemaValidator(newSchemaTestDB(dbs), "user-db", "others-db", "order-db", DefaultSchema())
+
+	report, err := v.Validate(context.Background(), schemaTestUsers)
+
+	require.NoError(t, err)
+	require.Len(t, report.Problems, 1)
+	require.Equal(t, "TBL-002", report.Problems[0].Table)
+	require.Equal(t, "Alice", report.Problems[0].Member)
+	require.Contains(t, report.Problems[0].Detail, `column "訂單" (relation) missing`)
+}
+
+func TestSchemaValidator_MistypedColumn(t *testing.T) {
+	dbs := validSchemaDBs()
+	dbs["user-db"]["currency"] = &notionapi.RichTextPropertyConfig{Type: notionapi.PropertyConfigTypeRichText}
+
+	v := NewSchemaValidator(newSchemaTestDB(dbs), "user-db", "others-db", "order-db", DefaultSchema())
+
+	report, err := v.Validate(context.Background(), schemaTestUsers)
+
+	require.NoError(t, err)
+	require.Len(t, report.Problems, 1)
+	require.Contains(t, report.Problems[0].Detail, `column "currency" has type rich_text, expected select`)
+}
+
+func TestSchemaValidator_MissingSelectOption(t *testing.T) {
+	dbs := validSchemaDBs()
+	dbs["others-db"]["付款狀況"] = &notionapi.SelectPropertyConfig{
+		Type:   notionapi.PropertyConfigTypeSelect,
+		Select: notionapi.Select{Options: []notionapi.Option{{Name: "已付款"}}},
+	}
+
+	v := NewSchemaValidator(newSchemaTestDB(dbs), "user-db", "others-db", "order-db", DefaultSchema())
+
+	report, err := v.Validate(context.Background(), schemaTestUsers)
+
+	require.NoError(t, err)
+	require.Len(t, report.Problems, 1)
+	require.Contains(t, report.Problems[0].Detail, `no select option "尚未付款"`)
+}
+
+func TestSchemaValidator_UnreadableMemberDatabase(t *testing.T) {
+	v := NewSchemaValidator(newSchemaTestDB(validSchemaDBs()), "user-db", "others-db", "order-db", DefaultSchema())
+
+	report, err := v.Validate(context.Background(), []*domain.User{{Name: "Bob", NotionID: "deleted-db"}})
+
+	require.NoError(t, err)
+	require.Len(t, report.Problems, 1)
+	require.Equal(t, "TBL-002", report.Problems[0].Table)
+	require.Contains(t, report.String(), "TBL-002 (Bob) [deleted-db]: cannot read database")
+}
+
+func TestSchemaValidator_OptionalColumn(t *testing.T) {
+	dbs := validSchemaDBs()
+	delete(dbs["user-db"], "reminder_threshold")
+
+	v := NewSchemaValidator(newSchemaTestDB(dbs), "user-db", "others-db", "order-db", DefaultSchema())
+
+	report, err := v.Validate(context.Background(), schemaTestUsers)
+
+	require.NoError(t, err)
+	require.Empty(t, report.Problems, "an optional column may be left out")
+
+	dbs["user-db"]["reminder_threshold"] = &notionapi.RichTextPropertyConfig{Type: notionapi.PropertyConfigTypeRichText}
+
+	report, err = v.Validate(context.Background(), schemaTestUsers)
+
+	require.NoError(t, err)
+	require.Len(t, report.Problems, 1)
+	require.Contains(t, report.Problems[0].Detail, `column "reminder_threshold" has type rich_text, expected number`)
+}
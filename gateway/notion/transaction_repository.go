@@ -2,7 +2,9 @@ package notion
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/jomei/notionapi"
 
@@ -12,44 +14,188 @@ import (
 // TransactionRepository implements port.TransactionRepository using the Notion API.
 type TransactionRepository struct {
 	page notionapi.PageService
+	cols TransactionColumns
 }
 
-func NewTransactionRepository(page notionapi.PageService) *TransactionRepository {
-	return &TransactionRepository{page: page}
+func NewTransactionRepository(page notionapi.PageService, schema Schema) *TransactionRepository {
+	return &TransactionRepository{page: page, cols: schema.Transactions}
 }
 
-func (r *TransactionRepository) CreateTransaction(ctx context.Context, tx domain.Transaction) error {
+func (r *TransactionRepository) CreateTransaction(ctx context.Context, tx domain.Transaction) (string, error) {
 	req := &notionapi.PageCreateRequest{
 		Parent: notionapi.Parent{
 			Type:       notionapi.ParentTypeDatabaseID,
 			DatabaseID: notionapi.DatabaseID(tx.DatabaseID),
 		},
 		Properties: notionapi.Properties{
-			"品項": notionapi.TitleProperty{
+			r.cols.ItemName: notionapi.TitleProperty{
 				Type: notionapi.PropertyTypeTitle,
 				Title: []notionapi.RichText{
 					{Type: notionapi.ObjectTypeText, Text: &notionapi.Text{Content: tx.ItemName}},
 				},
 			},
-			"日幣": notionapi.NumberProperty{
+			r.cols.JPYAmount: notionapi.NumberProperty{
 				Type:   notionapi.PropertyTypeNumber,
 				Number: tx.JPYAmount,
 			},
-			"台幣": notionapi.NumberProperty{
+			r.cols.TWDAmount: notionapi.NumberProperty{
 				Type:   notionapi.PropertyTypeNumber,
 				Number: tx.TWDAmount,
 			},
-			"付款狀況": notionapi.SelectProperty{
+			r.cols.PaymentStatus: notionapi.SelectProperty{
 				Type:   notionapi.PropertyTypeSelect,
-				Select: notionapi.Option{Name: "尚未付款"},
+				Select: notionapi.Option{Name: r.cols.StatusUnpaid},
 			},
 		},
 	}
 
-	_, err := r.page.Create(ctx, req)
+	err := r.setDetails(req.Properties, tx)
 	if err != nil {
-		return fmt.Errorf("notion page create failed: %w", err)
+		return "", err
+	}
+
+	// Notion adds a 購買人 option that does not exist yet when the page is written.
+	if tx.BuyerName != "" {
+		req.Properties[r.cols.Buyer] = notionapi.SelectProperty{
+			Type:   notionapi.PropertyTypeSelect,
+			Select: notionapi.Option{Name: tx.BuyerName},
+		}
+	}
+
+	created, err := r.page.Create(ctx, req)
+	if err != nil {
+		return "", fmt.Errorf("notion page create failed: %w", err)
+	}
+
+	return string(created.ID), nil
+}
+
+// UpdateTransaction rewrites a row after it was edited from its /buy message.
+// Empty note, shop, link and expected arrival clear their columns; 物品狀況,
+// 購買人 and 訂單 are not part of the edit and are left alone.
+func (r *TransactionRepository) UpdateTransaction(ctx context.Context, pageID string, tx domain.Transaction) error {
+	props := notionapi.Properties{
+		r.cols.ItemName: notionapi.TitleProperty{
+			Type: notionapi.PropertyTypeTitle,
+			Title: []notionapi.RichText{
+				{Type: notionapi.ObjectTypeText, Text: &notionapi.Text{Content: tx.ItemName}},
+			},
+		},
+		r.cols.JPYAmount: notionapi.NumberProperty{
+			Type:   notionapi.PropertyTypeNumber,
+			Number: tx.JPYAmount,
+		},
+		r.cols.TWDAmount: notionapi.NumberProperty{
+			Type:   notionapi.PropertyTypeNumber,
+			Number: tx.TWDAmount,
+		},
+		r.cols.Note: notionapi.RichTextProperty{
+			Type:     notionapi.PropertyTypeRichText,
+			RichText: []notionapi.RichText{},
+		},
+	}
+
+	err := r.setDetails(props, tx)
+	if err != nil {
+		return err
+	}
+
+	if tx.Shop == "" {
+		props[r.cols.Shop] = nullProperty{typ: notionapi.PropertyTypeSelect}
+	}
+
+	if tx.Link == "" {
+		props[r.cols.Link] = nullProperty{typ: notionapi.PropertyTypeURL}
+	}
+
+	if tx.ExpectedArrival == "" {
+		// A nil date is sent as null, which clears the column.
+		props[r.cols.ExpectedArrival] = notionapi.DateProperty{Type: notionapi.PropertyTypeDate}
+	}
+
+	_, err = r.page.Update(ctx, notionapi.PageID(pageID), &notionapi.PageUpdateRequest{Properties: props})
+	if err != nil {
+		return fmt.Errorf("notion page update failed for %s: %w", pageID, err)
 	}
 
 	return nil
 }
+
+func (r *TransactionRepository) ArchiveTransaction(ctx context.Context, pageID string) error {
+	_, err := r.page.Update(ctx, notionapi.PageID(pageID), &notionapi.PageUpdateRequest{Archived: true})
+	if err != nil {
+		return fmt.Errorf("notion page update failed for %s: %w", pageID, err)
+	}
+
+	return nil
+}
+
+// setDetails adds the optional tracking columns that are set on tx.
+func (r *TransactionRepository) setDetails(props notionapi.Properties, tx domain.Transaction) error {
+	if tx.ItemStatus != "" {
+		props[r.cols.ItemStatus] = notionapi.SelectProperty{
+			Type:   notionapi.PropertyTypeSelect,
+			Select: notionapi.Option{Name: r.cols.itemStatusOption(tx.ItemStatus)},
+		}
+	}
+
+	if tx.Shop != "" {
+		props[r.cols.Shop] = notionapi.SelectProperty{
+			Type:   notionapi.PropertyTypeSelect,
+			Select: notionapi.Option{Name: tx.Shop},
+		}
+	}
+
+	if tx.Link != "" {
+		props[r.cols.Link] = notionapi.URLProperty{
+			Type: notionapi.PropertyTypeURL,
+			URL:  tx.Link,
+		}
+	}
+
+	if tx.Note != "" {
+		props[r.cols.Note] = notionapi.RichTextProperty{
+			Type: notionapi.PropertyTypeRichText,
+			RichText: []notionapi.RichText{
+				{Type: notionapi.ObjectTypeText, Text: &notionapi.Text{Content: tx.Note}},
+			},
+		}
+	}
+
+	if tx.OrderID != "" {
+		props[r.cols.Order] = notionapi.RelationProperty{
+			Type:     notionapi.PropertyTypeRelation,
+			Relation: []notionapi.Relation{{ID: notionapi.PageID(tx.OrderID)}},
+		}
+	}
+
+	if tx.ExpectedArrival != "" {
+		t, err := time.Parse("2006-01-02", tx.ExpectedArrival)
+		if err != nil {
+			return fmt.Errorf("invalid expected arrival format: %w", err)
+		}
+
+		d := notionapi.Date(t)
+		props[r.cols.ExpectedArrival] = notionapi.DateProperty{
+			Type: notionapi.PropertyTypeDate,
+			Date: &notionapi.DateObject{Start: &d},
+		}
+	}
+
+	return nil
+}
+
+// nullProperty clears a select or URL column. notionapi always sends a value for
+// those types (an empty option name or URL, which Notion rejects), while Notion
+// only clears them on an explicit null.
+type nullProperty struct {
+	typ notionapi.PropertyType
+}
+
+func (p nullProperty) GetID() string { return "" }
+
+func (p nullProperty) GetType() notionapi.PropertyType { return p.typ }
+
+func (p nullProperty) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]any{"type": p.typ, string(p.typ): nil})
+}
@@ -2,8 +2,11 @@ package notion
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/jomei/notionapi"
 	"github.com/stretchr/testify/require"
@@ -17,11 +20,11 @@ func TestCreateTransaction_Success(t *testing.T) {
 	page := &mockPageService{
 		createFn: func(_ context.Context, req *notionapi.PageCreateRequest) (*notionapi.Page, error) {
 			capturedReq = req
-			return &notionapi.Page{}, nil
+			return &notionapi.Page{ID: "new-page"}, nil
 		},
 	}
 
-	repo := NewTransactionRepository(page)
+	repo := NewTransactionRepository(page, DefaultSchema())
 	tx := domain.Transaction{
 		ItemName:   "Test Item",
 		JPYAmount:  3000,
@@ -29,9 +32,10 @@ func TestCreateTransaction_Success(t *testing.T) {
 		DatabaseID: "target-db",
 	}
 
-	err := repo.CreateTransaction(context.Background(), tx)
+	pageID, err := repo.CreateTransaction(context.Background(), tx)
 
 	require.NoError(t, err)
+	require.Equal(t, "new-page", pageID)
 	require.Equal(t, notionapi.DatabaseID("target-db"), capturedReq.Parent.DatabaseID)
 
 	title, ok := capturedReq.Properties["品項"].(notionapi.TitleProperty)
@@ -49,6 +53,8 @@ func TestCreateTransaction_Success(t *testing.T) {
 	status, ok := capturedReq.Properties["付款狀況"].(notionapi.SelectProperty)
 	require.True(t, ok)
 	require.Equal(t, "尚未付款", status.Select.Name)
+
+	require.NotContains(t, capturedReq.Properties, "購買人")
 }
 
 func TestCreateTransaction_Error(t *testing.T) {
@@ -58,7 +64,7 @@ func TestCreateTransaction_Error(t *testing.T) {
 		},
 	}
 
-	repo := NewTransactionRepository(page)
+	repo := NewTransactionRepository(page, DefaultSchema())
 	tx := domain.Transaction{
 		ItemName:   "Test Item",
 		JPYAmount:  3000,
@@ -66,8 +72,288 @@ func TestCreateTransaction_Error(t *testing.T) {
 		DatabaseID: "target-db",
 	}
 
-	err := repo.CreateTransaction(context.Background(), tx)
+	_, err := repo.CreateTransaction(context.Background(), tx)
 
 	require.Error(t, err)
 	require.ErrorContains(t, err, "notion page create failed")
 }
+
+func TestCreateTransaction_Details(t *testing.T) {
+	var capturedReq *notionapi.PageCreateRequest
+
+	page := &mockPageService{
+		createFn: func(_ context.Context, req *notionapi.PageCreateRequest) (*notionapi.Page, error) {
+			capturedReq = req
+			return &notionapi.Page{}, nil
+		},
+	}
+
+	repo := NewTransactionRepository(page, DefaultSchema())
+	_, err := repo.CreateTransaction(context.Background(), domain.Transaction{
+		ItemName:        "Test Item",
+		DatabaseID:      "target-db",
+		ItemStatus:      domain.ItemStatusNotOrdered,
+		Shop:            "HMV",
+		Link:            "https://www.hmv.co.jp/product/detail/1",
+		Note:            "2 個",
+		ExpectedArrival: "2026-12-01",
+		OrderID:         "order-page",
+	})
+
+	require.NoError(t, err)
+
+	itemStatus, ok := capturedReq.Properties["物品狀況"].(notionapi.SelectProperty)
+	require.True(t, ok)
+	require.Equal(t, "未訂購", itemStatus.Select.Name)
+
+	shop, ok := capturedReq.Properties["購買途徑"].(notionapi.SelectProperty)
+	require.True(t, ok)
+	require.Equal(t, "HMV", shop.Select.Name)
+
+	link, ok := capturedReq.Properties["連結"].(notionapi.URLProperty)
+	require.True(t, ok)
+	require.Equal(t, "https://www.hmv.co.jp/product/detail/1", link.URL)
+
+	note, ok := capturedReq.Properties["備註"].(notionapi.RichTextProperty)
+	require.True(t, ok)
+	require.Equal(t, "2 個", note.RichText[0].Text.Content)
+
+	eta, ok := capturedReq.Properties["預計到貨"].(notionapi.DateProperty)
+	require.True(t, ok)
+	require.Equal(t, "2026-12-01", time.Time(*eta.Date.Start).Format("2006-01-02"))
+
+	order, ok := capturedReq.Properties["訂單"].(notionapi.RelationProperty)
+	require.True(t, ok)
+	require.Equal(t, []notionapi.Relation{{ID: "order-page"}}, order.Relation)
+}
+
+func TestCreateTransaction_EmptyDetailsOmitted(t *testing.T) {
+	var capturedReq *notionapi.PageCreateRequest
+
+	page := &mockPageService{
+		createFn: func(_ context.Context, req *notionapi.PageCreateRequest) (*notionapi.Page, error) {
+			capturedReq = req
+			return &notionapi.Page{}, nil
+		},
+	}
+
+	repo := NewTransactionRepository(page, DefaultSchema())
+	_, err := repo.CreateTransaction(context.Background(), domain.Transaction{ItemName: "Test Item", DatabaseID: "target-db"})
+
+	require.NoError(t, err)
+
+	for _, col := range []string{"物品狀況", "購買途徑", "連結", "備註", "預計到貨", "訂單"} {
+		require.NotContains(t, capturedReq.Properties, col)
+	}
+}
+
+func TestCreateTransaction_InvalidExpectedArrival(t *testing.T) {
+	repo := NewTransactionRepository(&mockPageService{}, DefaultSchema())
+	_, err := repo.CreateTransaction(context.Background(), domain.Transaction{
+		ItemName: "Test Item", DatabaseID: "target-db", ExpectedArrival: "12/01",
+	})
+
+	require.ErrorContains(t, err, "invalid expected arrival format")
+}
+
+func TestUpdateTransaction(t *testing.T) {
+	var (
+		capturedID  notionapi.PageID
+		capturedReq *notionapi.PageUpdateRequest
+	)
+
+	page := &mockPageService{
+		updateFn: func(
+			_ context.Context, id notionapi.PageID, req *notionapi.PageUpdateRequest,
+		) (*notionapi.Page, error) {
+			capturedID, capturedReq = id, req
+			return &notionapi.Page{ID: notionapi.ObjectID(id)}, nil
+		},
+	}
+
+	repo := NewTransactionRepository(page, DefaultSchema())
+	err := repo.UpdateTransaction(context.Background(), "page-1", domain.Transaction{
+		ItemName: "CD", JPYAmount: 3500, TWDAmount: 840, Shop: "Booth",
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, notionapi.PageID("page-1"), capturedID)
+
+	title := capturedReq.Properties["品項"].(notionapi.TitleProperty)
+	require.Equal(t, "CD", title.Title[0].Text.Content)
+	require.Equal(t, 3500.0, capturedReq.Properties["日幣"].(notionapi.NumberProperty).Number)
+	require.Equal(t, 840.0, capturedReq.Properties["台幣"].(notionapi.NumberProperty).Number)
+	require.Equal(t, "Booth", capturedReq.Properties["購買途徑"].(notionapi.SelectProperty).Select.Name)
+
+	// Empty fields clear their columns; columns outside the edit are not sent.
+	body, err := json.Marshal(capturedReq)
+	require.NoError(t, err)
+	require.Contains(t, string(body), `"備註":{"type":"rich_text","rich_text":[]}`)
+	require.Contains(t, string(body), `"連結":{"type":"url","url":null}`)
+	require.Contains(t, string(body), `"預計到貨":{"type":"date","date":null}`)
+	require.NotContains(t, capturedReq.Properties, "物品狀況")
+	require.NotContains(t, capturedReq.Properties, "付款狀況")
+	require.NotContains(t, capturedReq.Properties, "購買人")
+	require.NotContains(t, capturedReq.Properties, "訂單")
+}
+
+func TestUpdateTransaction_ClearsShop(t *testing.T) {
+	var capturedReq *notionapi.PageUpdateRequest
+
+	page := &mockPageService{
+		updateFn: func(
+			_ context.Context, id notionapi.PageID, req *notionapi.PageUpdateRequest,
+		) (*notionapi.Page, error) {
+			capturedReq = req
+			return &notionapi.Page{ID: notionapi.ObjectID(id)}, nil
+		},
+	}
+
+	repo := NewTransactionRepository(page, DefaultSchema())
+	err := repo.UpdateTransaction(context.Background(), "page-1", domain.Transaction{
+		ItemName: "CD", JPYAmount: 3500, TWDAmount: 840, Link: "https://example.com/cd",
+		ExpectedArrival: "2026-11-30",
+	})
+
+	require.NoError(t, err)
+
+	body, err := json.Marshal(capturedReq)
+	require.NoError(t, err)
+	require.Contains(t, string(body), `"購買途徑":{"select":null,"type":"select"}`)
+	require.Equal(t, "https://example.com/cd", capturedReq.Properties["連結"].(notionapi.URLProperty).URL)
+	require.IsType(t, notionapi.DateProperty{}, capturedReq.Properties["預計到貨"])
+}
+
+func TestArchiveTransaction(t *testing.T) {
+	var capturedReq *notionapi.PageUpdateRequest
+
+	page := &mockPageService{
+		updateFn: func(
+			_ context.Context, id notionapi.PageID, req *notionapi.PageUpdateRequest,
+		) (*notionapi.Page, error) {
+			capturedReq = req
+			return &notionapi.Page{ID: notionapi.ObjectID(id)}, nil
+		},
+	}
+
+	repo := NewTransactionRepository(page, DefaultSchema())
+
+	require.NoError(t, repo.ArchiveTransaction(context.Background(), "page-1"))
+	require.True(t, capturedReq.Archived)
+	require.Empty(t, capturedReq.Properties)
+}
+
+func TestArchiveTransaction_Error(t *testing.T) {
+	page := &mockPageService{
+		updateFn: func(context.Context, notionapi.PageID, *notionapi.PageUpdateRequest) (*notionapi.Page, error) {
+			return nil, errors.New("api down")
+		},
+	}
+
+	repo := NewTransactionRepository(page, DefaultSchema())
+	err := repo.ArchiveTransaction(context.Background(), "page-1")
+
+	require.ErrorContains(t, err, "notion page update failed for page-1")
+}
+
+func TestCreateTransaction_Buyer(t *testing.T) {
+	var capturedReq *notionapi.PageCreateRequest
+
+	page := &mockPageService{
+		createFn: func(_ context.Context, req *notionapi.PageCreateRequest) (*notionapi.Page, error) {
+			capturedReq = req
+			return &notionapi.Page{}, nil
+		},
+	}
+
+	repo := NewTransactionRepository(page, DefaultSchema())
+	_, err := repo.CreateTransaction(context.Background(), domain.Transaction{
+		ItemName: "Badge", JPYAmount: 1000, TWDAmount: 240, DatabaseID: "others-db", BuyerName: "Dave",
+	})
+
+	require.NoError(t, err)
+
+	buyer, ok := capturedReq.Properties["購買人"].(notionapi.SelectProperty)
+	require.True(t, ok)
+	require.Equal(t, "Dave", buyer.Select.Name)
+}
+
+// TestCreateTransaction_Buyer_RoundTrip checks that a row written for a TBL-003
+// member matches the filter GetOthersUnpaidItems uses to read it back.
+func TestCreateTransaction_Buyer_RoundTrip(t *testing.T) {
+	var stored []notionapi.Page
+
+	db := &mockDatabaseService{
+		queryFn: func(
+			_ context.Context, _ notionapi.DatabaseID, req *notionapi.DatabaseQueryRequest,
+		) (*notionapi.DatabaseQueryResponse, error) {
+			filter, ok := req.Filter.(notionapi.AndCompoundFilter)
+			require.True(t, ok)
+
+			var results []notionapi.Page
+
+			for _, p := range stored {
+				if matchesSelectFilters(p, filter) {
+					results = append(results, p)
+				}
+			}
+
+			return &notionapi.DatabaseQueryResponse{Results: results}, nil
+		},
+	}
+	page := &mockPageService{
+		createFn: func(_ context.Context, req *notionapi.PageCreateRequest) (*notionapi.Page, error) {
+			// Store the page the way Notion returns it from a query.
+			props := notionapi.Properties{}
+			for name, p := range req.Properties {
+				switch v := p.(type) {
+				case notionapi.TitleProperty:
+					props[name] = &v
+				case notionapi.NumberProperty:
+					props[name] = &v
+				case notionapi.SelectProperty:
+					props[name] = &v
+				}
+			}
+
+			p := notionapi.Page{ID: notionapi.ObjectID(fmt.Sprintf("page-%d", len(stored))), Properties: props}
+			stored = append(stored, p)
+
+			return &p, nil
+		},
+	}
+
+	txRepo := NewTransactionRepository(page, DefaultSchema())
+	userRepo := NewRepository(db, "user-db", "others-db", DefaultSchema())
+
+	for _, tx := range []domain.Transaction{
+		{ItemName: "Badge", JPYAmount: 1000, TWDAmount: 240, DatabaseID: "others-db", BuyerName: "Carol"},
+		{ItemName: "CD", JPYAmount: 2000, TWDAmount: 480, DatabaseID: "others-db", BuyerName: "Erin"},
+	} {
+		_, err := txRepo.CreateTransaction(context.Background(), tx)
+		require.NoError(t, err)
+	}
+
+	items, err := userRepo.GetOthersUnpaidItems(context.Background(), "Carol")
+
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	require.Equal(t, "Badge", items[0].ItemName)
+	require.Equal(t, 240.0, items[0].TWDAmount)
+}
+
+func matchesSelectFilters(p notionapi.Page, filters notionapi.AndCompoundFilter) bool {
+	for _, f := range filters {
+		pf, ok := f.(notionapi.PropertyFilter)
+		if !ok || pf.Select == nil {
+			return false
+		}
+
+		sel, ok := p.Properties[pf.Property].(*notionapi.SelectProperty)
+		if !ok || sel.Select.Name != pf.Select.Equals {
+			return false
+		}
+	}
+
+	return true
+}
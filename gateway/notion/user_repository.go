@@ -3,72 +3,120 @@ package notion
 import (
 	"context"
 	"fmt"
+	"slices"
+	"time"
 
 	"github.com/jomei/notionapi"
 
 	"github.com/xgnid-tw/gx5/domain"
+	"github.com/xgnid-tw/gx5/port"
 )
 
-var currencyColumnMap = map[domain.Currency]string{
-	domain.CurrencyTWD: "台幣",
-	domain.CurrencyJPY: "日幣",
-}
-
 // Repository implements port.UserRepository using the Notion API.
 type Repository struct {
 	db         notionapi.DatabaseService
 	userDBID   notionapi.DatabaseID
 	othersDBID notionapi.DatabaseID
+	schema     Schema
 }
 
-func NewRepository(db notionapi.DatabaseService, userDBID string, othersDBID string) *Repository {
+func NewRepository(
+	db notionapi.DatabaseService, userDBID string, othersDBID string, schema Schema,
+) *Repository {
 	return &Repository{
 		db:         db,
 		userDBID:   notionapi.DatabaseID(userDBID),
 		othersDBID: notionapi.DatabaseID(othersDBID),
+		schema:     schema,
 	}
 }
 
 func (r *Repository) GetUsers(ctx context.Context) ([]*domain.User, error) {
-	result, err := r.db.Query(ctx, r.userDBID, &notionapi.DatabaseQueryRequest{})
+	pages, err := queryAll(ctx, r.db, r.userDBID, &notionapi.DatabaseQueryRequest{})
 	if err != nil {
-		return nil, fmt.Errorf("notion database query failed: %w", err)
+		return nil, err
+	}
+
+	thresholds, err := r.reminderThresholds(ctx, pages)
+	if err != nil {
+		return nil, err
 	}
 
-	users := make([]*domain.User, 0, len(result.Results))
+	cols := r.schema.Users
+	users := make([]*domain.User, 0, len(pages))
 
-	for _, v := range result.Results {
-		discordID, ok := getTitleContent(v.Properties["discord_id"])
+	for _, v := range pages {
+		discordID, ok := getTitleContent(v.Properties[cols.DiscordID])
 		if !ok {
 			return nil, fmt.Errorf("failed to fetch discord column")
 		}
 
-		name, ok := getRichTextContent(v.Properties["name"])
+		name, ok := getRichTextContent(v.Properties[cols.Name])
 		if !ok {
 			return nil, fmt.Errorf("failed to fetch name column")
 		}
 
-		notionID, ok := getRichTextContent(v.Properties["notion_id"])
+		notionID, ok := getRichTextContent(v.Properties[cols.NotionID])
 		if !ok {
 			return nil, fmt.Errorf("failed to fetch notion column")
 		}
 
-		currency, ok := getSelectContent(v.Properties["currency"])
+		currency, ok := getSelectContent(v.Properties[cols.Currency])
 		if !ok {
 			return nil, fmt.Errorf("failed to fetch currency column")
 		}
 
+		var threshold *float64
+		if t, ok := thresholds[v.ID]; ok {
+			threshold = &t
+		}
+
 		users = append(users, &domain.User{
-			DiscordID: discordID,
-			Name:      name,
-			NotionID:  notionID,
-			Currency:  domain.Currency(currency),
+			DiscordID:         discordID,
+			Name:              name,
+			NotionID:          notionID,
+			Currency:          domain.Currency(currency),
+			ReminderThreshold: threshold,
 		})
 	}
 
 	return users, nil
 }
 
+// reminderThresholds returns the reminder_threshold of the TBL-001 rows that set
+// one. notionapi reads an empty number cell as 0, so those rows are found with
+// an is_not_empty query, which is skipped when the optional column is absent.
+func (r *Repository) reminderThresholds(
+	ctx context.Context, pages []notionapi.Page,
+) (map[notionapi.ObjectID]float64, error) {
+	col := r.schema.Users.Threshold
+
+	hasColumn := slices.ContainsFunc(pages, func(p notionapi.Page) bool {
+		_, ok := p.Properties[col].(*notionapi.NumberProperty)
+		return ok
+	})
+	if !hasColumn {
+		return nil, nil
+	}
+
+	set, err := queryAll(ctx, r.db, r.userDBID, &notionapi.DatabaseQueryRequest{
+		Filter: &notionapi.PropertyFilter{
+			Property: col,
+			Number:   &notionapi.NumberFilterCondition{IsNotEmpty: true},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("query reminder thresholds: %w", err)
+	}
+
+	thresholds := make(map[notionapi.ObjectID]float64, len(set))
+	for _, p := range set {
+		thresholds[p.ID], _ = getNumberContent(p.Properties[col])
+	}
+
+	return thresholds, nil
+}
+
 func (r *Repository) GetUserByDiscordID(ctx context.Context, discordID string) (*domain.User, error) {
 	users, err := r.GetUsers(ctx)
 	if err != nil {
@@ -81,81 +129,89 @@ func (r *Repository) GetUserByDiscordID(ctx context.Context, discordID string) (
 		}
 	}
 
-	return nil, fmt.Errorf("user not found for discord_id: %s", discordID)
+	return nil, fmt.Errorf("%w for discord_id: %s", port.ErrUserNotFound, discordID)
 }
 
-func (r *Repository) GetUnpaidAmount(
-	ctx context.Context, userDatabaseID string, currency domain.Currency,
-) (float64, error) {
-	col, ok := currencyColumnMap[currency]
-	if !ok {
-		return 0, fmt.Errorf("unsupported currency: %s", currency)
-	}
+func (r *Repository) GetUnpaidItems(ctx context.Context, userDatabaseID string) ([]domain.UnpaidItem, error) {
+	cols := r.schema.Transactions
 
 	filter := &notionapi.PropertyFilter{
-		Property: "付款狀況",
-		Select:   &notionapi.SelectFilterCondition{Equals: "尚未付款"},
+		Property: cols.PaymentStatus,
+		Select:   &notionapi.SelectFilterCondition{Equals: cols.StatusUnpaid},
 	}
 
-	res, err := r.db.Query(ctx, notionapi.DatabaseID(userDatabaseID), &notionapi.DatabaseQueryRequest{
+	pages, err := queryAll(ctx, r.db, notionapi.DatabaseID(userDatabaseID), &notionapi.DatabaseQueryRequest{
 		Filter: filter,
 	})
 	if err != nil {
-		return 0, fmt.Errorf("notion database query failed: %w", err)
-	}
-
-	total := float64(0)
-
-	for _, p := range res.Results {
-		amount, ok := getNumberContent(p.Properties[col])
-		if !ok {
-			return 0, fmt.Errorf("failed to fetch amount column")
-		}
-
-		total += amount
+		return nil, err
 	}
 
-	return total, nil
+	return r.toUnpaidItems(pages)
 }
 
-func (r *Repository) GetOthersUnpaidAmount(
-	ctx context.Context, buyerName string, currency domain.Currency,
-) (float64, error) {
-	col, ok := currencyColumnMap[currency]
-	if !ok {
-		return 0, fmt.Errorf("unsupported currency: %s", currency)
-	}
+func (r *Repository) GetOthersUnpaidItems(ctx context.Context, buyerName string) ([]domain.UnpaidItem, error) {
+	cols := r.schema.Transactions
 
 	filter := notionapi.AndCompoundFilter{
 		notionapi.PropertyFilter{
-			Property: "購買人",
+			Property: cols.Buyer,
 			Select:   &notionapi.SelectFilterCondition{Equals: buyerName},
 		},
 		notionapi.PropertyFilter{
-			Property: "付款狀況",
-			Select:   &notionapi.SelectFilterCondition{Equals: "尚未付款"},
+			Property: cols.PaymentStatus,
+			Select:   &notionapi.SelectFilterCondition{Equals: cols.StatusUnpaid},
 		},
 	}
 
-	res, err := r.db.Query(ctx, r.othersDBID, &notionapi.DatabaseQueryRequest{
+	pages, err := queryAll(ctx, r.db, r.othersDBID, &notionapi.DatabaseQueryRequest{
 		Filter: filter,
 	})
 	if err != nil {
-		return 0, fmt.Errorf("notion database query failed: %w", err)
+		return nil, err
 	}
 
-	total := float64(0)
+	return r.toUnpaidItems(pages)
+}
+
+func (r *Repository) toUnpaidItems(pages []notionapi.Page) ([]domain.UnpaidItem, error) {
+	cols := r.schema.Transactions
+	items := make([]domain.UnpaidItem, 0, len(pages))
+
+	for _, p := range pages {
+		twd, ok := getNumberContent(p.Properties[cols.TWDAmount])
+		if !ok {
+			return nil, fmt.Errorf("failed to fetch amount column")
+		}
 
-	for _, p := range res.Results {
-		amount, ok := getNumberContent(p.Properties[col])
+		jpy, ok := getNumberContent(p.Properties[cols.JPYAmount])
 		if !ok {
-			return 0, fmt.Errorf("failed to fetch amount column")
+			return nil, fmt.Errorf("failed to fetch amount column")
 		}
 
-		total += amount
+		// Descriptive columns are optional; an untitled or untracked row is still a debt.
+		name, _ := getTitleContent(p.Properties[cols.ItemName])
+		itemStatus, _ := getSelectContent(p.Properties[cols.ItemStatus])
+		shop, _ := getSelectContent(p.Properties[cols.Shop])
+
+		createdAt, ok := getCreatedTimeContent(p.Properties[cols.CreatedTime])
+		if !ok {
+			createdAt = p.CreatedTime
+		}
+
+		items = append(items, domain.UnpaidItem{
+			PageID:     string(p.ID),
+			ItemName:   name,
+			TWDAmount:  twd,
+			JPYAmount:  jpy,
+			ItemStatus: cols.itemStatus(itemStatus),
+			Shop:       shop,
+			CreatedAt:  createdAt,
+			URL:        p.URL,
+		})
 	}
 
-	return total, nil
+	return items, nil
 }
 
 func getTitleContent(p notionapi.Property) (string, bool) {
@@ -193,3 +249,30 @@ func getNumberContent(p notionapi.Property) (float64, bool) {
 
 	return 0, false
 }
+
+func getCreatedTimeContent(p notionapi.Property) (time.Time, bool) {
+	ctp, ok := p.(*notionapi.CreatedTimeProperty)
+	if ok && !ctp.CreatedTime.IsZero() {
+		return ctp.CreatedTime, true
+	}
+
+	return time.Time{}, false
+}
+
+func getURLContent(p notionapi.Property) (string, bool) {
+	up, ok := p.(*notionapi.URLProperty)
+	if ok && up.URL != "" {
+		return up.URL, true
+	}
+
+	return "", false
+}
+
+func getDateContent(p notionapi.Property) (time.Time, bool) {
+	dp, ok := p.(*notionapi.DateProperty)
+	if ok && dp.Date != nil && dp.Date.Start != nil {
+		return time.Time(*dp.Date.Start), true
+	}
+
+	return time.Time{}, false
+}
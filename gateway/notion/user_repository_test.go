@@ -3,12 +3,15 @@ package notion
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/jomei/notionapi"
 	"github.com/stretchr/testify/require"
 
 	"github.com/xgnid-tw/gx5/domain"
+	"github.com/xgnid-tw/gx5/port"
 )
 
 // --- GetUsers tests ---
@@ -35,6 +38,68 @@ func TestGetUsers_Success(t *testing.T) {
 	require.Equal(t, domain.CurrencyTWD, users[0].Currency)
 }
 
+func TestGetUsers_ReminderThreshold(t *testing.T) {
+	pages := []notionapi.Page{
+		makeUserPage("111", "Alice", "abc", "TWD"),
+		makeUserPage("222", "Bob", "def", "JPY"),
+		makeUserPage("333", "Carol", "ghi", "TWD"),
+	}
+	for n, threshold := range []float64{5000, 0, 0} {
+		pages[n].ID = notionapi.ObjectID(fmt.Sprintf("page-%d", n))
+		pages[n].Properties["reminder_threshold"] = &notionapi.NumberProperty{Number: threshold}
+	}
+
+	db := &mockDatabaseService{
+		queryFn: func(
+			_ context.Context, _ notionapi.DatabaseID, req *notionapi.DatabaseQueryRequest,
+		) (*notionapi.DatabaseQueryResponse, error) {
+			if req.Filter == nil {
+				return &notionapi.DatabaseQueryResponse{Results: pages}, nil
+			}
+
+			filter, ok := req.Filter.(*notionapi.PropertyFilter)
+			require.True(t, ok)
+			require.Equal(t, "reminder_threshold", filter.Property)
+			require.True(t, filter.Number.IsNotEmpty)
+
+			// Carol's cell is empty; notionapi reads it as 0 just like Bob's.
+			return &notionapi.DatabaseQueryResponse{Results: pages[:2]}, nil
+		},
+	}
+
+	repo := newTestRepository(db, "user-db")
+	users, err := repo.GetUsers(context.Background())
+
+	require.NoError(t, err)
+	require.InDelta(t, 5000, *users[0].ReminderThreshold, 0.001)
+	require.NotNil(t, users[1].ReminderThreshold)
+	require.Zero(t, *users[1].ReminderThreshold, "0 is a threshold of its own")
+	require.Nil(t, users[2].ReminderThreshold, "an empty cell keeps the default")
+}
+
+func TestGetUsers_NoReminderThresholdColumn(t *testing.T) {
+	queries := 0
+
+	db := &mockDatabaseService{
+		queryFn: func(
+			_ context.Context, _ notionapi.DatabaseID, _ *notionapi.DatabaseQueryRequest,
+		) (*notionapi.DatabaseQueryResponse, error) {
+			queries++
+
+			return &notionapi.DatabaseQueryResponse{
+				Results: []notionapi.Page{makeUserPage("111", "Alice", "abc", "TWD")},
+			}, nil
+		},
+	}
+
+	repo := newTestRepository(db, "user-db")
+	users, err := repo.GetUsers(context.Background())
+
+	require.NoError(t, err)
+	require.Nil(t, users[0].ReminderThreshold, "the column is optional")
+	require.Equal(t, 1, queries, "no threshold query without the column")
+}
+
 func TestGetUsers_MultipleUsers(t *testing.T) {
 	db := &mockDatabaseService{
 		queryFn: func(
@@ -60,6 +125,25 @@ func TestGetUsers_MultipleUsers(t *testing.T) {
 	require.Equal(t, domain.CurrencyJPY, users[1].Currency)
 }
 
+func TestGetUsers_MultiplePages(t *testing.T) {
+	db := &mockDatabaseService{
+		queryFn: pagedQueryFn(
+			[]notionapi.Page{
+				makeUserPage("111", "Alice", "abc", "TWD"),
+				makeUserPage("222", "Bob", "def", "JPY"),
+			},
+			[]notionapi.Page{makeUserPage("333", "Carol", "ghi", "TWD")},
+		),
+	}
+
+	repo := newTestRepository(db, "user-db")
+	users, err := repo.GetUsers(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, users, 3)
+	require.Equal(t, "Carol", users[2].Name)
+}
+
 func TestGetUsers_QueryError(t *testing.T) {
 	db := &mockDatabaseService{
 		queryFn: func(
@@ -188,183 +272,177 @@ func TestGetUsers_MissingCurrency(t *testing.T) {
 	require.ErrorContains(t, err, "failed to fetch currency column")
 }
 
-// --- GetUnpaidAmount tests ---
+// --- GetUnpaidItems tests ---
 
-func TestGetUnpaidAmount_Success(t *testing.T) {
+func TestGetUnpaidItems_Success(t *testing.T) {
 	db := &mockDatabaseService{
 		queryFn: func(
-			_ context.Context, id notionapi.DatabaseID, _ *notionapi.DatabaseQueryRequest,
+			_ context.Context, id notionapi.DatabaseID, req *notionapi.DatabaseQueryRequest,
 		) (*notionapi.DatabaseQueryResponse, error) {
 			require.Equal(t, notionapi.DatabaseID("tx-db"), id)
 
+			filter, ok := req.Filter.(*notionapi.PropertyFilter)
+			require.True(t, ok)
+			require.Equal(t, "付款狀況", filter.Property)
+			require.Equal(t, "尚未付款", filter.Select.Equals)
+
 			return &notionapi.DatabaseQueryResponse{
 				Results: []notionapi.Page{
-					makeAmountPage("台幣", 1000),
-					makeAmountPage("台幣", 500),
+					makeItemPage("p1", "Acrylic Stand", 720, 3000),
+					makeItemPage("p2", "CD", 480, 2000),
 				},
 			}, nil
 		},
 	}
 
 	repo := newTestRepository(db, "user-db")
-	total, err := repo.GetUnpaidAmount(context.Background(), "tx-db", domain.CurrencyTWD)
+	items, err := repo.GetUnpaidItems(context.Background(), "tx-db")
 
 	require.NoError(t, err)
-	require.Equal(t, 1500.0, total)
+	require.Equal(t, []domain.UnpaidItem{
+		{PageID: "p1", ItemName: "Acrylic Stand", TWDAmount: 720, JPYAmount: 3000},
+		{PageID: "p2", ItemName: "CD", TWDAmount: 480, JPYAmount: 2000},
+	}, items)
 }
 
-func TestGetUnpaidAmount_JPY(t *testing.T) {
+func TestGetUnpaidItems_LedgerDetails(t *testing.T) {
+	created := time.Date(2026, 9, 1, 12, 0, 0, 0, time.UTC)
+	fallback := time.Date(2026, 9, 2, 8, 0, 0, 0, time.UTC)
+
+	tracked := makeItemPage("p1", "Acrylic Stand", 720, 3000)
+	tracked.URL = "https://www.notion.so/p1"
+	tracked.Properties["物品狀況"] = &notionapi.SelectProperty{Select: notionapi.Option{Name: "已回台"}}
+	tracked.Properties["購買途徑"] = &notionapi.SelectProperty{Select: notionapi.Option{Name: "Animate"}}
+	tracked.Properties["建立時間"] = &notionapi.CreatedTimeProperty{CreatedTime: created}
+
+	untracked := makeItemPage("p2", "CD", 480, 2000)
+	untracked.CreatedTime = fallback
+
 	db := &mockDatabaseService{
 		queryFn: func(
-			_ context.Context, _ notionapi.DatabaseID, _ *notionapi.DatabaseQueryRequest,
+			context.Context, notionapi.DatabaseID, *notionapi.DatabaseQueryRequest,
 		) (*notionapi.DatabaseQueryResponse, error) {
-			return &notionapi.DatabaseQueryResponse{
-				Results: []notionapi.Page{
-					makeAmountPage("日幣", 3000),
-					makeAmountPage("日幣", 5000),
-				},
-			}, nil
+			return &notionapi.DatabaseQueryResponse{Results: []notionapi.Page{tracked, untracked}}, nil
 		},
 	}
 
 	repo := newTestRepository(db, "user-db")
-	total, err := repo.GetUnpaidAmount(context.Background(), "tx-db", domain.CurrencyJPY)
+	items, err := repo.GetUnpaidItems(context.Background(), "tx-db")
 
 	require.NoError(t, err)
-	require.Equal(t, 8000.0, total)
+	require.Equal(t, domain.UnpaidItem{
+		PageID: "p1", ItemName: "Acrylic Stand", TWDAmount: 720, JPYAmount: 3000,
+		ItemStatus: "已回台", Shop: "Animate", CreatedAt: created, URL: "https://www.notion.so/p1",
+	}, items[0])
+	require.Empty(t, items[1].ItemStatus)
+	require.Empty(t, items[1].Shop)
+	require.Equal(t, fallback, items[1].CreatedAt)
 }
 
-func TestGetUnpaidAmount_EmptyResult(t *testing.T) {
+func TestGetUnpaidItems_MultiplePages(t *testing.T) {
 	db := &mockDatabaseService{
-		queryFn: func(
-			context.Context, notionapi.DatabaseID, *notionapi.DatabaseQueryRequest,
-		) (*notionapi.DatabaseQueryResponse, error) {
-			return &notionapi.DatabaseQueryResponse{Results: []notionapi.Page{}}, nil
-		},
+		queryFn: pagedQueryFn(
+			[]notionapi.Page{makeItemPage("p1", "A", 1000, 4000), makeItemPage("p2", "B", 500, 2000)},
+			[]notionapi.Page{makeItemPage("p3", "C", 700, 3000)},
+		),
 	}
 
 	repo := newTestRepository(db, "user-db")
-	total, err := repo.GetUnpaidAmount(context.Background(), "tx-db", domain.CurrencyTWD)
+	items, err := repo.GetUnpaidItems(context.Background(), "tx-db")
 
 	require.NoError(t, err)
-	require.Equal(t, 0.0, total)
+	require.Len(t, items, 3)
+	require.Equal(t, "p3", items[2].PageID)
 }
 
-func TestGetUnpaidAmount_QueryError(t *testing.T) {
+func TestGetUnpaidItems_EmptyResult(t *testing.T) {
 	db := &mockDatabaseService{
 		queryFn: func(
 			context.Context, notionapi.DatabaseID, *notionapi.DatabaseQueryRequest,
 		) (*notionapi.DatabaseQueryResponse, error) {
-			return nil, errors.New("api down")
+			return &notionapi.DatabaseQueryResponse{Results: []notionapi.Page{}}, nil
 		},
 	}
 
 	repo := newTestRepository(db, "user-db")
-	_, err := repo.GetUnpaidAmount(context.Background(), "tx-db", domain.CurrencyTWD)
+	items, err := repo.GetUnpaidItems(context.Background(), "tx-db")
 
-	require.Error(t, err)
-	require.ErrorContains(t, err, "notion database query failed")
+	require.NoError(t, err)
+	require.Empty(t, items)
 }
 
-func TestGetUnpaidAmount_UnsupportedCurrency(t *testing.T) {
+func TestGetUnpaidItems_QueryError(t *testing.T) {
 	db := &mockDatabaseService{
 		queryFn: func(
 			context.Context, notionapi.DatabaseID, *notionapi.DatabaseQueryRequest,
 		) (*notionapi.DatabaseQueryResponse, error) {
-			return &notionapi.DatabaseQueryResponse{Results: []notionapi.Page{}}, nil
+			return nil, errors.New("api down")
 		},
 	}
 
 	repo := newTestRepository(db, "user-db")
-	_, err := repo.GetUnpaidAmount(context.Background(), "tx-db", domain.Currency("USD"))
+	_, err := repo.GetUnpaidItems(context.Background(), "tx-db")
 
-	require.Error(t, err)
-	require.ErrorContains(t, err, "unsupported currency")
+	require.ErrorContains(t, err, "notion database query failed")
 }
 
-func TestGetUnpaidAmount_MissingAmountColumn(t *testing.T) {
+func TestGetUnpaidItems_MissingAmountColumn(t *testing.T) {
 	db := &mockDatabaseService{
 		queryFn: func(
 			context.Context, notionapi.DatabaseID, *notionapi.DatabaseQueryRequest,
 		) (*notionapi.DatabaseQueryResponse, error) {
-			page := notionapi.Page{
-				Properties: notionapi.Properties{},
-			}
-
-			return &notionapi.DatabaseQueryResponse{Results: []notionapi.Page{page}}, nil
+			return &notionapi.DatabaseQueryResponse{Results: []notionapi.Page{makeAmountPage("台幣", 100)}}, nil
 		},
 	}
 
 	repo := newTestRepository(db, "user-db")
-	_, err := repo.GetUnpaidAmount(context.Background(), "tx-db", domain.CurrencyTWD)
+	_, err := repo.GetUnpaidItems(context.Background(), "tx-db")
 
-	require.Error(t, err)
 	require.ErrorContains(t, err, "failed to fetch amount column")
 }
 
-// --- GetOthersUnpaidAmount tests ---
-
-func TestGetOthersUnpaidAmount_Success(t *testing.T) {
+func TestGetOthersUnpaidItems_Success(t *testing.T) {
 	db := &mockDatabaseService{
 		queryFn: func(
-			_ context.Context, id notionapi.DatabaseID, _ *notionapi.DatabaseQueryRequest,
+			_ context.Context, id notionapi.DatabaseID, req *notionapi.DatabaseQueryRequest,
 		) (*notionapi.DatabaseQueryResponse, error) {
 			require.Equal(t, notionapi.DatabaseID("others-db"), id)
 
-			return &notionapi.DatabaseQueryResponse{
-				Results: []notionapi.Page{
-					makeAmountPage("台幣", 800),
-					makeAmountPage("台幣", 200),
-				},
-			}, nil
-		},
-	}
-
-	repo := newTestRepository(db, "user-db")
-	total, err := repo.GetOthersUnpaidAmount(context.Background(), "Alice", domain.CurrencyTWD)
+			filter, ok := req.Filter.(notionapi.AndCompoundFilter)
+			require.True(t, ok)
+			require.Len(t, filter, 2)
 
-	require.NoError(t, err)
-	require.Equal(t, 1000.0, total)
-}
-
-func TestGetOthersUnpaidAmount_JPY(t *testing.T) {
-	db := &mockDatabaseService{
-		queryFn: func(
-			_ context.Context, _ notionapi.DatabaseID, _ *notionapi.DatabaseQueryRequest,
-		) (*notionapi.DatabaseQueryResponse, error) {
 			return &notionapi.DatabaseQueryResponse{
-				Results: []notionapi.Page{
-					makeAmountPage("日幣", 4000),
-					makeAmountPage("日幣", 5000),
-				},
+				Results: []notionapi.Page{makeItemPage("p1", "Badge", 240, 1000)},
 			}, nil
 		},
 	}
 
 	repo := newTestRepository(db, "user-db")
-	total, err := repo.GetOthersUnpaidAmount(context.Background(), "Bob", domain.CurrencyJPY)
+	items, err := repo.GetOthersUnpaidItems(context.Background(), "Carol")
 
 	require.NoError(t, err)
-	require.Equal(t, 9000.0, total)
+	require.Len(t, items, 1)
+	require.Equal(t, "Badge", items[0].ItemName)
 }
 
-func TestGetOthersUnpaidAmount_EmptyResult(t *testing.T) {
+func TestGetOthersUnpaidItems_MultiplePages(t *testing.T) {
 	db := &mockDatabaseService{
-		queryFn: func(
-			context.Context, notionapi.DatabaseID, *notionapi.DatabaseQueryRequest,
-		) (*notionapi.DatabaseQueryResponse, error) {
-			return &notionapi.DatabaseQueryResponse{Results: []notionapi.Page{}}, nil
-		},
+		queryFn: pagedQueryFn(
+			[]notionapi.Page{makeItemPage("p1", "A", 1000, 4000)},
+			[]notionapi.Page{makeItemPage("p2", "B", 1250, 5000)},
+			[]notionapi.Page{makeItemPage("p3", "C", 250, 1000)},
+		),
 	}
 
 	repo := newTestRepository(db, "user-db")
-	total, err := repo.GetOthersUnpaidAmount(context.Background(), "Alice", domain.CurrencyTWD)
+	items, err := repo.GetOthersUnpaidItems(context.Background(), "Bob")
 
 	require.NoError(t, err)
-	require.Equal(t, 0.0, total)
+	require.Len(t, items, 3)
 }
 
-func TestGetOthersUnpaidAmount_QueryError(t *testing.T) {
+func TestGetOthersUnpaidItems_QueryError(t *testing.T) {
 	db := &mockDatabaseService{
 		queryFn: func(
 			context.Context, notionapi.DatabaseID, *notionapi.DatabaseQueryRequest,
@@ -374,28 +452,11 @@ func TestGetOthersUnpaidAmount_QueryError(t *testing.T) {
 	}
 
 	repo := newTestRepository(db, "user-db")
-	_, err := repo.GetOthersUnpaidAmount(context.Background(), "Alice", domain.CurrencyTWD)
+	_, err := repo.GetOthersUnpaidItems(context.Background(), "Carol")
 
-	require.Error(t, err)
 	require.ErrorContains(t, err, "notion database query failed")
 }
 
-func TestGetOthersUnpaidAmount_UnsupportedCurrency(t *testing.T) {
-	db := &mockDatabaseService{
-		queryFn: func(
-			context.Context, notionapi.DatabaseID, *notionapi.DatabaseQueryRequest,
-		) (*notionapi.DatabaseQueryResponse, error) {
-			return &notionapi.DatabaseQueryResponse{Results: []notionapi.Page{}}, nil
-		},
-	}
-
-	repo := newTestRepository(db, "user-db")
-	_, err := repo.GetOthersUnpaidAmount(context.Background(), "Alice", domain.Currency("USD"))
-
-	require.Error(t, err)
-	require.ErrorContains(t, err, "unsupported currency")
-}
-
 // --- GetUserByDiscordID tests ---
 
 func TestGetUserByDiscordID_Success(t *testing.T) {
@@ -421,6 +482,21 @@ func TestGetUserByDiscordID_Success(t *testing.T) {
 	require.Equal(t, domain.CurrencyTWD, user.Currency)
 }
 
+func TestGetUserByDiscordID_OnLaterPage(t *testing.T) {
+	db := &mockDatabaseService{
+		queryFn: pagedQueryFn(
+			[]notionapi.Page{makeUserPage("111", "Alice", "abc", "TWD")},
+			[]notionapi.Page{makeUserPage("222", "Bob", "def", "JPY")},
+		),
+	}
+
+	repo := newTestRepository(db, "user-db")
+	user, err := repo.GetUserByDiscordID(context.Background(), "222")
+
+	require.NoError(t, err)
+	require.Equal(t, "Bob", user.Name)
+}
+
 func TestGetUserByDiscordID_NotFound(t *testing.T) {
 	db := &mockDatabaseService{
 		queryFn: func(
@@ -433,7 +509,7 @@ func TestGetUserByDiscordID_NotFound(t *testing.T) {
 	repo := newTestRepository(db, "user-db")
 	_, err := repo.GetUserByDiscordID(context.Background(), "999")
 
-	require.Error(t, err)
+	require.ErrorIs(t, err, port.ErrUserNotFound)
 	require.ErrorContains(t, err, "user not found for discord_id")
 }
 
@@ -0,0 +1,91 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/go-co-op/gocron/v2"
+
+	"github.com/xgnid-tw/gx5/domain"
+	"github.com/xgnid-tw/gx5/port"
+)
+
+// DefaultDeadlineResync is how often the deadline jobs are re-derived from
+// Notion, which picks up new orders and deadlines edited by hand.
+const DefaultDeadlineResync = 15 * time.Minute
+
+const orderDeadlineTag = "order-deadline"
+
+// OrderDeadlineJobs keeps one gocron job per pending deadline event. Jobs only
+// live in memory; Sync rebuilds them from Notion at startup and on every resync.
+type OrderDeadlineJobs struct {
+	s  gocron.Scheduler
+	uc port.OrderDeadlineKeeper
+}
+
+func NewOrderDeadlineJobs(s gocron.Scheduler, uc port.OrderDeadlineKeeper) *OrderDeadlineJobs {
+	return &OrderDeadlineJobs{s: s, uc: uc}
+}
+
+// Start syncs the deadline jobs now and then every interval.
+func (j *OrderDeadlineJobs) Start(ctx context.Context, interval time.Duration) error {
+	err := j.Sync(ctx)
+	if err != nil {
+		log.Printf("initial order deadline sync failed: %s", err)
+	}
+
+	_, err = j.s.NewJob(
+		gocron.DurationJob(interval),
+		gocron.NewTask(func() {
+			syncErr := j.Sync(ctx)
+			if syncErr != nil {
+				log.Printf("order deadline sync failed: %s", syncErr)
+			}
+		}),
+	)
+	if err != nil {
+		return fmt.Errorf("schedule order deadline sync: %w", err)
+	}
+
+	return nil
+}
+
+// Sync replaces every scheduled deadline job with the events planned from
+// Notion. Events that are already due run immediately.
+func (j *OrderDeadlineJobs) Sync(ctx context.Context) error {
+	now := time.Now()
+
+	events, err := j.uc.Plan(ctx, now)
+	if err != nil {
+		return fmt.Errorf("plan order deadlines: %w", err)
+	}
+
+	j.s.RemoveByTags(orderDeadlineTag)
+
+	for _, e := range events {
+		start := gocron.OneTimeJobStartImmediately()
+		if e.At.After(now) {
+			start = gocron.OneTimeJobStartDateTime(e.At)
+		}
+
+		_, err = j.s.NewJob(
+			gocron.OneTimeJob(start),
+			gocron.NewTask(j.run, ctx, e),
+			gocron.WithTags(orderDeadlineTag),
+		)
+		if err != nil {
+			return fmt.Errorf("schedule deadline event for thread %s: %w", e.ThreadID, err)
+		}
+	}
+
+	return nil
+}
+
+func (j *OrderDeadlineJobs) run(ctx context.Context, e domain.DeadlineEvent) {
+	err := j.uc.Run(ctx, e)
+	if err != nil {
+		log.Printf("order deadline event for thread %s failed: %s", e.ThreadID, err)
+	}
+}
@@ -0,0 +1,92 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-co-op/gocron/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/xgnid-tw/gx5/domain"
+)
+
+type fakeDeadlineKeeper struct {
+	events  []domain.DeadlineEvent
+	planErr error
+	ran     chan domain.DeadlineEvent
+}
+
+func (f *fakeDeadlineKeeper) Plan(context.Context, time.Time) ([]domain.DeadlineEvent, error) {
+	return f.events, f.planErr
+}
+
+func (f *fakeDeadlineKeeper) Run(_ context.Context, e domain.DeadlineEvent) error {
+	f.ran <- e
+	return nil
+}
+
+func newTestScheduler(t *testing.T) gocron.Scheduler {
+	t.Helper()
+
+	s, err := gocron.NewScheduler()
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = s.Shutdown() })
+
+	return s
+}
+
+func TestSync_ReplacesJobs(t *testing.T) {
+	s := newTestScheduler(t)
+	future := time.Now().Add(time.Hour)
+
+	keeper := &fakeDeadlineKeeper{
+		events: []domain.DeadlineEvent{
+			{ThreadID: "thread-1", At: future, Left: time.Hour},
+			{ThreadID: "thread-1", At: future.Add(time.Hour)},
+		},
+	}
+
+	jobs := NewOrderDeadlineJobs(s, keeper)
+
+	require.NoError(t, jobs.Sync(context.Background()))
+	require.NoError(t, jobs.Sync(context.Background()))
+	require.Len(t, s.Jobs(), 2)
+}
+
+func TestSync_RunsDueEventImmediately(t *testing.T) {
+	s := newTestScheduler(t)
+	due := domain.DeadlineEvent{ThreadID: "thread-1", At: time.Now().Add(-time.Minute)}
+
+	keeper := &fakeDeadlineKeeper{
+		events: []domain.DeadlineEvent{due},
+		ran:    make(chan domain.DeadlineEvent, 1),
+	}
+
+	s.Start()
+	require.NoError(t, NewOrderDeadlineJobs(s, keeper).Sync(context.Background()))
+
+	select {
+	case e := <-keeper.ran:
+		require.Equal(t, due, e)
+	case <-time.After(time.Second):
+		t.Fatal("due event did not run")
+	}
+}
+
+func TestSync_PlanErrorKeepsJobs(t *testing.T) {
+	s := newTestScheduler(t)
+
+	keeper := &fakeDeadlineKeeper{
+		events: []domain.DeadlineEvent{{ThreadID: "thread-1", At: time.Now().Add(time.Hour)}},
+	}
+
+	jobs := NewOrderDeadlineJobs(s, keeper)
+	require.NoError(t, jobs.Sync(context.Background()))
+
+	keeper.planErr = errors.New("notion down")
+	require.ErrorContains(t, jobs.Sync(context.Background()), "plan order deadlines")
+	require.Len(t, s.Jobs(), 1)
+}
@@ -1,7 +1,9 @@
 package main
 
 import (
+	"context"
 	"log"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
@@ -13,9 +15,11 @@ import (
 	"github.com/jomei/notionapi"
 
 	"github.com/xgnid-tw/gx5/config"
+	cachegw "github.com/xgnid-tw/gx5/gateway/cache"
 	discordgw "github.com/xgnid-tw/gx5/gateway/discord"
 	discordcmd "github.com/xgnid-tw/gx5/gateway/discord/command"
 	notiongw "github.com/xgnid-tw/gx5/gateway/notion"
+	schedulergw "github.com/xgnid-tw/gx5/gateway/scheduler"
 	"github.com/xgnid-tw/gx5/usecase"
 )
 
@@ -31,6 +35,11 @@ func main() {
 		log.Fatalf("invalid config: %s", err)
 	}
 
+	schema, err := notiongw.LoadSchema(cfg.NotionSchemaFile)
+	if err != nil {
+		log.Fatalf("invalid NOTION_SCHEMA_FILE: %s", err)
+	}
+
 	// Initialize external service clients
 	dc, err := discordgo.New(cfg.DiscordToken)
 	if err != nil {
@@ -39,7 +48,18 @@ func main() {
 
 	dc.Identify.Intents = discordgo.IntentsAll
 
-	notionClient := notionapi.NewClient(notionapi.Token(cfg.NotionToken))
+	// Notion calls share one limiter; the client itself does not retry so that
+	// the retrying decorators below own backoff and Retry-After handling.
+	notionLimiter := notiongw.NewLimiter(notiongw.DefaultRequestsPerSecond)
+	notionClient := notionapi.NewClient(
+		notionapi.Token(cfg.NotionToken),
+		notionapi.WithRetry(1),
+		notionapi.WithHTTPClient(&http.Client{
+			Transport: notiongw.NewRetryAfterTransport(http.DefaultTransport, notionLimiter),
+		}),
+	)
+	notionDB := notiongw.NewRetryingDatabaseService(notionClient.Database, notionLimiter)
+	notionPage := notiongw.NewRetryingPageService(notionClient.Page, notionLimiter)
 
 	// Load Asia/Tokyo timezone for scheduler
 	loc, err := time.LoadLocation("Asia/Tokyo")
@@ -48,20 +68,54 @@ func main() {
 	}
 
 	// Wire dependencies: gateway adapters -> use cases
-	repo := notiongw.NewRepository(notionClient.Database, cfg.NotionUserDBID, cfg.NotionOthersDBID)
-	notifier := discordgw.NewNotifier(dc, cfg.DiscordLogChannelID)
-	notifyUnpaidUC := usecase.NewNotifyUnpaid(repo, notifier, cfg.NotionOthersDBID)
+	repo := cachegw.NewUserRepository(
+		notiongw.NewRepository(notionDB, cfg.NotionUserDBID, cfg.NotionOthersDBID, schema),
+		cfg.MemberCacheTTL,
+	)
+	reminderTemplates, err := discordgw.LoadReminderTemplates(cfg.ReminderTemplateDir)
+	if err != nil {
+		log.Fatalf("invalid reminder templates: %s", err)
+	}
+
+	notifier := discordgw.NewNotifier(dc, cfg.DiscordLogChannelID, reminderTemplates, cfg.ReminderPaymentInfo)
+	notifyUnpaidUC := usecase.NewNotifyUnpaid(repo, notifier, cfg.NotionOthersDBID, cfg.ReminderThresholds)
 
-	orderRepo := notiongw.NewOrderRepository(notionClient.Page, cfg.NotionOrderDBID)
+	orderRepo := notiongw.NewOrderRepository(notionPage, notionDB, cfg.NotionOrderDBID, schema)
 	threadCreator := discordgw.NewThreadCreator(dc)
 	memberAdder := discordgw.NewMemberAdder(dc, cfg.DiscordGuildID)
 	createOrderUC := usecase.NewCreateOrder(orderRepo, threadCreator, memberAdder, cfg.TagRoleMap)
+	updateOrderStatusUC := usecase.NewUpdateOrderStatus(orderRepo, threadCreator)
+	orderDeadlineUC := usecase.NewOrderDeadline(orderRepo, threadCreator, loc)
+
+	txRepo := notiongw.NewTransactionRepository(notionPage, schema)
+	buyUC := usecase.NewRegisterBuyRecord(repo, txRepo, orderRepo, cfg.ExchangeRateJPYTWD, cfg.NotionOthersDBID)
 
-	txRepo := notiongw.NewTransactionRepository(notionClient.Page)
-	buyUC := usecase.NewRegisterBuyRecord(repo, txRepo, cfg.ExchangeRateJPYTWD)
+	itemRepo := notiongw.NewItemRepository(notionPage, notionDB, schema)
+	trackItemStatusUC := usecase.NewTrackItemStatus(orderRepo, itemRepo, repo, notifier, cfg.NotionOthersDBID)
+	summarizeOrderUC := usecase.NewSummarizeOrder(orderRepo, itemRepo, repo, threadCreator, cfg.NotionOthersDBID)
+	reviseBuyUC := usecase.NewReviseBuyRecord(
+		itemRepo, txRepo, repo, cfg.ExchangeRateJPYTWD, cfg.NotionOthersDBID, cfg.BuyRevisionWindow,
+	)
+
+	paymentRepo := notiongw.NewPaymentRepository(notionPage, schema)
+	settlePaymentUC := usecase.NewSettlePayment(repo, paymentRepo, cfg.NotionOthersDBID)
+	checkBalanceUC := usecase.NewCheckBalance(repo, cfg.NotionOthersDBID, cfg.ReminderThresholds)
+	listDebtsUC := usecase.NewListDebts(repo, cfg.NotionOthersDBID, cfg.ReminderThresholds)
 
 	// Register Discord application commands
-	cmdHandler := discordcmd.NewHandler(dc, cfg.DiscordAppID)
+	// Commands live in the configured guild, where updates show up at once, unless
+	// they are registered globally
+	commandGuildID, staleGuildID := cfg.DiscordGuildID, ""
+	if cfg.DiscordGlobalCommands {
+		commandGuildID, staleGuildID = "", cfg.DiscordGuildID
+	}
+
+	cmdHandler := discordcmd.NewHandler(dc, cfg.DiscordAppID, commandGuildID)
+	cmdHandler.Use(
+		discordcmd.InteractionContext(discordcmd.DefaultInteractionTimeout),
+		discordcmd.Recover(),
+		discordcmd.LogLatency(),
+	)
 
 	// Scheduler for one-shot delayed jobs
 	s, err := gocron.NewScheduler(gocron.WithLocation(loc))
@@ -70,8 +124,36 @@ func main() {
 	}
 
 	discordcmd.RegisterNewOrderCommand(cmdHandler, createOrderUC)
-	discordcmd.RegisterBuyCommand(cmdHandler, buyUC)
+	discordcmd.RegisterOrderCommand(cmdHandler, updateOrderStatusUC, summarizeOrderUC)
+	discordcmd.RegisterBuyCommand(cmdHandler, buyUC, reviseBuyUC, summarizeOrderUC)
 	discordcmd.RegisterDebtReminderCommand(cmdHandler, notifyUnpaidUC, s)
+	discordcmd.RegisterMembersCommand(cmdHandler, repo)
+	discordcmd.RegisterPaidCommand(cmdHandler, settlePaymentUC)
+	discordcmd.RegisterBalanceCommand(cmdHandler, checkBalanceUC)
+	discordcmd.RegisterDebtsCommand(cmdHandler, listDebtsUC)
+	discordcmd.RegisterItemStatusCommand(cmdHandler, trackItemStatusUC)
+
+	// Loading the members also warms the cache so the first /buy does not wait on Notion
+	users, err := repo.GetUsers(context.Background())
+	if err != nil {
+		log.Printf("initial member load failed: %s", err)
+	}
+
+	// Verify the Notion databases before accepting commands
+	schemaValidator := notiongw.NewSchemaValidator(
+		notionDB, cfg.NotionUserDBID, cfg.NotionOthersDBID, cfg.NotionOrderDBID, schema,
+	)
+
+	report, err := schemaValidator.Validate(context.Background(), users)
+	if err != nil {
+		log.Fatalf("error verifying notion schema: %s", err)
+	}
+
+	log.Print(report)
+
+	if report.HasSharedProblems() {
+		log.Fatal("notion schema mismatch in shared databases, fix the columns above and restart")
+	}
 
 	// Open Discord connection and start the scheduler
 	err = dc.Open()
@@ -79,7 +161,8 @@ func main() {
 		log.Fatalf("error opening connection: %s", err)
 	}
 
-	// Sync application commands after connection is open
+	// Sync application commands after connection is open; they stay registered
+	// across restarts and are only rewritten when they changed
 	err = cmdHandler.SyncCommands()
 	if err != nil {
 		_ = dc.Close()
@@ -87,9 +170,21 @@ func main() {
 		log.Fatalf("error syncing commands: %s", err)
 	}
 
-	defer cmdHandler.UnregisterAll()
+	err = cmdHandler.ClearCommands(staleGuildID)
+	if err != nil {
+		log.Printf("error removing commands from the other scope: %s", err)
+	}
+
 	defer dc.Close()
 
+	// Deadline reminders and auto-close are re-derived from the open orders in Notion
+	deadlineJobs := schedulergw.NewOrderDeadlineJobs(s, orderDeadlineUC)
+
+	err = deadlineJobs.Start(context.Background(), schedulergw.DefaultDeadlineResync)
+	if err != nil {
+		log.Printf("order deadline jobs not scheduled: %s", err)
+	}
+
 	log.Print("Bot is now running. Press CTRL-C to exit.")
 
 	s.Start()
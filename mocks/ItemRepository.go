@@ -0,0 +1,107 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	domain "github.com/xgnid-tw/gx5/domain"
+)
+
+// ItemRepository is an autogenerated mock type for the ItemRepository type
+type ItemRepository struct {
+	mock.Mock
+}
+
+// GetItem provides a mock function with given fields: ctx, pageID
+func (_m *ItemRepository) GetItem(ctx context.Context, pageID string) (*domain.ItemRecord, error) {
+	ret := _m.Called(ctx, pageID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetItem")
+	}
+
+	var r0 *domain.ItemRecord
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.ItemRecord, error)); ok {
+		return rf(ctx, pageID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.ItemRecord); ok {
+		r0 = rf(ctx, pageID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.ItemRecord)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, pageID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOrderItems provides a mock function with given fields: ctx, databaseID, orderID
+func (_m *ItemRepository) GetOrderItems(ctx context.Context, databaseID string, orderID string) ([]domain.ItemRecord, error) {
+	ret := _m.Called(ctx, databaseID, orderID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOrderItems")
+	}
+
+	var r0 []domain.ItemRecord
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) ([]domain.ItemRecord, error)); ok {
+		return rf(ctx, databaseID, orderID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) []domain.ItemRecord); ok {
+		r0 = rf(ctx, databaseID, orderID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.ItemRecord)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, databaseID, orderID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateItemStatus provides a mock function with given fields: ctx, pageID, status
+func (_m *ItemRepository) UpdateItemStatus(ctx context.Context, pageID string, status domain.ItemStatus) error {
+	ret := _m.Called(ctx, pageID, status)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateItemStatus")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.ItemStatus) error); ok {
+		r0 = rf(ctx, pageID, status)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewItemRepository creates a new instance of ItemRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewItemRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ItemRepository {
+	mock := &ItemRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
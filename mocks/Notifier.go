@@ -14,17 +14,53 @@ type Notifier struct {
 	mock.Mock
 }
 
-// Notify provides a mock function with given fields: ctx, user, debug
-func (_m *Notifier) Notify(ctx context.Context, user domain.User, debug bool) error {
-	ret := _m.Called(ctx, user, debug)
+// Notify provides a mock function with given fields: ctx, balance, debug
+func (_m *Notifier) Notify(ctx context.Context, balance domain.Balance, debug bool) error {
+	ret := _m.Called(ctx, balance, debug)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Notify")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, domain.User, bool) error); ok {
-		r0 = rf(ctx, user, debug)
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Balance, bool) error); ok {
+		r0 = rf(ctx, balance, debug)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NotifyItemsArrived provides a mock function with given fields: ctx, user, items
+func (_m *Notifier) NotifyItemsArrived(ctx context.Context, user domain.User, items []domain.ItemRecord) error {
+	ret := _m.Called(ctx, user, items)
+
+	if len(ret) == 0 {
+		panic("no return value specified for NotifyItemsArrived")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.User, []domain.ItemRecord) error); ok {
+		r0 = rf(ctx, user, items)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReportReminder provides a mock function with given fields: ctx, report
+func (_m *Notifier) ReportReminder(ctx context.Context, report domain.ReminderReport) error {
+	ret := _m.Called(ctx, report)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReportReminder")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.ReminderReport) error); ok {
+		r0 = rf(ctx, report)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -32,6 +32,84 @@ func (_m *OrderRepository) CreateOrder(ctx context.Context, order domain.Order)
 	return r0
 }
 
+// GetOpenOrders provides a mock function with given fields: ctx
+func (_m *OrderRepository) GetOpenOrders(ctx context.Context) ([]domain.Order, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOpenOrders")
+	}
+
+	var r0 []domain.Order
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]domain.Order, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []domain.Order); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Order)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOrderByThreadID provides a mock function with given fields: ctx, threadID
+func (_m *OrderRepository) GetOrderByThreadID(ctx context.Context, threadID string) (*domain.Order, error) {
+	ret := _m.Called(ctx, threadID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOrderByThreadID")
+	}
+
+	var r0 *domain.Order
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.Order, error)); ok {
+		return rf(ctx, threadID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.Order); ok {
+		r0 = rf(ctx, threadID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Order)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, threadID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateOrderStatus provides a mock function with given fields: ctx, pageID, status
+func (_m *OrderRepository) UpdateOrderStatus(ctx context.Context, pageID string, status domain.OrderStatus) error {
+	ret := _m.Called(ctx, pageID, status)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateOrderStatus")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.OrderStatus) error); ok {
+		r0 = rf(ctx, pageID, status)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewOrderRepository creates a new instance of OrderRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewOrderRepository(t interface {
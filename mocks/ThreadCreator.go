@@ -1,4 +1,4 @@
-// Code generated manually. DO NOT EDIT.
+// Code generated by mockery v2.53.5. DO NOT EDIT.
 
 package mocks
 
@@ -6,17 +6,34 @@ import (
 	context "context"
 
 	mock "github.com/stretchr/testify/mock"
+	domain "github.com/xgnid-tw/gx5/domain"
 )
 
-// ThreadCreator is a mock type for the ThreadCreator type.
+// ThreadCreator is an autogenerated mock type for the ThreadCreator type
 type ThreadCreator struct {
 	mock.Mock
 }
 
-// CreateThread provides a mock function with given fields: ctx, channelID, name.
-func (_m *ThreadCreator) CreateThread(
-	ctx context.Context, channelID string, name string,
-) (string, error) {
+// CloseThread provides a mock function with given fields: ctx, threadID, name
+func (_m *ThreadCreator) CloseThread(ctx context.Context, threadID string, name string) error {
+	ret := _m.Called(ctx, threadID, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CloseThread")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, threadID, name)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateThread provides a mock function with given fields: ctx, channelID, name
+func (_m *ThreadCreator) CreateThread(ctx context.Context, channelID string, name string) (string, error) {
 	ret := _m.Called(ctx, channelID, name)
 
 	if len(ret) == 0 {
@@ -25,11 +42,9 @@ func (_m *ThreadCreator) CreateThread(
 
 	var r0 string
 	var r1 error
-
 	if rf, ok := ret.Get(0).(func(context.Context, string, string) (string, error)); ok {
 		return rf(ctx, channelID, name)
 	}
-
 	if rf, ok := ret.Get(0).(func(context.Context, string, string) string); ok {
 		r0 = rf(ctx, channelID, name)
 	} else {
@@ -45,10 +60,36 @@ func (_m *ThreadCreator) CreateThread(
 	return r0, r1
 }
 
-// SendThreadMessage provides a mock function with given fields: ctx, threadID, message.
-func (_m *ThreadCreator) SendThreadMessage(
-	ctx context.Context, threadID string, message string,
-) error {
+// PostSummary provides a mock function with given fields: ctx, threadID, rollup
+func (_m *ThreadCreator) PostSummary(ctx context.Context, threadID string, rollup domain.OrderRollup) (string, error) {
+	ret := _m.Called(ctx, threadID, rollup)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PostSummary")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.OrderRollup) (string, error)); ok {
+		return rf(ctx, threadID, rollup)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.OrderRollup) string); ok {
+		r0 = rf(ctx, threadID, rollup)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, domain.OrderRollup) error); ok {
+		r1 = rf(ctx, threadID, rollup)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SendThreadMessage provides a mock function with given fields: ctx, threadID, message
+func (_m *ThreadCreator) SendThreadMessage(ctx context.Context, threadID string, message string) error {
 	ret := _m.Called(ctx, threadID, message)
 
 	if len(ret) == 0 {
@@ -56,7 +97,6 @@ func (_m *ThreadCreator) SendThreadMessage(
 	}
 
 	var r0 error
-
 	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
 		r0 = rf(ctx, threadID, message)
 	} else {
@@ -66,7 +106,26 @@ func (_m *ThreadCreator) SendThreadMessage(
 	return r0
 }
 
-// NewThreadCreator creates a new instance of ThreadCreator.
+// UpdateSummary provides a mock function with given fields: ctx, threadID, messageID, rollup
+func (_m *ThreadCreator) UpdateSummary(ctx context.Context, threadID string, messageID string, rollup domain.OrderRollup) error {
+	ret := _m.Called(ctx, threadID, messageID, rollup)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateSummary")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, domain.OrderRollup) error); ok {
+		r0 = rf(ctx, threadID, messageID, rollup)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewThreadCreator creates a new instance of ThreadCreator. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
 func NewThreadCreator(t interface {
 	mock.TestingT
 	Cleanup(func())
@@ -14,17 +14,63 @@ type TransactionRepository struct {
 	mock.Mock
 }
 
+// ArchiveTransaction provides a mock function with given fields: ctx, pageID
+func (_m *TransactionRepository) ArchiveTransaction(ctx context.Context, pageID string) error {
+	ret := _m.Called(ctx, pageID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ArchiveTransaction")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, pageID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // CreateTransaction provides a mock function with given fields: ctx, tx
-func (_m *TransactionRepository) CreateTransaction(ctx context.Context, tx domain.Transaction) error {
+func (_m *TransactionRepository) CreateTransaction(ctx context.Context, tx domain.Transaction) (string, error) {
 	ret := _m.Called(ctx, tx)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CreateTransaction")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, domain.Transaction) error); ok {
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Transaction) (string, error)); ok {
+		return rf(ctx, tx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Transaction) string); ok {
 		r0 = rf(ctx, tx)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.Transaction) error); ok {
+		r1 = rf(ctx, tx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateTransaction provides a mock function with given fields: ctx, pageID, tx
+func (_m *TransactionRepository) UpdateTransaction(ctx context.Context, pageID string, tx domain.Transaction) error {
+	ret := _m.Called(ctx, pageID, tx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateTransaction")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.Transaction) error); ok {
+		r0 = rf(ctx, pageID, tx)
 	} else {
 		r0 = ret.Error(0)
 	}
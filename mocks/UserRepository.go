@@ -14,27 +14,29 @@ type UserRepository struct {
 	mock.Mock
 }
 
-// GetOthersUnpaidAmount provides a mock function with given fields: ctx, buyerName, currency
-func (_m *UserRepository) GetOthersUnpaidAmount(ctx context.Context, buyerName string, currency domain.Currency) (float64, error) {
-	ret := _m.Called(ctx, buyerName, currency)
+// GetOthersUnpaidItems provides a mock function with given fields: ctx, buyerName
+func (_m *UserRepository) GetOthersUnpaidItems(ctx context.Context, buyerName string) ([]domain.UnpaidItem, error) {
+	ret := _m.Called(ctx, buyerName)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetOthersUnpaidAmount")
+		panic("no return value specified for GetOthersUnpaidItems")
 	}
 
-	var r0 float64
+	var r0 []domain.UnpaidItem
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, domain.Currency) (float64, error)); ok {
-		return rf(ctx, buyerName, currency)
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]domain.UnpaidItem, error)); ok {
+		return rf(ctx, buyerName)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, domain.Currency) float64); ok {
-		r0 = rf(ctx, buyerName, currency)
+	if rf, ok := ret.Get(0).(func(context.Context, string) []domain.UnpaidItem); ok {
+		r0 = rf(ctx, buyerName)
 	} else {
-		r0 = ret.Get(0).(float64)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.UnpaidItem)
+		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, domain.Currency) error); ok {
-		r1 = rf(ctx, buyerName, currency)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, buyerName)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -42,27 +44,29 @@ func (_m *UserRepository) GetOthersUnpaidAmount(ctx context.Context, buyerName s
 	return r0, r1
 }
 
-// GetUnpaidAmount provides a mock function with given fields: ctx, userDatabaseID, currency
-func (_m *UserRepository) GetUnpaidAmount(ctx context.Context, userDatabaseID string, currency domain.Currency) (float64, error) {
-	ret := _m.Called(ctx, userDatabaseID, currency)
+// GetUnpaidItems provides a mock function with given fields: ctx, userDatabaseID
+func (_m *UserRepository) GetUnpaidItems(ctx context.Context, userDatabaseID string) ([]domain.UnpaidItem, error) {
+	ret := _m.Called(ctx, userDatabaseID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetUnpaidAmount")
+		panic("no return value specified for GetUnpaidItems")
 	}
 
-	var r0 float64
+	var r0 []domain.UnpaidItem
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, domain.Currency) (float64, error)); ok {
-		return rf(ctx, userDatabaseID, currency)
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]domain.UnpaidItem, error)); ok {
+		return rf(ctx, userDatabaseID)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, domain.Currency) float64); ok {
-		r0 = rf(ctx, userDatabaseID, currency)
+	if rf, ok := ret.Get(0).(func(context.Context, string) []domain.UnpaidItem); ok {
+		r0 = rf(ctx, userDatabaseID)
 	} else {
-		r0 = ret.Get(0).(float64)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.UnpaidItem)
+		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, domain.Currency) error); ok {
-		r1 = rf(ctx, userDatabaseID, currency)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userDatabaseID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -0,0 +1,15 @@
+package port
+
+import (
+	"context"
+
+	"github.com/xgnid-tw/gx5/domain"
+)
+
+// BalanceChecker abstracts the check-balance use case for the gateway layer.
+type BalanceChecker interface {
+	// Balance returns the unpaid items of the member with the given Discord ID,
+	// their total and the debt reminder threshold. It returns ErrUserNotFound
+	// (wrapped) when the Discord user is not in TBL-001.
+	Balance(ctx context.Context, discordID string) (*domain.Balance, error)
+}
@@ -8,7 +8,17 @@ import (
 
 // BuyRecordRegisterer abstracts the register-buy-record use case for the gateway layer.
 type BuyRecordRegisterer interface {
-	Execute(
-		ctx context.Context, targetDiscordID string, jpyAmount float64, itemName string,
-	) (*domain.BuyResult, error)
+	Execute(ctx context.Context, req domain.BuyRequest) (*domain.BuyResult, error)
+	// OrderShopURL returns the shop URL of the order opened in the thread, or ""
+	// when the thread has no order or the order has no URL.
+	OrderShopURL(ctx context.Context, threadID string) (string, error)
+}
+
+// BuyRecordReviser abstracts the revise-buy-record use case for the gateway layer.
+type BuyRecordReviser interface {
+	// Get returns the record so it can be shown for editing.
+	Get(ctx context.Context, rev domain.BuyRevision) (*domain.ItemRecord, error)
+	// Cancel archives the record and returns it as it was before.
+	Cancel(ctx context.Context, rev domain.BuyRevision) (*domain.ItemRecord, error)
+	Edit(ctx context.Context, rev domain.BuyRevision, req domain.BuyRequest) (*domain.BuyResult, error)
 }
@@ -0,0 +1,15 @@
+package port
+
+import (
+	"context"
+
+	"github.com/xgnid-tw/gx5/domain"
+)
+
+// DebtLister abstracts the list-debts use case for the gateway layer.
+type DebtLister interface {
+	// ListDebts returns the balance of every member who has unpaid items. A
+	// member whose ledger cannot be read is reported in Failed rather than
+	// failing the whole list.
+	ListDebts(ctx context.Context) (*domain.Debts, error)
+}
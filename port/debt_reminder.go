@@ -1,7 +1,14 @@
 package port
 
-import "context"
+import (
+	"context"
+
+	"github.com/xgnid-tw/gx5/domain"
+)
 
 type DebtReminder interface {
-	Execute(ctx context.Context, debug bool) error
+	// Execute runs the debt reminder and reports the outcome for every member.
+	// Members that failed are in the report and joined into the error; the
+	// report is nil only when the run could not be carried out at all.
+	Execute(ctx context.Context, run domain.ReminderRun) (*domain.ReminderReport, error)
 }
@@ -0,0 +1,29 @@
+package port
+
+import "errors"
+
+// ErrRateLimited is returned (wrapped) by gateways when an external API keeps
+// rejecting requests for exceeding its rate limit after all retries.
+var ErrRateLimited = errors.New("rate limited")
+
+// ErrUserNotFound is returned when no TBL-001 member has the given Discord ID.
+var ErrUserNotFound = errors.New("user not found")
+
+// ErrItemNotAdvanceable is returned when an item is already at or past the
+// requested 物品狀況, or is a charge such as 運費 that has no fulfillment steps.
+var ErrItemNotAdvanceable = errors.New("item cannot be advanced")
+
+// ErrOrderNotFound is returned when no TBL-004 order belongs to a Discord thread.
+var ErrOrderNotFound = errors.New("order not found")
+
+// ErrInvalidOrderTransition is returned when an order is asked to move to a
+// status that does not directly follow its current one.
+var ErrInvalidOrderTransition = errors.New("invalid order status transition")
+
+// ErrBuyRevisionDenied is returned when someone other than the registrant or an
+// admin tries to undo or edit a /buy record.
+var ErrBuyRevisionDenied = errors.New("buy record revision denied")
+
+// ErrBuyRevisionExpired is returned when a /buy record is undone or edited after
+// the configured revision window has passed.
+var ErrBuyRevisionExpired = errors.New("buy record revision window expired")
@@ -0,0 +1,14 @@
+package port
+
+import (
+	"context"
+
+	"github.com/xgnid-tw/gx5/domain"
+)
+
+type ItemRepository interface {
+	GetItem(ctx context.Context, pageID string) (*domain.ItemRecord, error)
+	UpdateItemStatus(ctx context.Context, pageID string, status domain.ItemStatus) error
+	// GetOrderItems returns the rows of one TBL-002 or TBL-003 database linked to the order.
+	GetOrderItems(ctx context.Context, databaseID string, orderID string) ([]domain.ItemRecord, error)
+}
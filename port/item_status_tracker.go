@@ -0,0 +1,15 @@
+package port
+
+import (
+	"context"
+
+	"github.com/xgnid-tw/gx5/domain"
+)
+
+// ItemStatusTracker abstracts the track-item-status use case for the gateway layer.
+type ItemStatusTracker interface {
+	// Advance moves one item to the given status, or to the next one when to is empty.
+	Advance(ctx context.Context, pageID string, to domain.ItemStatus) (*domain.ItemStatusChange, error)
+	// AdvanceOrder moves every item linked to the thread's order that has not reached to yet.
+	AdvanceOrder(ctx context.Context, threadID string, to domain.ItemStatus) (*domain.ItemStatusReport, error)
+}
@@ -0,0 +1,16 @@
+package port
+
+import "context"
+
+// CacheStats reports how often cached lookups were served without hitting the backing store.
+type CacheStats struct {
+	Hits   uint64
+	Misses uint64
+}
+
+// MemberDirectory exposes maintenance operations on the cached member list.
+type MemberDirectory interface {
+	// Refresh drops the cached members and reloads them, returning the member count.
+	Refresh(ctx context.Context) (int, error)
+	Stats() CacheStats
+}
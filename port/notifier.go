@@ -7,5 +7,9 @@ import (
 )
 
 type Notifier interface {
-	Notify(ctx context.Context, user domain.User, debug bool) error
+	// Notify DMs the member a debt reminder listing the unpaid items of their balance.
+	Notify(ctx context.Context, balance domain.Balance, debug bool) error
+	NotifyItemsArrived(ctx context.Context, user domain.User, items []domain.ItemRecord) error
+	// ReportReminder posts the summary of a debt reminder run to the log channel.
+	ReportReminder(ctx context.Context, report domain.ReminderReport) error
 }
@@ -0,0 +1,16 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"github.com/xgnid-tw/gx5/domain"
+)
+
+// OrderDeadlineKeeper abstracts the order-deadline use case for the scheduler.
+type OrderDeadlineKeeper interface {
+	// Plan returns the reminders and closings still due for every open order.
+	Plan(ctx context.Context, now time.Time) ([]domain.DeadlineEvent, error)
+	// Run performs a planned event if it still applies to the order.
+	Run(ctx context.Context, event domain.DeadlineEvent) error
+}
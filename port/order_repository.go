@@ -8,4 +8,9 @@ import (
 
 type OrderRepository interface {
 	CreateOrder(ctx context.Context, order domain.Order) error
+	// GetOrderByThreadID returns the order opened in the thread, or ErrOrderNotFound.
+	GetOrderByThreadID(ctx context.Context, threadID string) (*domain.Order, error)
+	// GetOpenOrders returns the orders that have not been closed yet.
+	GetOpenOrders(ctx context.Context) ([]domain.Order, error)
+	UpdateOrderStatus(ctx context.Context, pageID string, status domain.OrderStatus) error
 }
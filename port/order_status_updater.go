@@ -0,0 +1,13 @@
+package port
+
+import (
+	"context"
+
+	"github.com/xgnid-tw/gx5/domain"
+)
+
+// OrderStatusUpdater abstracts the update-order-status use case for the gateway layer.
+type OrderStatusUpdater interface {
+	// Execute moves the order opened in the thread to the given status.
+	Execute(ctx context.Context, threadID string, to domain.OrderStatus) (*domain.OrderStatusChange, error)
+}
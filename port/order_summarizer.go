@@ -0,0 +1,15 @@
+package port
+
+import (
+	"context"
+
+	"github.com/xgnid-tw/gx5/domain"
+)
+
+// OrderSummarizer abstracts the summarize-order use case for the gateway layer.
+type OrderSummarizer interface {
+	// Execute totals the records linked to the order opened in the thread.
+	Execute(ctx context.Context, threadID string) (*domain.OrderRollup, error)
+	// Refresh updates the pinned summary in the thread with the current totals.
+	Refresh(ctx context.Context, threadID string) error
+}
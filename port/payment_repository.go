@@ -0,0 +1,10 @@
+package port
+
+import "context"
+
+// PaymentRepository updates the payment status of transaction records.
+type PaymentRepository interface {
+	// MarkPaid sets 付款狀況 to 已付款 on the given TBL-002/TBL-003 pages and returns
+	// the pages it updated, which on error may be only some of them.
+	MarkPaid(ctx context.Context, pageIDs []string) ([]string, error)
+}
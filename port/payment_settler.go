@@ -0,0 +1,16 @@
+package port
+
+import (
+	"context"
+
+	"github.com/xgnid-tw/gx5/domain"
+)
+
+// PaymentSettler abstracts the settle-payment use case for the gateway layer.
+type PaymentSettler interface {
+	// ListUnpaid returns the member's unpaid items with their total in the member's currency.
+	ListUnpaid(ctx context.Context, discordID string) (*domain.UnpaidLedger, error)
+	// Settle marks the given items of the member as paid. The report is also
+	// returned with an error when only some of the items could be settled.
+	Settle(ctx context.Context, discordID string, pageIDs []string) (*domain.SettleReport, error)
+}
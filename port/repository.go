@@ -9,6 +9,6 @@ import (
 type UserRepository interface {
 	GetUsers(ctx context.Context) ([]*domain.User, error)
 	GetUserByDiscordID(ctx context.Context, discordID string) (*domain.User, error)
-	GetUnpaidAmount(ctx context.Context, userDatabaseID string, currency domain.Currency) (float64, error)
-	GetOthersUnpaidAmount(ctx context.Context, buyerName string, currency domain.Currency) (float64, error)
+	GetUnpaidItems(ctx context.Context, userDatabaseID string) ([]domain.UnpaidItem, error)
+	GetOthersUnpaidItems(ctx context.Context, buyerName string) ([]domain.UnpaidItem, error)
 }
@@ -1,8 +1,18 @@
 package port
 
-import "context"
+import (
+	"context"
+
+	"github.com/xgnid-tw/gx5/domain"
+)
 
 type ThreadCreator interface {
 	CreateThread(ctx context.Context, channelID string, name string) (string, error)
 	SendThreadMessage(ctx context.Context, threadID string, message string) error
+	// CloseThread renames the thread and locks it so members can no longer post.
+	CloseThread(ctx context.Context, threadID string, name string) error
+	// PostSummary posts and pins the order summary, returning the message ID.
+	PostSummary(ctx context.Context, threadID string, rollup domain.OrderRollup) (string, error)
+	// UpdateSummary replaces the content of a summary posted by PostSummary.
+	UpdateSummary(ctx context.Context, threadID string, messageID string, rollup domain.OrderRollup) error
 }
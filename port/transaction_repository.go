@@ -7,5 +7,10 @@ import (
 )
 
 type TransactionRepository interface {
-	CreateTransaction(ctx context.Context, tx domain.Transaction) error
+	// CreateTransaction inserts tx and returns the ID of the created page.
+	CreateTransaction(ctx context.Context, tx domain.Transaction) (string, error)
+	// UpdateTransaction overwrites the item name, amounts and the details set on tx.
+	UpdateTransaction(ctx context.Context, pageID string, tx domain.Transaction) error
+	// ArchiveTransaction moves the page to the Notion trash.
+	ArchiveTransaction(ctx context.Context, pageID string) error
 }
@@ -0,0 +1,30 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/xgnid-tw/gx5/domain"
+	"github.com/xgnid-tw/gx5/port"
+)
+
+type CheckBalance struct {
+	repo       port.UserRepository
+	othersDBID string
+	thresholds domain.ReminderThresholds
+}
+
+func NewCheckBalance(repo port.UserRepository, othersDBID string, thresholds domain.ReminderThresholds) *CheckBalance {
+	return &CheckBalance{repo: repo, othersDBID: othersDBID, thresholds: thresholds}
+}
+
+// Balance returns what the member owes, computed the same way the debt reminder
+// decides whether to DM them.
+func (uc *CheckBalance) Balance(ctx context.Context, discordID string) (*domain.Balance, error) {
+	user, err := uc.repo.GetUserByDiscordID(ctx, discordID)
+	if err != nil {
+		return nil, fmt.Errorf("get user by discord id: %w", err)
+	}
+
+	return loadBalance(ctx, uc.repo, uc.othersDBID, uc.thresholds, user)
+}
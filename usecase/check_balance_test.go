@@ -0,0 +1,77 @@
+package usecase_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/xgnid-tw/gx5/domain"
+	"github.com/xgnid-tw/gx5/mocks"
+	"github.com/xgnid-tw/gx5/port"
+	"github.com/xgnid-tw/gx5/usecase"
+)
+
+func TestCheckBalance_PersonalDB(t *testing.T) {
+	repo := mocks.NewUserRepository(t)
+
+	user := &domain.User{DiscordID: "111", Name: "Alice", NotionID: "abc", Currency: domain.CurrencyTWD}
+
+	repo.On("GetUserByDiscordID", mock.Anything, "111").Return(user, nil)
+	repo.On("GetUnpaidItems", mock.Anything, "abc").Return(twdItems(1500, 800), nil)
+
+	uc := usecase.NewCheckBalance(repo, testOthersDBID, testThresholds)
+	balance, err := uc.Balance(context.Background(), "111")
+
+	require.NoError(t, err)
+	require.Len(t, balance.Items, 2)
+	require.InDelta(t, 2300, balance.Total, 0.001)
+	require.InDelta(t, 2000, balance.Threshold, 0.001)
+	require.True(t, balance.OverThreshold())
+}
+
+func TestCheckBalance_OthersDB(t *testing.T) {
+	repo := mocks.NewUserRepository(t)
+
+	user := &domain.User{DiscordID: "222", Name: "Bob", NotionID: testOthersDBID, Currency: domain.CurrencyTWD}
+
+	repo.On("GetUserByDiscordID", mock.Anything, "222").Return(user, nil)
+	repo.On("GetOthersUnpaidItems", mock.Anything, "Bob").Return(twdItems(100), nil)
+
+	uc := usecase.NewCheckBalance(repo, testOthersDBID, testThresholds)
+	balance, err := uc.Balance(context.Background(), "222")
+
+	require.NoError(t, err)
+	require.Zero(t, balance.Threshold)
+	require.True(t, balance.OverThreshold(), "shared database members are reminded of any amount")
+}
+
+func TestCheckBalance_OthersDB_DifferentIDForm(t *testing.T) {
+	repo := mocks.NewUserRepository(t)
+
+	// TBL-001 may hold the dashed ID Notion shows while the config has the compact one.
+	user := &domain.User{DiscordID: "222", Name: "Bob", NotionID: "Others-DB", Currency: domain.CurrencyTWD}
+
+	repo.On("GetUserByDiscordID", mock.Anything, "222").Return(user, nil)
+	repo.On("GetOthersUnpaidItems", mock.Anything, "Bob").Return(twdItems(100), nil)
+
+	uc := usecase.NewCheckBalance(repo, "othersdb", testThresholds)
+	balance, err := uc.Balance(context.Background(), "222")
+
+	require.NoError(t, err)
+	require.True(t, balance.Shared)
+}
+
+func TestCheckBalance_UserNotFound(t *testing.T) {
+	repo := mocks.NewUserRepository(t)
+
+	repo.On("GetUserByDiscordID", mock.Anything, "999").
+		Return(nil, fmt.Errorf("%w for discord_id: 999", port.ErrUserNotFound))
+
+	uc := usecase.NewCheckBalance(repo, testOthersDBID, testThresholds)
+	_, err := uc.Balance(context.Background(), "999")
+
+	require.ErrorIs(t, err, port.ErrUserNotFound)
+}
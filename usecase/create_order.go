@@ -43,6 +43,17 @@ func (uc *CreateOrder) Execute(
 		return fmt.Errorf("create thread: %w", err)
 	}
 
+	order.ThreadID = threadID
+	order.ChannelID = channelID
+	order.Status = domain.OrderStatusOpen
+
+	summaryID, err := uc.threadCreator.PostSummary(ctx, threadID, domain.OrderRollup{Order: order})
+	if err != nil {
+		log.Printf("post order summary: %s", err)
+	}
+
+	order.SummaryID = summaryID
+
 	roleID, hasRole := uc.tagRoleMap[string(order.Tag)]
 	if order.Tag != "" && hasRole && uc.memberAdder != nil {
 		//nolint:contextcheck,gosec,nolintlint // intentionally detached from caller context
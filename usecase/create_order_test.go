@@ -13,6 +13,16 @@ import (
 	"github.com/xgnid-tw/gx5/usecase"
 )
 
+// persisted returns order as CreateOrder stores it once the thread is open.
+func persisted(order domain.Order) domain.Order {
+	order.ThreadID = "thread-id"
+	order.ChannelID = "ch-1"
+	order.Status = domain.OrderStatusOpen
+	order.SummaryID = "summary-msg"
+
+	return order
+}
+
 func TestCreateOrder_MissingOrderTitle(t *testing.T) {
 	repo := mocks.NewOrderRepository(t)
 	tc := mocks.NewThreadCreator(t)
@@ -48,7 +58,8 @@ func TestCreateOrder_NotionError(t *testing.T) {
 
 	tc.On("CreateThread", mock.Anything, "ch-1", "test order").
 		Return("thread-id", nil)
-	repo.On("CreateOrder", mock.Anything, domain.Order{ThreadName: "test order"}).
+	tc.On("PostSummary", mock.Anything, "thread-id", mock.Anything).Return("summary-msg", nil)
+	repo.On("CreateOrder", mock.Anything, persisted(domain.Order{ThreadName: "test order"})).
 		Return(errors.New("notion error"))
 
 	uc := usecase.NewCreateOrder(repo, tc, nil, nil)
@@ -71,6 +82,7 @@ func TestCreateOrder_Success_AllFields(t *testing.T) {
 		Deadline:   "2026-04-01",
 		ShopURL:    "https://shop.example.com",
 		Tag:        domain.Tag315Pro,
+		CreatedBy:  "999",
 	}
 
 	tagRoleMap := map[string]string{"315pro": "123456"}
@@ -78,9 +90,10 @@ func TestCreateOrder_Success_AllFields(t *testing.T) {
 
 	tc.On("CreateThread", mock.Anything, "ch-1", "test order").
 		Return("thread-id", nil)
+	tc.On("PostSummary", mock.Anything, "thread-id", mock.Anything).Return("summary-msg", nil)
 	ma.On("AddRoleMembersToThread", mock.Anything, "thread-id", "123456", expectedMessage).
 		Return(nil).Maybe()
-	repo.On("CreateOrder", mock.Anything, order).
+	repo.On("CreateOrder", mock.Anything, persisted(order)).
 		Return(nil)
 
 	uc := usecase.NewCreateOrder(repo, tc, ma, tagRoleMap)
@@ -100,7 +113,8 @@ func TestCreateOrder_Success_OnlyTitle(t *testing.T) {
 
 	tc.On("CreateThread", mock.Anything, "ch-1", "minimal order").
 		Return("thread-id", nil)
-	repo.On("CreateOrder", mock.Anything, order).
+	tc.On("PostSummary", mock.Anything, "thread-id", mock.Anything).Return("summary-msg", nil)
+	repo.On("CreateOrder", mock.Anything, persisted(order)).
 		Return(nil)
 
 	uc := usecase.NewCreateOrder(repo, tc, nil, nil)
@@ -123,8 +137,9 @@ func TestCreateOrder_Success_PartialFields(t *testing.T) {
 
 	tc.On("CreateThread", mock.Anything, "ch-1", "partial order").
 		Return("thread-id", nil)
+	tc.On("PostSummary", mock.Anything, "thread-id", mock.Anything).Return("summary-msg", nil)
 	tc.On("SendThreadMessage", mock.Anything, "thread-id", expectedMessage).Return(nil)
-	repo.On("CreateOrder", mock.Anything, order).
+	repo.On("CreateOrder", mock.Anything, persisted(order)).
 		Return(nil)
 
 	uc := usecase.NewCreateOrder(repo, tc, nil, nil)
@@ -147,8 +162,9 @@ func TestCreateOrder_Success_ShopURLOnly(t *testing.T) {
 
 	tc.On("CreateThread", mock.Anything, "ch-1", "url order").
 		Return("thread-id", nil)
+	tc.On("PostSummary", mock.Anything, "thread-id", mock.Anything).Return("summary-msg", nil)
 	tc.On("SendThreadMessage", mock.Anything, "thread-id", expectedMessage).Return(nil)
-	repo.On("CreateOrder", mock.Anything, order).
+	repo.On("CreateOrder", mock.Anything, persisted(order)).
 		Return(nil)
 
 	uc := usecase.NewCreateOrder(repo, tc, nil, nil)
@@ -173,9 +189,10 @@ func TestCreateOrder_Success_TagOnly(t *testing.T) {
 
 	tc.On("CreateThread", mock.Anything, "ch-1", "tag order").
 		Return("thread-id", nil)
+	tc.On("PostSummary", mock.Anything, "thread-id", mock.Anything).Return("summary-msg", nil)
 	ma.On("AddRoleMembersToThread", mock.Anything, "thread-id", "789012", expectedMessage).
 		Return(nil).Maybe()
-	repo.On("CreateOrder", mock.Anything, order).
+	repo.On("CreateOrder", mock.Anything, persisted(order)).
 		Return(nil)
 
 	uc := usecase.NewCreateOrder(repo, tc, ma, tagRoleMap)
@@ -199,8 +216,9 @@ func TestCreateOrder_Success_TagWithoutRoleID(t *testing.T) {
 
 	tc.On("CreateThread", mock.Anything, "ch-1", "fallback order").
 		Return("thread-id", nil)
+	tc.On("PostSummary", mock.Anything, "thread-id", mock.Anything).Return("summary-msg", nil)
 	tc.On("SendThreadMessage", mock.Anything, "thread-id", expectedMessage).Return(nil)
-	repo.On("CreateOrder", mock.Anything, order).
+	repo.On("CreateOrder", mock.Anything, persisted(order)).
 		Return(nil)
 
 	uc := usecase.NewCreateOrder(repo, tc, nil, tagRoleMap)
@@ -209,3 +227,26 @@ func TestCreateOrder_Success_TagWithoutRoleID(t *testing.T) {
 
 	require.NoError(t, err)
 }
+
+func TestCreateOrder_SummaryErrorIsNotFatal(t *testing.T) {
+	repo := mocks.NewOrderRepository(t)
+	tc := mocks.NewThreadCreator(t)
+
+	order := domain.Order{ThreadName: "minimal order"}
+
+	tc.On("CreateThread", mock.Anything, "ch-1", "minimal order").
+		Return("thread-id", nil)
+	tc.On("PostSummary", mock.Anything, "thread-id", mock.Anything).
+		Return("", errors.New("missing permissions"))
+
+	expected := persisted(order)
+	expected.SummaryID = ""
+
+	repo.On("CreateOrder", mock.Anything, expected).Return(nil)
+
+	uc := usecase.NewCreateOrder(repo, tc, nil, nil)
+
+	err := uc.Execute(context.Background(), "ch-1", order)
+
+	require.NoError(t, err)
+}
@@ -0,0 +1,42 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/xgnid-tw/gx5/domain"
+	"github.com/xgnid-tw/gx5/port"
+)
+
+// loadLedger reads the member's unpaid items from TBL-002, or from TBL-003 for
+// members whose notion_id is the shared others database, and totals them in the
+// member's currency.
+func loadLedger(
+	ctx context.Context, repo port.UserRepository, othersDBID string, u *domain.User,
+) (*domain.UnpaidLedger, error) {
+	var (
+		items []domain.UnpaidItem
+		err   error
+	)
+
+	shared := sameNotionID(u.NotionID, othersDBID)
+
+	if !shared {
+		items, err = repo.GetUnpaidItems(ctx, u.NotionID)
+		if err != nil {
+			return nil, fmt.Errorf("get unpaid items for %s: %w", u.Name, err)
+		}
+	} else {
+		items, err = repo.GetOthersUnpaidItems(ctx, u.Name)
+		if err != nil {
+			return nil, fmt.Errorf("get others unpaid items for %s: %w", u.Name, err)
+		}
+	}
+
+	ledger := &domain.UnpaidLedger{User: *u, Items: items, Shared: shared}
+	for _, it := range items {
+		ledger.Total += it.Amount(u.Currency)
+	}
+
+	return ledger, nil
+}
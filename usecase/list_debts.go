@@ -0,0 +1,70 @@
+package usecase
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/xgnid-tw/gx5/domain"
+	"github.com/xgnid-tw/gx5/port"
+)
+
+type ListDebts struct {
+	repo       port.UserRepository
+	othersDBID string
+	thresholds domain.ReminderThresholds
+}
+
+func NewListDebts(repo port.UserRepository, othersDBID string, thresholds domain.ReminderThresholds) *ListDebts {
+	return &ListDebts{repo: repo, othersDBID: othersDBID, thresholds: thresholds}
+}
+
+// ListDebts computes every member's balance the same way the debt reminder does
+// and returns those with unpaid items, grouped by currency (TWD first) and sorted
+// by total, largest first. Members without unpaid items are left out. Like the
+// debt reminder, ledgers are read by a few workers at a time, and a member whose
+// ledger fails to load is recorded in Failed, in TBL-001 order, and does not
+// keep the others from being listed.
+func (uc *ListDebts) ListDebts(ctx context.Context) (*domain.Debts, error) {
+	users, err := uc.repo.GetUsers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get users: %w", err)
+	}
+
+	balances := make([]*domain.Balance, len(users))
+	errs := make([]error, len(users))
+
+	parallel(len(users), func(n int) {
+		balances[n], errs[n] = loadBalance(ctx, uc.repo, uc.othersDBID, uc.thresholds, users[n])
+	})
+
+	debts := &domain.Debts{}
+
+	for n, u := range users {
+		switch {
+		case errs[n] != nil:
+			debts.Failed = append(debts.Failed, domain.DebtFailure{User: *u, Reason: errs[n].Error()})
+		case len(balances[n].Items) > 0:
+			debts.Balances = append(debts.Balances, balances[n])
+		}
+	}
+
+	slices.SortFunc(debts.Balances, func(a, b *domain.Balance) int {
+		return cmp.Or(
+			cmp.Compare(currencyRank(a.User.Currency), currencyRank(b.User.Currency)),
+			cmp.Compare(b.Total, a.Total),
+			cmp.Compare(a.User.Name, b.User.Name),
+		)
+	})
+
+	return debts, nil
+}
+
+func currencyRank(c domain.Currency) int {
+	if c == domain.CurrencyJPY {
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,82 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/xgnid-tw/gx5/domain"
+	"github.com/xgnid-tw/gx5/mocks"
+	"github.com/xgnid-tw/gx5/usecase"
+)
+
+func TestListDebts_SortedByCurrencyAndTotal(t *testing.T) {
+	repo := mocks.NewUserRepository(t)
+
+	alice := &domain.User{DiscordID: "1", Name: "Alice", NotionID: "a", Currency: domain.CurrencyTWD}
+	bob := &domain.User{DiscordID: "2", Name: "Bob", NotionID: "b", Currency: domain.CurrencyJPY}
+	carol := &domain.User{DiscordID: "3", Name: "Carol", NotionID: testOthersDBID, Currency: domain.CurrencyTWD}
+	dave := &domain.User{DiscordID: "4", Name: "Dave", NotionID: "d", Currency: domain.CurrencyTWD}
+
+	repo.On("GetUsers", mock.Anything).Return([]*domain.User{alice, bob, carol, dave}, nil)
+	repo.On("GetUnpaidItems", mock.Anything, "a").Return(twdItems(500), nil)
+	repo.On("GetUnpaidItems", mock.Anything, "b").
+		Return([]domain.UnpaidItem{{JPYAmount: 9000, TWDAmount: 1980}}, nil)
+	repo.On("GetOthersUnpaidItems", mock.Anything, "Carol").Return(twdItems(1200, 300), nil)
+	repo.On("GetUnpaidItems", mock.Anything, "d").Return(nil, nil)
+
+	uc := usecase.NewListDebts(repo, testOthersDBID, testThresholds)
+	list, err := uc.ListDebts(context.Background())
+
+	require.NoError(t, err)
+	require.Empty(t, list.Failed)
+
+	debts := list.Balances
+	require.Len(t, debts, 3, "members without unpaid items are left out")
+
+	require.Equal(t, "Carol", debts[0].User.Name)
+	require.InDelta(t, 1500, debts[0].Total, 0.001)
+	require.True(t, debts[0].OverThreshold())
+
+	require.Equal(t, "Alice", debts[1].User.Name)
+	require.False(t, debts[1].OverThreshold())
+
+	require.Equal(t, "Bob", debts[2].User.Name)
+	require.InDelta(t, 9000, debts[2].Total, 0.001)
+	require.InDelta(t, 8000, debts[2].Threshold, 0.001)
+}
+
+func TestListDebts_QueryError_OtherMembersListed(t *testing.T) {
+	repo := mocks.NewUserRepository(t)
+
+	alice := &domain.User{DiscordID: "1", Name: "Alice", NotionID: "a", Currency: domain.CurrencyTWD}
+	bob := &domain.User{DiscordID: "2", Name: "Bob", NotionID: "b", Currency: domain.CurrencyTWD}
+
+	repo.On("GetUsers", mock.Anything).Return([]*domain.User{alice, bob}, nil)
+	repo.On("GetUnpaidItems", mock.Anything, "a").Return(nil, errors.New("notion error"))
+	repo.On("GetUnpaidItems", mock.Anything, "b").Return(twdItems(500), nil)
+
+	uc := usecase.NewListDebts(repo, testOthersDBID, testThresholds)
+	debts, err := uc.ListDebts(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, debts.Balances, 1)
+	require.Equal(t, "Bob", debts.Balances[0].User.Name)
+	require.Len(t, debts.Failed, 1)
+	require.Equal(t, "Alice", debts.Failed[0].User.Name)
+	require.Contains(t, debts.Failed[0].Reason, "get unpaid items for Alice")
+}
+
+func TestListDebts_GetUsersError(t *testing.T) {
+	repo := mocks.NewUserRepository(t)
+
+	repo.On("GetUsers", mock.Anything).Return(nil, errors.New("notion error"))
+
+	uc := usecase.NewListDebts(repo, testOthersDBID, testThresholds)
+	_, err := uc.ListDebts(context.Background())
+
+	require.ErrorContains(t, err, "get users")
+}
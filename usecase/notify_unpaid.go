@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 
@@ -9,71 +10,113 @@ import (
 	"github.com/xgnid-tw/gx5/port"
 )
 
-const (
-	twdNotificationThreshold = 2000
-	jpyNotificationThreshold = 8000
-)
-
-var notificationAmountLimit = map[domain.Currency]float64{
-	domain.CurrencyTWD: twdNotificationThreshold,
-	domain.CurrencyJPY: jpyNotificationThreshold,
-}
-
 type NotifyUnpaid struct {
 	repo       port.UserRepository
 	notifier   port.Notifier
 	othersDBID string
+	thresholds domain.ReminderThresholds
 }
 
 func NewNotifyUnpaid(
 	repo port.UserRepository, notifier port.Notifier,
-	othersDBID string,
+	othersDBID string, thresholds domain.ReminderThresholds,
 ) *NotifyUnpaid {
 	return &NotifyUnpaid{
 		repo: repo, notifier: notifier,
-		othersDBID: othersDBID,
+		othersDBID: othersDBID, thresholds: thresholds,
 	}
 }
 
-func (uc *NotifyUnpaid) Execute(ctx context.Context, debug bool) error {
+// Execute reminds every member whose unpaid total exceeds their threshold, or
+// reaches run.MinAmount when set, and returns what it did for each member, in TBL-001
+// order. The report is also posted to the log channel. Members are handled by a
+// few workers at a time; a member whose ledger cannot be read or whose DM fails
+// is recorded as failed and does not stop the run. The returned error joins
+// those failures, and the report is nil only when the member list itself
+// cannot be read.
+func (uc *NotifyUnpaid) Execute(ctx context.Context, run domain.ReminderRun) (*domain.ReminderReport, error) {
 	users, err := uc.repo.GetUsers(ctx)
 	if err != nil {
-		return fmt.Errorf("get users: %w", err)
+		return nil, fmt.Errorf("get users: %w", err)
 	}
 
-	for _, u := range users {
-		shouldNotify, err := uc.shouldNotifyUser(ctx, u)
-		if err != nil {
-			return err
-		}
+	report := &domain.ReminderReport{ReminderRun: run, Results: make([]domain.ReminderResult, len(users))}
+	errs := make([]error, len(users))
 
-		if shouldNotify {
-			err = uc.notifier.Notify(ctx, *u, debug)
-			if err != nil {
-				log.Printf("notify %s: %s", u.Name, err)
-			}
-		}
+	parallel(len(users), func(n int) {
+		report.Results[n], errs[n] = uc.remind(ctx, users[n], run)
+	})
+
+	err = uc.notifier.ReportReminder(ctx, *report)
+	if err != nil {
+		log.Printf("post debt reminder report: %s", err)
 	}
 
-	return nil
+	return report, errors.Join(errs...)
 }
 
-func (uc *NotifyUnpaid) shouldNotifyUser(
-	ctx context.Context, u *domain.User,
-) (bool, error) {
-	if u.NotionID != uc.othersDBID {
-		a, err := uc.repo.GetUnpaidAmount(ctx, u.NotionID, u.Currency)
+func (uc *NotifyUnpaid) remind(
+	ctx context.Context, u *domain.User, run domain.ReminderRun,
+) (domain.ReminderResult, error) {
+	balance, err := loadBalance(ctx, uc.repo, uc.othersDBID, uc.thresholds, u)
+	if err != nil {
+		return domain.ReminderResult{User: *u, Outcome: domain.ReminderFailed, Reason: err.Error()}, err
+	}
+
+	over, below := balance.OverThreshold(), "未超過門檻"
+
+	// The one-off minimum is inclusive, in the member's own currency: owing
+	// exactly min_amount is reminded.
+	if run.MinAmount != nil {
+		balance.Threshold = *run.MinAmount
+		over, below = balance.Total >= balance.Threshold, "未達本次門檻"
+	}
+
+	res := domain.ReminderResult{User: *u, Amount: balance.Total, Threshold: balance.Threshold}
+
+	switch {
+	case len(balance.Items) == 0:
+		res.Outcome, res.Reason = domain.ReminderSkipped, "沒有未付款項目"
+	case !over:
+		res.Outcome, res.Reason = domain.ReminderSkipped, below
+	default:
+		err = uc.notifier.Notify(ctx, *balance, run.Debug)
 		if err != nil {
-			return false, fmt.Errorf("get unpaid amount for %s: %w", u.Name, err)
+			res.Outcome, res.Reason = domain.ReminderFailed, err.Error()
+
+			return res, fmt.Errorf("notify %s: %w", u.Name, err)
 		}
 
-		return a > notificationAmountLimit[u.Currency], nil
+		res.Outcome = domain.ReminderNotified
 	}
 
-	a, err := uc.repo.GetOthersUnpaidAmount(ctx, u.Name, u.Currency)
+	return res, nil
+}
+
+// loadBalance loads the member's unpaid ledger with the threshold the debt
+// reminder applies to it.
+func loadBalance(
+	ctx context.Context, repo port.UserRepository, othersDBID string,
+	thresholds domain.ReminderThresholds, u *domain.User,
+) (*domain.Balance, error) {
+	ledger, err := loadLedger(ctx, repo, othersDBID, u)
 	if err != nil {
-		return false, fmt.Errorf("get others unpaid amount for %s: %w", u.Name, err)
+		return nil, err
 	}
 
-	return a > 0, nil
+	return &domain.Balance{UnpaidLedger: *ledger, Threshold: reminderThreshold(thresholds, othersDBID, u)}, nil
+}
+
+// reminderThreshold resolves the member's threshold: their own TBL-001 value
+// (BR-049), else the one for the shared others database (BR-006), else the one
+// for their currency (BR-001).
+func reminderThreshold(thresholds domain.ReminderThresholds, othersDBID string, u *domain.User) float64 {
+	switch {
+	case u.ReminderThreshold != nil:
+		return *u.ReminderThreshold
+	case sameNotionID(u.NotionID, othersDBID):
+		return thresholds.Others
+	default:
+		return thresholds.ByCurrency[u.Currency]
+	}
 }
@@ -15,21 +15,41 @@ import (
 
 const testOthersDBID = "others-db"
 
+// testThresholds are the default thresholds from config.Load.
+var testThresholds = domain.ReminderThresholds{
+	ByCurrency: map[domain.Currency]float64{domain.CurrencyTWD: 2000, domain.CurrencyJPY: 8000},
+}
+
+// twdItems builds one unpaid item per TWD amount.
+func twdItems(amounts ...float64) []domain.UnpaidItem {
+	items := make([]domain.UnpaidItem, 0, len(amounts))
+	for _, a := range amounts {
+		items = append(items, domain.UnpaidItem{TWDAmount: a})
+	}
+
+	return items
+}
+
+// balanceOf matches the balance the reminder is sent with by its member.
+func balanceOf(u *domain.User) any {
+	return mock.MatchedBy(func(b domain.Balance) bool { return b.User == *u })
+}
+
 func TestExecute_GetUsersError(t *testing.T) {
 	repo := mocks.NewUserRepository(t)
 	notifier := mocks.NewNotifier(t)
 
 	repo.On("GetUsers", mock.Anything).Return(nil, errors.New("db error"))
 
-	uc := usecase.NewNotifyUnpaid(repo, notifier, testOthersDBID)
+	uc := usecase.NewNotifyUnpaid(repo, notifier, testOthersDBID, testThresholds)
 
-	err := uc.Execute(context.Background(), false)
+	_, err := uc.Execute(context.Background(), domain.ReminderRun{})
 
 	require.Error(t, err)
 	require.ErrorContains(t, err, "get users")
 }
 
-func TestExecute_GetUnpaidAmountError(t *testing.T) {
+func TestExecute_GetUnpaidItemsError(t *testing.T) {
 	repo := mocks.NewUserRepository(t)
 	notifier := mocks.NewNotifier(t)
 
@@ -39,18 +59,20 @@ func TestExecute_GetUnpaidAmountError(t *testing.T) {
 	}
 
 	repo.On("GetUsers", mock.Anything).Return([]*domain.User{user}, nil)
-	repo.On("GetUnpaidAmount", mock.Anything, "abc", domain.CurrencyTWD).
-		Return(float64(0), errors.New("notion error"))
+	repo.On("GetUnpaidItems", mock.Anything, "abc").
+		Return(nil, errors.New("notion error"))
+	notifier.On("ReportReminder", mock.Anything, mock.Anything).Return(nil)
 
-	uc := usecase.NewNotifyUnpaid(repo, notifier, testOthersDBID)
+	uc := usecase.NewNotifyUnpaid(repo, notifier, testOthersDBID, testThresholds)
 
-	err := uc.Execute(context.Background(), false)
+	report, err := uc.Execute(context.Background(), domain.ReminderRun{})
 
 	require.Error(t, err)
-	require.ErrorContains(t, err, "get unpaid amount")
+	require.Equal(t, domain.ReminderFailed, report.Results[0].Outcome)
+	require.ErrorContains(t, err, "get unpaid items")
 }
 
-func TestExecute_GetOthersUnpaidAmountError(t *testing.T) {
+func TestExecute_GetOthersUnpaidItemsError(t *testing.T) {
 	repo := mocks.NewUserRepository(t)
 	notifier := mocks.NewNotifier(t)
 
@@ -60,15 +82,17 @@ func TestExecute_GetOthersUnpaidAmountError(t *testing.T) {
 	}
 
 	repo.On("GetUsers", mock.Anything).Return([]*domain.User{user}, nil)
-	repo.On("GetOthersUnpaidAmount", mock.Anything, "Alice", domain.CurrencyTWD).
-		Return(float64(0), errors.New("notion error"))
+	repo.On("GetOthersUnpaidItems", mock.Anything, "Alice").
+		Return(nil, errors.New("notion error"))
+	notifier.On("ReportReminder", mock.Anything, mock.Anything).Return(nil)
 
-	uc := usecase.NewNotifyUnpaid(repo, notifier, testOthersDBID)
+	uc := usecase.NewNotifyUnpaid(repo, notifier, testOthersDBID, testThresholds)
 
-	err := uc.Execute(context.Background(), false)
+	report, err := uc.Execute(context.Background(), domain.ReminderRun{})
 
 	require.Error(t, err)
-	require.ErrorContains(t, err, "get others unpaid amount")
+	require.Equal(t, domain.ReminderFailed, report.Results[0].Outcome)
+	require.ErrorContains(t, err, "get others unpaid items")
 }
 
 func TestExecute_PersonalDB_AboveThreshold_Notified(t *testing.T) {
@@ -81,15 +105,47 @@ func TestExecute_PersonalDB_AboveThreshold_Notified(t *testing.T) {
 	}
 
 	repo.On("GetUsers", mock.Anything).Return([]*domain.User{user}, nil)
-	repo.On("GetUnpaidAmount", mock.Anything, "abc", domain.CurrencyTWD).
-		Return(float64(3000), nil)
-	notifier.On("Notify", mock.Anything, *user, false).Return(nil)
+	repo.On("GetUnpaidItems", mock.Anything, "abc").
+		Return(twdItems(3000), nil)
+	notifier.On("Notify", mock.Anything, domain.Balance{
+		UnpaidLedger: domain.UnpaidLedger{User: *user, Items: twdItems(3000), Total: 3000},
+		Threshold:    2000,
+	}, false).Return(nil)
+
+	notifier.On("ReportReminder", mock.Anything, mock.Anything).Return(nil)
+
+	uc := usecase.NewNotifyUnpaid(repo, notifier, testOthersDBID, testThresholds)
+
+	report, err := uc.Execute(context.Background(), domain.ReminderRun{})
+
+	require.NoError(t, err)
+	require.Equal(t, domain.ReminderNotified, report.Results[0].Outcome)
+	require.InDelta(t, 3000, report.Results[0].Amount, 0.001)
+	require.InDelta(t, 2000, report.Results[0].Threshold, 0.001)
+}
+
+func TestExecute_PersonalDB_ItemsSumAboveThreshold_Notified(t *testing.T) {
+	repo := mocks.NewUserRepository(t)
+	notifier := mocks.NewNotifier(t)
+
+	user := &domain.User{
+		DiscordID: "111", Name: "Alice",
+		NotionID: "abc", Currency: domain.CurrencyTWD,
+	}
+
+	repo.On("GetUsers", mock.Anything).Return([]*domain.User{user}, nil)
+	repo.On("GetUnpaidItems", mock.Anything, "abc").
+		Return(twdItems(1500, 600), nil)
+	notifier.On("Notify", mock.Anything, balanceOf(user), false).Return(nil)
+
+	notifier.On("ReportReminder", mock.Anything, mock.Anything).Return(nil)
 
-	uc := usecase.NewNotifyUnpaid(repo, notifier, testOthersDBID)
+	uc := usecase.NewNotifyUnpaid(repo, notifier, testOthersDBID, testThresholds)
 
-	err := uc.Execute(context.Background(), false)
+	report, err := uc.Execute(context.Background(), domain.ReminderRun{})
 
 	require.NoError(t, err)
+	require.Equal(t, domain.ReminderNotified, report.Results[0].Outcome)
 }
 
 func TestExecute_OthersDB_AboveThreshold_Notified(t *testing.T) {
@@ -102,15 +158,20 @@ func TestExecute_OthersDB_AboveThreshold_Notified(t *testing.T) {
 	}
 
 	repo.On("GetUsers", mock.Anything).Return([]*domain.User{user}, nil)
-	repo.On("GetOthersUnpaidAmount", mock.Anything, "Carol", domain.CurrencyTWD).
-		Return(float64(2500), nil)
-	notifier.On("Notify", mock.Anything, *user, false).Return(nil)
+	repo.On("GetOthersUnpaidItems", mock.Anything, "Carol").
+		Return(twdItems(2500), nil)
+	notifier.On("Notify", mock.Anything, mock.MatchedBy(func(b domain.Balance) bool {
+		return b.User == *user && b.Shared
+	}), false).Return(nil)
 
-	uc := usecase.NewNotifyUnpaid(repo, notifier, testOthersDBID)
+	notifier.On("ReportReminder", mock.Anything, mock.Anything).Return(nil)
 
-	err := uc.Execute(context.Background(), false)
+	uc := usecase.NewNotifyUnpaid(repo, notifier, testOthersDBID, testThresholds)
+
+	report, err := uc.Execute(context.Background(), domain.ReminderRun{})
 
 	require.NoError(t, err)
+	require.Equal(t, domain.ReminderNotified, report.Results[0].Outcome)
 }
 
 func TestExecute_PersonalDB_ZeroAmount_NotNotified(t *testing.T) {
@@ -123,14 +184,18 @@ func TestExecute_PersonalDB_ZeroAmount_NotNotified(t *testing.T) {
 	}
 
 	repo.On("GetUsers", mock.Anything).Return([]*domain.User{user}, nil)
-	repo.On("GetUnpaidAmount", mock.Anything, "abc", domain.CurrencyTWD).
-		Return(float64(0), nil)
+	repo.On("GetUnpaidItems", mock.Anything, "abc").
+		Return(twdItems(), nil)
+
+	notifier.On("ReportReminder", mock.Anything, mock.Anything).Return(nil)
 
-	uc := usecase.NewNotifyUnpaid(repo, notifier, testOthersDBID)
+	uc := usecase.NewNotifyUnpaid(repo, notifier, testOthersDBID, testThresholds)
 
-	err := uc.Execute(context.Background(), false)
+	report, err := uc.Execute(context.Background(), domain.ReminderRun{})
 
 	require.NoError(t, err)
+	require.Equal(t, domain.ReminderSkipped, report.Results[0].Outcome)
+	require.Equal(t, "沒有未付款項目", report.Results[0].Reason)
 }
 
 func TestExecute_OthersDB_ZeroAmount_NotNotified(t *testing.T) {
@@ -143,14 +208,17 @@ func TestExecute_OthersDB_ZeroAmount_NotNotified(t *testing.T) {
 	}
 
 	repo.On("GetUsers", mock.Anything).Return([]*domain.User{user}, nil)
-	repo.On("GetOthersUnpaidAmount", mock.Anything, "Carol", domain.CurrencyTWD).
-		Return(float64(0), nil)
+	repo.On("GetOthersUnpaidItems", mock.Anything, "Carol").
+		Return(twdItems(), nil)
 
-	uc := usecase.NewNotifyUnpaid(repo, notifier, testOthersDBID)
+	notifier.On("ReportReminder", mock.Anything, mock.Anything).Return(nil)
 
-	err := uc.Execute(context.Background(), false)
+	uc := usecase.NewNotifyUnpaid(repo, notifier, testOthersDBID, testThresholds)
+
+	report, err := uc.Execute(context.Background(), domain.ReminderRun{})
 
 	require.NoError(t, err)
+	require.Equal(t, domain.ReminderSkipped, report.Results[0].Outcome)
 }
 
 func TestExecute_NotifyError_ContinuesNextUser(t *testing.T) {
@@ -167,17 +235,187 @@ func TestExecute_NotifyError_ContinuesNextUser(t *testing.T) {
 	}
 
 	repo.On("GetUsers", mock.Anything).Return([]*domain.User{user1, user2}, nil)
-	repo.On("GetUnpaidAmount", mock.Anything, "abc", domain.CurrencyTWD).
-		Return(float64(3000), nil)
-	repo.On("GetUnpaidAmount", mock.Anything, "def", domain.CurrencyTWD).
-		Return(float64(3000), nil)
-	notifier.On("Notify", mock.Anything, *user1, false).
+	repo.On("GetUnpaidItems", mock.Anything, "abc").
+		Return(twdItems(3000), nil)
+	repo.On("GetUnpaidItems", mock.Anything, "def").
+		Return(twdItems(3000), nil)
+	notifier.On("Notify", mock.Anything, balanceOf(user1), false).
 		Return(errors.New("discord error"))
-	notifier.On("Notify", mock.Anything, *user2, false).Return(nil)
+	notifier.On("Notify", mock.Anything, balanceOf(user2), false).Return(nil)
+
+	notifier.On("ReportReminder", mock.Anything, mock.Anything).Return(nil)
+
+	uc := usecase.NewNotifyUnpaid(repo, notifier, testOthersDBID, testThresholds)
+
+	report, err := uc.Execute(context.Background(), domain.ReminderRun{})
+
+	require.EqualError(t, err, "notify Alice: discord error")
+	require.Equal(t, 1, report.Count(domain.ReminderNotified))
+	require.Equal(t, []domain.ReminderResult{{
+		User: *user1, Amount: 3000, Threshold: 2000,
+		Outcome: domain.ReminderFailed, Reason: "discord error",
+	}}, report.Failed())
+}
+
+func TestExecute_LedgerError_ContinuesNextUser(t *testing.T) {
+	repo := mocks.NewUserRepository(t)
+	notifier := mocks.NewNotifier(t)
+
+	users := []*domain.User{
+		{DiscordID: "111", Name: "Alice", NotionID: "deleted", Currency: domain.CurrencyTWD},
+		{DiscordID: "222", Name: "Bob", NotionID: "def", Currency: domain.CurrencyTWD},
+		{DiscordID: "333", Name: "Carol", NotionID: "ghi", Currency: domain.CurrencyTWD},
+		{DiscordID: "444", Name: "Dave", NotionID: "gone", Currency: domain.CurrencyTWD},
+		{DiscordID: "555", Name: "Eve", NotionID: "jkl", Currency: domain.CurrencyTWD},
+	}
+
+	repo.On("GetUsers", mock.Anything).Return(users, nil)
+	repo.On("GetUnpaidItems", mock.Anything, "deleted").Return(nil, errors.New("object_not_found"))
+	repo.On("GetUnpaidItems", mock.Anything, "gone").Return(nil, errors.New("object_not_found"))
+	repo.On("GetUnpaidItems", mock.Anything, mock.Anything).Return(twdItems(3000), nil)
+	notifier.On("Notify", mock.Anything, mock.Anything, false).Return(nil)
+	notifier.On("ReportReminder", mock.Anything, mock.Anything).Return(nil)
+
+	uc := usecase.NewNotifyUnpaid(repo, notifier, testOthersDBID, testThresholds)
+
+	report, err := uc.Execute(context.Background(), domain.ReminderRun{})
+
+	require.ErrorContains(t, err, "get unpaid items for Alice: object_not_found")
+	require.ErrorContains(t, err, "get unpaid items for Dave: object_not_found")
+	require.Equal(t, 3, report.Count(domain.ReminderNotified))
+	notifier.AssertNumberOfCalls(t, "Notify", 3)
+
+	// Results keep the TBL-001 order although members are handled concurrently.
+	for n, res := range report.Results {
+		require.Equal(t, users[n].Name, res.User.Name)
+	}
+
+	require.Equal(t, domain.ReminderFailed, report.Results[3].Outcome)
+}
+
+func TestExecute_BelowThreshold_Skipped(t *testing.T) {
+	repo := mocks.NewUserRepository(t)
+	notifier := mocks.NewNotifier(t)
+
+	user := &domain.User{
+		DiscordID: "111", Name: "Alice",
+		NotionID: "abc", Currency: domain.CurrencyJPY,
+	}
+
+	repo.On("GetUsers", mock.Anything).Return([]*domain.User{user}, nil)
+	repo.On("GetUnpaidItems", mock.Anything, "abc").
+		Return([]domain.UnpaidItem{{JPYAmount: 5000}}, nil)
+	notifier.On("ReportReminder", mock.Anything, mock.MatchedBy(func(r domain.ReminderReport) bool {
+		return r.Debug && r.Count(domain.ReminderSkipped) == 1
+	})).Return(errors.New("log channel down"))
+
+	uc := usecase.NewNotifyUnpaid(repo, notifier, testOthersDBID, testThresholds)
+
+	report, err := uc.Execute(context.Background(), domain.ReminderRun{Debug: true})
+
+	require.NoError(t, err, "a failed report post does not fail the run")
+	require.Equal(t, domain.ReminderResult{
+		User: *user, Amount: 5000, Threshold: 8000,
+		Outcome: domain.ReminderSkipped, Reason: "未超過門檻",
+	}, report.Results[0])
+}
+
+func TestExecute_MemberThreshold_Overrides(t *testing.T) {
+	repo := mocks.NewUserRepository(t)
+	notifier := mocks.NewNotifier(t)
+
+	high, zero := 5000.0, 0.0
+	alice := &domain.User{
+		DiscordID: "111", Name: "Alice",
+		NotionID: "abc", Currency: domain.CurrencyTWD, ReminderThreshold: &high,
+	}
+	bob := &domain.User{
+		DiscordID: "222", Name: "Bob",
+		NotionID: "def", Currency: domain.CurrencyTWD, ReminderThreshold: &zero,
+	}
+
+	repo.On("GetUsers", mock.Anything).Return([]*domain.User{alice, bob}, nil)
+	repo.On("GetUnpaidItems", mock.Anything, "abc").Return(twdItems(3000), nil)
+	repo.On("GetUnpaidItems", mock.Anything, "def").Return(twdItems(100), nil)
+	notifier.On("Notify", mock.Anything, balanceOf(bob), false).Return(nil)
+	notifier.On("ReportReminder", mock.Anything, mock.Anything).Return(nil)
+
+	uc := usecase.NewNotifyUnpaid(repo, notifier, testOthersDBID, testThresholds)
+
+	report, err := uc.Execute(context.Background(), domain.ReminderRun{})
+
+	require.NoError(t, err)
+	require.Equal(t, domain.ReminderSkipped, report.Results[0].Outcome)
+	require.InDelta(t, 5000, report.Results[0].Threshold, 0.001)
+	require.Equal(t, domain.ReminderNotified, report.Results[1].Outcome, "a threshold of 0 reminds at any amount")
+	require.Zero(t, report.Results[1].Threshold)
+}
+
+func TestExecute_MinAmount_ReplacesThresholds(t *testing.T) {
+	repo := mocks.NewUserRepository(t)
+	notifier := mocks.NewNotifier(t)
+
+	threshold := 5000.0
+	alice := &domain.User{
+		DiscordID: "111", Name: "Alice",
+		NotionID: "abc", Currency: domain.CurrencyTWD, ReminderThreshold: &threshold,
+	}
+	bob := &domain.User{DiscordID: "222", Name: "Bob", NotionID: testOthersDBID, Currency: domain.CurrencyTWD}
+
+	repo.On("GetUsers", mock.Anything).Return([]*domain.User{alice, bob}, nil)
+	repo.On("GetUnpaidItems", mock.Anything, "abc").Return(twdItems(600), nil)
+	repo.On("GetOthersUnpaidItems", mock.Anything, "Bob").Return(twdItems(300), nil)
+	notifier.On("Notify", mock.Anything, balanceOf(alice), false).Return(nil)
+	notifier.On("ReportReminder", mock.Anything, mock.MatchedBy(func(r domain.ReminderReport) bool {
+		return r.MinAmount != nil && *r.MinAmount == 500
+	})).Return(nil)
+
+	uc := usecase.NewNotifyUnpaid(repo, notifier, testOthersDBID, testThresholds)
+
+	minAmount := 500.0
+	report, err := uc.Execute(context.Background(), domain.ReminderRun{MinAmount: &minAmount})
+
+	require.NoError(t, err)
+	require.Equal(t, domain.ReminderNotified, report.Results[0].Outcome)
+	require.Equal(t, domain.ReminderSkipped, report.Results[1].Outcome)
+	require.InDelta(t, 500, report.Results[1].Threshold, 0.001)
+}
+
+func TestExecute_MinAmount_Inclusive(t *testing.T) {
+	repo := mocks.NewUserRepository(t)
+	notifier := mocks.NewNotifier(t)
+
+	alice := &domain.User{DiscordID: "111", Name: "Alice", NotionID: "abc", Currency: domain.CurrencyTWD}
+
+	repo.On("GetUsers", mock.Anything).Return([]*domain.User{alice}, nil)
+	repo.On("GetUnpaidItems", mock.Anything, "abc").Return(twdItems(500), nil)
+	notifier.On("Notify", mock.Anything, mock.Anything, false).Return(nil)
+	notifier.On("ReportReminder", mock.Anything, mock.Anything).Return(nil)
+
+	uc := usecase.NewNotifyUnpaid(repo, notifier, testOthersDBID, testThresholds)
+
+	minAmount := 500.0
+	report, err := uc.Execute(context.Background(), domain.ReminderRun{MinAmount: &minAmount})
+
+	require.NoError(t, err)
+	require.Equal(t, domain.ReminderNotified, report.Results[0].Outcome, "owing exactly min_amount is reminded")
+}
+
+func TestExecute_SharedMemberWithDifferentIDForm(t *testing.T) {
+	repo := mocks.NewUserRepository(t)
+	notifier := mocks.NewNotifier(t)
+
+	bob := &domain.User{DiscordID: "222", Name: "Bob", NotionID: "Others-DB", Currency: domain.CurrencyTWD}
 
-	uc := usecase.NewNotifyUnpaid(repo, notifier, testOthersDBID)
+	repo.On("GetUsers", mock.Anything).Return([]*domain.User{bob}, nil)
+	repo.On("GetOthersUnpaidItems", mock.Anything, "Bob").Return(twdItems(100), nil)
+	notifier.On("Notify", mock.Anything, mock.Anything, false).Return(nil)
+	notifier.On("ReportReminder", mock.Anything, mock.Anything).Return(nil)
 
-	err := uc.Execute(context.Background(), false)
+	uc := usecase.NewNotifyUnpaid(repo, notifier, "othersdb", testThresholds)
+	report, err := uc.Execute(context.Background(), domain.ReminderRun{})
 
 	require.NoError(t, err)
+	require.Equal(t, domain.ReminderNotified, report.Results[0].Outcome)
+	require.InDelta(t, testThresholds.Others, report.Results[0].Threshold, 0.001)
 }
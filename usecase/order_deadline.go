@@ -0,0 +1,127 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/xgnid-tw/gx5/domain"
+	"github.com/xgnid-tw/gx5/port"
+)
+
+// deadlineReminders are posted in the order thread this long before the deadline.
+var deadlineReminders = []time.Duration{24 * time.Hour, time.Hour}
+
+type OrderDeadline struct {
+	repo          port.OrderRepository
+	threadCreator port.ThreadCreator
+	loc           *time.Location
+}
+
+func NewOrderDeadline(
+	repo port.OrderRepository, threadCreator port.ThreadCreator, loc *time.Location,
+) *OrderDeadline {
+	return &OrderDeadline{repo: repo, threadCreator: threadCreator, loc: loc}
+}
+
+// Plan derives the pending events of every open order from TBL-004. Reminders
+// whose time has passed are dropped, while an order already past its deadline
+// gets a closing event at now so that it is closed after a restart.
+func (uc *OrderDeadline) Plan(ctx context.Context, now time.Time) ([]domain.DeadlineEvent, error) {
+	orders, err := uc.repo.GetOpenOrders(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get open orders: %w", err)
+	}
+
+	var events []domain.DeadlineEvent
+
+	for _, o := range orders {
+		if o.ThreadID == "" || o.Deadline == "" {
+			continue
+		}
+
+		deadline, err := domain.DeadlineAt(o.Deadline, uc.loc)
+		if err != nil {
+			log.Printf("skip order %s: %s", o.ThreadName, err)
+			continue
+		}
+
+		for _, left := range deadlineReminders {
+			at := deadline.Add(-left)
+			if at.After(now) {
+				events = append(events, domain.DeadlineEvent{ThreadID: o.ThreadID, At: at, Left: left})
+			}
+		}
+
+		events = append(events, domain.DeadlineEvent{ThreadID: o.ThreadID, At: later(deadline, now)})
+	}
+
+	return events, nil
+}
+
+// Run posts the reminder or closes the order. The order is read again first, so
+// nothing happens when it was closed by hand or its deadline moved since Plan.
+func (uc *OrderDeadline) Run(ctx context.Context, event domain.DeadlineEvent) error {
+	order, err := uc.repo.GetOrderByThreadID(ctx, event.ThreadID)
+	if err != nil {
+		return fmt.Errorf("get order for thread %s: %w", event.ThreadID, err)
+	}
+
+	if order.Status != "" && order.Status != domain.OrderStatusOpen {
+		return nil
+	}
+
+	deadline, err := domain.DeadlineAt(order.Deadline, uc.loc)
+	if err != nil {
+		return fmt.Errorf("order %s: %w", order.ThreadName, err)
+	}
+
+	if event.IsClose() {
+		if deadline.After(event.At) {
+			return nil
+		}
+
+		return uc.close(ctx, order)
+	}
+
+	if !deadline.Equal(event.At.Add(event.Left)) {
+		return nil
+	}
+
+	err = uc.threadCreator.SendThreadMessage(ctx, order.ThreadID, fmt.Sprintf(
+		"⏰ 距離截止還有 %d 小時（截止時間: %s）", int(event.Left.Hours()), order.Deadline,
+	))
+	if err != nil {
+		return fmt.Errorf("send deadline reminder for %s: %w", order.ThreadName, err)
+	}
+
+	return nil
+}
+
+func (uc *OrderDeadline) close(ctx context.Context, order *domain.Order) error {
+	err := uc.repo.UpdateOrderStatus(ctx, order.PageID, domain.OrderStatusClosed)
+	if err != nil {
+		return fmt.Errorf("update order status for %s: %w", order.ThreadName, err)
+	}
+
+	err = uc.threadCreator.SendThreadMessage(ctx, order.ThreadID, "訂單已截止，感謝參與！")
+	if err != nil {
+		log.Printf("send closing message for %s: %s", order.ThreadName, err)
+	}
+
+	err = uc.threadCreator.CloseThread(ctx, order.ThreadID, closedThreadName(order.ThreadName))
+	if err != nil {
+		return fmt.Errorf("close thread %s: %w", order.ThreadID, err)
+	}
+
+	return nil
+}
+
+func later(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+
+	return b
+}
@@ -0,0 +1,193 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/xgnid-tw/gx5/domain"
+	"github.com/xgnid-tw/gx5/mocks"
+	"github.com/xgnid-tw/gx5/usecase"
+)
+
+var tokyo = time.FixedZone("Asia/Tokyo", 9*60*60)
+
+// deadlineOrder closes at 2026-04-02 00:00 JST.
+func deadlineOrder(status domain.OrderStatus) *domain.Order {
+	return &domain.Order{
+		PageID:     "order-page",
+		ThreadName: "test order",
+		Deadline:   "2026-04-01",
+		ThreadID:   "thread-1",
+		Status:     status,
+	}
+}
+
+func TestOrderDeadline_Plan(t *testing.T) {
+	repo := mocks.NewOrderRepository(t)
+	tc := mocks.NewThreadCreator(t)
+
+	repo.On("GetOpenOrders", mock.Anything).Return([]domain.Order{
+		*deadlineOrder(""),
+		{ThreadName: "no thread", Deadline: "2026-04-01"},
+		{ThreadName: "no deadline", ThreadID: "thread-2"},
+		{ThreadName: "bad deadline", ThreadID: "thread-3", Deadline: "4/1"},
+	}, nil)
+
+	uc := usecase.NewOrderDeadline(repo, tc, tokyo)
+	now := time.Date(2026, 3, 30, 12, 0, 0, 0, tokyo)
+
+	events, err := uc.Plan(context.Background(), now)
+
+	require.NoError(t, err)
+	require.Equal(t, []domain.DeadlineEvent{
+		{ThreadID: "thread-1", At: time.Date(2026, 4, 1, 0, 0, 0, 0, tokyo), Left: 24 * time.Hour},
+		{ThreadID: "thread-1", At: time.Date(2026, 4, 1, 23, 0, 0, 0, tokyo), Left: time.Hour},
+		{ThreadID: "thread-1", At: time.Date(2026, 4, 2, 0, 0, 0, 0, tokyo)},
+	}, events)
+}
+
+func TestOrderDeadline_Plan_DropsPastReminders(t *testing.T) {
+	repo := mocks.NewOrderRepository(t)
+	tc := mocks.NewThreadCreator(t)
+
+	repo.On("GetOpenOrders", mock.Anything).Return([]domain.Order{*deadlineOrder("")}, nil)
+
+	uc := usecase.NewOrderDeadline(repo, tc, tokyo)
+	now := time.Date(2026, 4, 1, 12, 0, 0, 0, tokyo)
+
+	events, err := uc.Plan(context.Background(), now)
+
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	require.Equal(t, time.Hour, events[0].Left)
+	require.True(t, events[1].IsClose())
+}
+
+func TestOrderDeadline_Plan_OverdueClosesNow(t *testing.T) {
+	repo := mocks.NewOrderRepository(t)
+	tc := mocks.NewThreadCreator(t)
+
+	repo.On("GetOpenOrders", mock.Anything).Return([]domain.Order{*deadlineOrder("")}, nil)
+
+	uc := usecase.NewOrderDeadline(repo, tc, tokyo)
+	now := time.Date(2026, 4, 3, 9, 0, 0, 0, tokyo)
+
+	events, err := uc.Plan(context.Background(), now)
+
+	require.NoError(t, err)
+	require.Equal(t, []domain.DeadlineEvent{{ThreadID: "thread-1", At: now}}, events)
+}
+
+func TestOrderDeadline_Plan_Error(t *testing.T) {
+	repo := mocks.NewOrderRepository(t)
+	tc := mocks.NewThreadCreator(t)
+
+	repo.On("GetOpenOrders", mock.Anything).Return(nil, errors.New("notion error"))
+
+	uc := usecase.NewOrderDeadline(repo, tc, tokyo)
+	_, err := uc.Plan(context.Background(), time.Now())
+
+	require.ErrorContains(t, err, "get open orders")
+}
+
+func TestOrderDeadline_Run_Reminder(t *testing.T) {
+	repo := mocks.NewOrderRepository(t)
+	tc := mocks.NewThreadCreator(t)
+
+	repo.On("GetOrderByThreadID", mock.Anything, "thread-1").Return(deadlineOrder(domain.OrderStatusOpen), nil)
+	tc.On("SendThreadMessage", mock.Anything, "thread-1", "⏰ 距離截止還有 24 小時（截止時間: 2026-04-01）").
+		Return(nil)
+
+	uc := usecase.NewOrderDeadline(repo, tc, tokyo)
+	err := uc.Run(context.Background(), domain.DeadlineEvent{
+		ThreadID: "thread-1", At: time.Date(2026, 4, 1, 0, 0, 0, 0, tokyo), Left: 24 * time.Hour,
+	})
+
+	require.NoError(t, err)
+}
+
+func TestOrderDeadline_Run_ReminderForMovedDeadline(t *testing.T) {
+	repo := mocks.NewOrderRepository(t)
+	tc := mocks.NewThreadCreator(t)
+
+	moved := deadlineOrder(domain.OrderStatusOpen)
+	moved.Deadline = "2026-04-05"
+
+	repo.On("GetOrderByThreadID", mock.Anything, "thread-1").Return(moved, nil)
+
+	uc := usecase.NewOrderDeadline(repo, tc, tokyo)
+	err := uc.Run(context.Background(), domain.DeadlineEvent{
+		ThreadID: "thread-1", At: time.Date(2026, 4, 1, 0, 0, 0, 0, tokyo), Left: 24 * time.Hour,
+	})
+
+	require.NoError(t, err)
+}
+
+func TestOrderDeadline_Run_Close(t *testing.T) {
+	repo := mocks.NewOrderRepository(t)
+	tc := mocks.NewThreadCreator(t)
+
+	repo.On("GetOrderByThreadID", mock.Anything, "thread-1").Return(deadlineOrder(""), nil)
+	repo.On("UpdateOrderStatus", mock.Anything, "order-page", domain.OrderStatusClosed).Return(nil)
+	tc.On("SendThreadMessage", mock.Anything, "thread-1", mock.Anything).Return(nil)
+	tc.On("CloseThread", mock.Anything, "thread-1", "【已截止】test order").Return(nil)
+
+	uc := usecase.NewOrderDeadline(repo, tc, tokyo)
+	err := uc.Run(context.Background(), domain.DeadlineEvent{
+		ThreadID: "thread-1", At: time.Date(2026, 4, 2, 0, 0, 0, 0, tokyo),
+	})
+
+	require.NoError(t, err)
+}
+
+func TestOrderDeadline_Run_CloseSkipsClosedOrder(t *testing.T) {
+	repo := mocks.NewOrderRepository(t)
+	tc := mocks.NewThreadCreator(t)
+
+	repo.On("GetOrderByThreadID", mock.Anything, "thread-1").Return(deadlineOrder(domain.OrderStatusClosed), nil)
+
+	uc := usecase.NewOrderDeadline(repo, tc, tokyo)
+	err := uc.Run(context.Background(), domain.DeadlineEvent{
+		ThreadID: "thread-1", At: time.Date(2026, 4, 2, 0, 0, 0, 0, tokyo),
+	})
+
+	require.NoError(t, err)
+}
+
+func TestOrderDeadline_Run_CloseSkipsExtendedDeadline(t *testing.T) {
+	repo := mocks.NewOrderRepository(t)
+	tc := mocks.NewThreadCreator(t)
+
+	extended := deadlineOrder(domain.OrderStatusOpen)
+	extended.Deadline = "2026-04-05"
+
+	repo.On("GetOrderByThreadID", mock.Anything, "thread-1").Return(extended, nil)
+
+	uc := usecase.NewOrderDeadline(repo, tc, tokyo)
+	err := uc.Run(context.Background(), domain.DeadlineEvent{
+		ThreadID: "thread-1", At: time.Date(2026, 4, 2, 0, 0, 0, 0, tokyo),
+	})
+
+	require.NoError(t, err)
+}
+
+func TestOrderDeadline_Run_CloseUpdateError(t *testing.T) {
+	repo := mocks.NewOrderRepository(t)
+	tc := mocks.NewThreadCreator(t)
+
+	repo.On("GetOrderByThreadID", mock.Anything, "thread-1").Return(deadlineOrder(""), nil)
+	repo.On("UpdateOrderStatus", mock.Anything, "order-page", domain.OrderStatusClosed).
+		Return(errors.New("notion error"))
+
+	uc := usecase.NewOrderDeadline(repo, tc, tokyo)
+	err := uc.Run(context.Background(), domain.DeadlineEvent{
+		ThreadID: "thread-1", At: time.Date(2026, 4, 2, 0, 0, 0, 0, tokyo),
+	})
+
+	require.ErrorContains(t, err, "update order status")
+}
@@ -0,0 +1,68 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/xgnid-tw/gx5/domain"
+	"github.com/xgnid-tw/gx5/port"
+)
+
+// orderDatabase is a TBL-002 or TBL-003 database that may hold rows linked to an order.
+type orderDatabase struct {
+	id    string
+	owner string // member name; empty for TBL-003, whose rows name their 購買人
+}
+
+// participant returns who a row of the database belongs to.
+func (db orderDatabase) participant(it domain.ItemRecord) string {
+	if db.owner == "" {
+		return it.BuyerName
+	}
+
+	return db.owner
+}
+
+// orderDatabases lists the shared TBL-003 followed by every member's own TBL-002.
+func orderDatabases(users []*domain.User, othersDBID string) []orderDatabase {
+	dbs := []orderDatabase{{id: othersDBID}}
+
+	for _, u := range users {
+		if !sameNotionID(u.NotionID, othersDBID) {
+			dbs = append(dbs, orderDatabase{id: u.NotionID, owner: u.Name})
+		}
+	}
+
+	return dbs
+}
+
+// readOrderItems reads the rows linked to the order from each database, a few
+// databases at a time. items and errs are indexed like dbs, so a database that
+// cannot be read does not keep the others from being read.
+func readOrderItems(
+	ctx context.Context, repo port.ItemRepository, dbs []orderDatabase, orderID string,
+) (items [][]domain.ItemRecord, errs []error) {
+	items = make([][]domain.ItemRecord, len(dbs))
+	errs = make([]error, len(dbs))
+
+	parallel(len(dbs), func(n int) {
+		items[n], errs[n] = readDatabaseItems(ctx, repo, dbs[n], orderID)
+	})
+
+	return items, errs
+}
+
+func readDatabaseItems(
+	ctx context.Context, repo port.ItemRepository, db orderDatabase, orderID string,
+) ([]domain.ItemRecord, error) {
+	items, err := repo.GetOrderItems(ctx, db.id, orderID)
+	if err != nil {
+		if db.owner == "" {
+			return nil, fmt.Errorf("get order items in others database: %w", err)
+		}
+
+		return nil, fmt.Errorf("get order items for %s: %w", db.owner, err)
+	}
+
+	return items, nil
+}
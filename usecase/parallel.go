@@ -0,0 +1,31 @@
+package usecase
+
+import "sync"
+
+// memberWorkers bounds how many members or databases are read at once. Notion
+// requests are still paced by the gateway's shared rate limiter.
+const memberWorkers = 4
+
+// parallel calls fn for every index below n on at most memberWorkers
+// goroutines and returns once all calls are done. fn must only write to
+// index-owned state.
+func parallel(n int, fn func(i int)) {
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+
+	for range min(memberWorkers, n) {
+		wg.Go(func() {
+			for i := range jobs {
+				fn(i)
+			}
+		})
+	}
+
+	for i := range n {
+		jobs <- i
+	}
+
+	close(jobs)
+	wg.Wait()
+}
@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math"
 
@@ -12,45 +13,104 @@ import (
 type RegisterBuyRecord struct {
 	userRepo     port.UserRepository
 	txRepo       port.TransactionRepository
+	orderRepo    port.OrderRepository
 	jpyToTWDRate float64
+	othersDBID   string
 }
 
 func NewRegisterBuyRecord(
-	userRepo port.UserRepository, txRepo port.TransactionRepository, jpyToTWDRate float64,
+	userRepo port.UserRepository, txRepo port.TransactionRepository, orderRepo port.OrderRepository,
+	jpyToTWDRate float64, othersDBID string,
 ) *RegisterBuyRecord {
-	return &RegisterBuyRecord{userRepo: userRepo, txRepo: txRepo, jpyToTWDRate: jpyToTWDRate}
+	return &RegisterBuyRecord{
+		userRepo: userRepo, txRepo: txRepo, orderRepo: orderRepo,
+		jpyToTWDRate: jpyToTWDRate, othersDBID: othersDBID,
+	}
 }
 
-func (uc *RegisterBuyRecord) Execute(
-	ctx context.Context, targetDiscordID string, jpyAmount float64, itemName string,
-) (*domain.BuyResult, error) {
-	user, err := uc.userRepo.GetUserByDiscordID(ctx, targetDiscordID)
+func (uc *RegisterBuyRecord) Execute(ctx context.Context, req domain.BuyRequest) (*domain.BuyResult, error) {
+	user, err := uc.userRepo.GetUserByDiscordID(ctx, req.TargetDiscordID)
 	if err != nil {
 		return nil, fmt.Errorf("get user by discord id: %w", err)
 	}
 
-	twdAmount := math.Round(jpyAmount * uc.jpyToTWDRate)
+	twdAmount := math.Round(req.JPYAmount * uc.jpyToTWDRate)
 
 	tx := domain.Transaction{
-		ItemName:   itemName,
-		JPYAmount:  jpyAmount,
-		TWDAmount:  twdAmount,
-		DatabaseID: user.NotionID,
+		ItemName:        req.ItemName,
+		JPYAmount:       req.JPYAmount,
+		TWDAmount:       twdAmount,
+		DatabaseID:      user.NotionID,
+		ItemStatus:      domain.ItemStatusNotOrdered,
+		Shop:            domain.ShopFromURL(req.Link),
+		Link:            req.Link,
+		Note:            req.Note,
+		ExpectedArrival: req.ExpectedArrival,
+	}
+
+	order, err := uc.order(ctx, req.ThreadID)
+	if err != nil {
+		return nil, err
+	}
+
+	if order != nil {
+		tx.OrderID = order.PageID
 	}
 
-	err = uc.txRepo.CreateTransaction(ctx, tx)
+	// Rows in the shared TBL-003 are attributed to members by 購買人 only.
+	if sameNotionID(user.NotionID, uc.othersDBID) {
+		tx.BuyerName = user.Name
+	}
+
+	pageID, err := uc.txRepo.CreateTransaction(ctx, tx)
 	if err != nil {
 		return nil, fmt.Errorf("create transaction: %w", err)
 	}
 
-	displayAmount := twdAmount
+	return buyResult(user, pageID, tx), nil
+}
+
+// buyResult reports tx in the currency the member pays in.
+func buyResult(user *domain.User, pageID string, tx domain.Transaction) *domain.BuyResult {
+	displayAmount := tx.TWDAmount
 	if user.Currency == domain.CurrencyJPY {
-		displayAmount = jpyAmount
+		displayAmount = tx.JPYAmount
 	}
 
 	return &domain.BuyResult{
-		DisplayAmount: displayAmount,
-		Currency:      user.Currency,
-		ItemName:      itemName,
-	}, nil
+		PageID:          pageID,
+		TargetDiscordID: user.DiscordID,
+		DisplayAmount:   displayAmount,
+		Currency:        user.Currency,
+		ItemName:        tx.ItemName,
+		ItemStatus:      tx.ItemStatus,
+	}
+}
+
+func (uc *RegisterBuyRecord) OrderShopURL(ctx context.Context, threadID string) (string, error) {
+	order, err := uc.order(ctx, threadID)
+	if err != nil || order == nil {
+		return "", err
+	}
+
+	return order.ShopURL, nil
+}
+
+// order returns the TBL-004 order opened in the thread, or nil when the thread
+// has no order, e.g. one created before threadID was recorded.
+func (uc *RegisterBuyRecord) order(ctx context.Context, threadID string) (*domain.Order, error) {
+	if threadID == "" {
+		return nil, nil
+	}
+
+	order, err := uc.orderRepo.GetOrderByThreadID(ctx, threadID)
+	if errors.Is(err, port.ErrOrderNotFound) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("get order for thread %s: %w", threadID, err)
+	}
+
+	return order, nil
 }
@@ -10,6 +10,7 @@ import (
 
 	"github.com/xgnid-tw/gx5/domain"
 	"github.com/xgnid-tw/gx5/mocks"
+	"github.com/xgnid-tw/gx5/port"
 	"github.com/xgnid-tw/gx5/usecase"
 )
 
@@ -28,12 +29,17 @@ func TestRegisterBuyRecord_Success(t *testing.T) {
 		JPYAmount:  3000,
 		TWDAmount:  720,
 		DatabaseID: "abc-db",
-	}).Return(nil)
+		ItemStatus: domain.ItemStatusNotOrdered,
+	}).Return("page-1", nil)
 
-	uc := usecase.NewRegisterBuyRecord(userRepo, txRepo, 0.24)
-	result, err := uc.Execute(context.Background(), "111", 3000, "Thread Title")
+	uc := usecase.NewRegisterBuyRecord(userRepo, txRepo, mocks.NewOrderRepository(t), 0.24, testOthersDBID)
+	result, err := uc.Execute(context.Background(), domain.BuyRequest{
+		TargetDiscordID: "111", JPYAmount: 3000, ItemName: "Thread Title",
+	})
 
 	require.NoError(t, err)
+	require.Equal(t, "page-1", result.PageID)
+	require.Equal(t, domain.ItemStatusNotOrdered, result.ItemStatus)
 	require.Equal(t, float64(3000), result.DisplayAmount)
 	require.Equal(t, domain.CurrencyJPY, result.Currency)
 	require.Equal(t, "Thread Title", result.ItemName)
@@ -54,16 +60,65 @@ func TestRegisterBuyRecord_Success_TWDUser(t *testing.T) {
 		JPYAmount:  3000,
 		TWDAmount:  720,
 		DatabaseID: "bob-db",
-	}).Return(nil)
+		ItemStatus: domain.ItemStatusNotOrdered,
+	}).Return("page-1", nil)
 
-	uc := usecase.NewRegisterBuyRecord(userRepo, txRepo, 0.24)
-	result, err := uc.Execute(context.Background(), "222", 3000, "Item")
+	uc := usecase.NewRegisterBuyRecord(userRepo, txRepo, mocks.NewOrderRepository(t), 0.24, testOthersDBID)
+	result, err := uc.Execute(context.Background(), domain.BuyRequest{
+		TargetDiscordID: "222", JPYAmount: 3000, ItemName: "Item",
+	})
 
 	require.NoError(t, err)
 	require.Equal(t, float64(720), result.DisplayAmount)
 	require.Equal(t, domain.CurrencyTWD, result.Currency)
 }
 
+func TestRegisterBuyRecord_Success_OthersDBUser(t *testing.T) {
+	userRepo := mocks.NewUserRepository(t)
+	txRepo := mocks.NewTransactionRepository(t)
+
+	user := &domain.User{
+		DiscordID: "333", Name: "Carol",
+		NotionID: testOthersDBID, Currency: domain.CurrencyTWD,
+	}
+
+	userRepo.On("GetUserByDiscordID", mock.Anything, "333").Return(user, nil)
+	txRepo.On("CreateTransaction", mock.Anything, domain.Transaction{
+		ItemName:   "Item",
+		JPYAmount:  3000,
+		TWDAmount:  720,
+		DatabaseID: testOthersDBID,
+		BuyerName:  "Carol",
+		ItemStatus: domain.ItemStatusNotOrdered,
+	}).Return("page-1", nil)
+
+	uc := usecase.NewRegisterBuyRecord(userRepo, txRepo, mocks.NewOrderRepository(t), 0.24, testOthersDBID)
+	_, err := uc.Execute(context.Background(), domain.BuyRequest{
+		TargetDiscordID: "333", JPYAmount: 3000, ItemName: "Item",
+	})
+
+	require.NoError(t, err)
+}
+
+func TestRegisterBuyRecord_OthersDBUser_DifferentIDForm(t *testing.T) {
+	userRepo := mocks.NewUserRepository(t)
+	txRepo := mocks.NewTransactionRepository(t)
+
+	user := &domain.User{DiscordID: "333", Name: "Carol", NotionID: "Others-DB", Currency: domain.CurrencyTWD}
+
+	userRepo.On("GetUserByDiscordID", mock.Anything, "333").Return(user, nil)
+	txRepo.On("CreateTransaction", mock.Anything, mock.MatchedBy(func(tx domain.Transaction) bool {
+		return tx.BuyerName == "Carol"
+	})).Return("page-1", nil)
+
+	uc := usecase.NewRegisterBuyRecord(userRepo, txRepo, mocks.NewOrderRepository(t), 0.24, "othersdb")
+	_, err := uc.Execute(context.Background(), domain.BuyRequest{
+		TargetDiscordID: "333", JPYAmount: 3000, ItemName: "Item",
+	})
+
+	require.NoError(t, err)
+}
+
 func TestRegisterBuyRecord_UserNotFound(t *testing.T) {
 	userRepo := mocks.NewUserRepository(t)
 	txRepo := mocks.NewTransactionRepository(t)
@@ -71,8 +126,10 @@ func TestRegisterBuyRecord_UserNotFound(t *testing.T) {
 	userRepo.On("GetUserByDiscordID", mock.Anything, "999").
 		Return(nil, errors.New("user not found"))
 
-	uc := usecase.NewRegisterBuyRecord(userRepo, txRepo, 0.24)
-	result, err := uc.Execute(context.Background(), "999", 3000, "Thread Title")
+	uc := usecase.NewRegisterBuyRecord(userRepo, txRepo, mocks.NewOrderRepository(t), 0.24, testOthersDBID)
+	result, err := uc.Execute(context.Background(), domain.BuyRequest{
+		TargetDiscordID: "999", JPYAmount: 3000, ItemName: "Thread Title",
+	})
 
 	require.Error(t, err)
 	require.Nil(t, result)
@@ -90,10 +147,12 @@ func TestRegisterBuyRecord_CreateTransactionError(t *testing.T) {
 
 	userRepo.On("GetUserByDiscordID", mock.Anything, "111").Return(user, nil)
 	txRepo.On("CreateTransaction", mock.Anything, mock.Anything).
-		Return(errors.New("notion error"))
+		Return("", errors.New("notion error"))
 
-	uc := usecase.NewRegisterBuyRecord(userRepo, txRepo, 0.24)
-	result, err := uc.Execute(context.Background(), "111", 3000, "Thread Title")
+	uc := usecase.NewRegisterBuyRecord(userRepo, txRepo, mocks.NewOrderRepository(t), 0.24, testOthersDBID)
+	result, err := uc.Execute(context.Background(), domain.BuyRequest{
+		TargetDiscordID: "111", JPYAmount: 3000, ItemName: "Thread Title",
+	})
 
 	require.Error(t, err)
 	require.Nil(t, result)
@@ -112,10 +171,12 @@ func TestRegisterBuyRecord_ExchangeRate(t *testing.T) {
 	userRepo.On("GetUserByDiscordID", mock.Anything, "111").Return(user, nil)
 	txRepo.On("CreateTransaction", mock.Anything, mock.MatchedBy(func(tx domain.Transaction) bool {
 		return tx.JPYAmount == 10000 && tx.TWDAmount == 2400
-	})).Return(nil)
+	})).Return("page-1", nil)
 
-	uc := usecase.NewRegisterBuyRecord(userRepo, txRepo, 0.24)
-	result, err := uc.Execute(context.Background(), "111", 10000, "Item")
+	uc := usecase.NewRegisterBuyRecord(userRepo, txRepo, mocks.NewOrderRepository(t), 0.24, testOthersDBID)
+	result, err := uc.Execute(context.Background(), domain.BuyRequest{
+		TargetDiscordID: "111", JPYAmount: 10000, ItemName: "Item",
+	})
 
 	require.NoError(t, err)
 	require.Equal(t, float64(10000), result.DisplayAmount)
@@ -134,11 +195,160 @@ func TestRegisterBuyRecord_TWDRounded(t *testing.T) {
 	userRepo.On("GetUserByDiscordID", mock.Anything, "111").Return(user, nil)
 	txRepo.On("CreateTransaction", mock.Anything, mock.MatchedBy(func(tx domain.Transaction) bool {
 		return tx.JPYAmount == 3500 && tx.TWDAmount == 760
-	})).Return(nil)
+	})).Return("page-1", nil)
 
-	uc := usecase.NewRegisterBuyRecord(userRepo, txRepo, 0.217)
-	result, err := uc.Execute(context.Background(), "111", 3500, "Item")
+	uc := usecase.NewRegisterBuyRecord(userRepo, txRepo, mocks.NewOrderRepository(t), 0.217, testOthersDBID)
+	result, err := uc.Execute(context.Background(), domain.BuyRequest{
+		TargetDiscordID: "111", JPYAmount: 3500, ItemName: "Item",
+	})
 
 	require.NoError(t, err)
 	require.Equal(t, float64(3500), result.DisplayAmount)
 }
+
+func TestRegisterBuyRecord_Details(t *testing.T) {
+	userRepo := mocks.NewUserRepository(t)
+	txRepo := mocks.NewTransactionRepository(t)
+
+	user := &domain.User{
+		DiscordID: "111", Name: "Alice",
+		NotionID: "abc-db", Currency: domain.CurrencyJPY,
+	}
+
+	userRepo.On("GetUserByDiscordID", mock.Anything, "111").Return(user, nil)
+	txRepo.On("CreateTransaction", mock.Anything, domain.Transaction{
+		ItemName:        "Item",
+		JPYAmount:       3000,
+		TWDAmount:       720,
+		DatabaseID:      "abc-db",
+		ItemStatus:      domain.ItemStatusNotOrdered,
+		Shop:            "HMV",
+		Link:            "https://www.hmv.co.jp/product/detail/1",
+		Note:            "2 個",
+		ExpectedArrival: "2026-12-01",
+	}).Return("page-1", nil)
+
+	uc := usecase.NewRegisterBuyRecord(userRepo, txRepo, mocks.NewOrderRepository(t), 0.24, testOthersDBID)
+	_, err := uc.Execute(context.Background(), domain.BuyRequest{
+		TargetDiscordID: "111", JPYAmount: 3000, ItemName: "Item",
+		Link: "https://www.hmv.co.jp/product/detail/1",
+		Note: "2 個", ExpectedArrival: "2026-12-01",
+	})
+
+	require.NoError(t, err)
+}
+
+func TestRegisterBuyRecord_ShopFromLink(t *testing.T) {
+	tests := []struct {
+		link string
+		want string
+	}{
+		{"https://shop.asobistore.jp/products/detail/1", "阿搜比"},
+		{"https://www.animate-onlineshop.jp/pn/1/", "アニメイトストア"},
+		{"https://someone.booth.pm/items/1", "Booth"},
+		{"https://example.com/item", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.link, func(t *testing.T) {
+			userRepo := mocks.NewUserRepository(t)
+			txRepo := mocks.NewTransactionRepository(t)
+
+			user := &domain.User{DiscordID: "111", Name: "Alice", NotionID: "abc-db", Currency: domain.CurrencyJPY}
+
+			userRepo.On("GetUserByDiscordID", mock.Anything, "111").Return(user, nil)
+			txRepo.On("CreateTransaction", mock.Anything, mock.MatchedBy(func(tx domain.Transaction) bool {
+				return tx.Shop == tt.want && tx.Link == tt.link
+			})).Return("page-1", nil)
+
+			uc := usecase.NewRegisterBuyRecord(userRepo, txRepo, mocks.NewOrderRepository(t), 0.24, testOthersDBID)
+			_, err := uc.Execute(context.Background(), domain.BuyRequest{
+				TargetDiscordID: "111", JPYAmount: 3000, ItemName: "Item", Link: tt.link,
+			})
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestRegisterBuyRecord_LinksOrder(t *testing.T) {
+	userRepo := mocks.NewUserRepository(t)
+	txRepo := mocks.NewTransactionRepository(t)
+	orderRepo := mocks.NewOrderRepository(t)
+
+	user := &domain.User{DiscordID: "111", Name: "Alice", NotionID: "abc-db", Currency: domain.CurrencyJPY}
+
+	userRepo.On("GetUserByDiscordID", mock.Anything, "111").Return(user, nil)
+	orderRepo.On("GetOrderByThreadID", mock.Anything, "thread-1").
+		Return(&domain.Order{PageID: "order-page", ThreadID: "thread-1"}, nil)
+	txRepo.On("CreateTransaction", mock.Anything, mock.MatchedBy(func(tx domain.Transaction) bool {
+		return tx.OrderID == "order-page"
+	})).Return("page-1", nil)
+
+	uc := usecase.NewRegisterBuyRecord(userRepo, txRepo, orderRepo, 0.24, testOthersDBID)
+	_, err := uc.Execute(context.Background(), domain.BuyRequest{
+		TargetDiscordID: "111", ThreadID: "thread-1", JPYAmount: 3000, ItemName: "Item",
+	})
+
+	require.NoError(t, err)
+}
+
+func TestRegisterBuyRecord_ThreadWithoutOrder(t *testing.T) {
+	userRepo := mocks.NewUserRepository(t)
+	txRepo := mocks.NewTransactionRepository(t)
+	orderRepo := mocks.NewOrderRepository(t)
+
+	user := &domain.User{DiscordID: "111", Name: "Alice", NotionID: "abc-db", Currency: domain.CurrencyJPY}
+
+	userRepo.On("GetUserByDiscordID", mock.Anything, "111").Return(user, nil)
+	orderRepo.On("GetOrderByThreadID", mock.Anything, "thread-1").Return(nil, port.ErrOrderNotFound)
+	txRepo.On("CreateTransaction", mock.Anything, mock.MatchedBy(func(tx domain.Transaction) bool {
+		return tx.OrderID == ""
+	})).Return("page-1", nil)
+
+	uc := usecase.NewRegisterBuyRecord(userRepo, txRepo, orderRepo, 0.24, testOthersDBID)
+	_, err := uc.Execute(context.Background(), domain.BuyRequest{
+		TargetDiscordID: "111", ThreadID: "thread-1", JPYAmount: 3000, ItemName: "Item",
+	})
+
+	require.NoError(t, err)
+}
+
+func TestRegisterBuyRecord_OrderLookupError(t *testing.T) {
+	userRepo := mocks.NewUserRepository(t)
+	txRepo := mocks.NewTransactionRepository(t)
+	orderRepo := mocks.NewOrderRepository(t)
+
+	user := &domain.User{DiscordID: "111", Name: "Alice", NotionID: "abc-db", Currency: domain.CurrencyJPY}
+
+	userRepo.On("GetUserByDiscordID", mock.Anything, "111").Return(user, nil)
+	orderRepo.On("GetOrderByThreadID", mock.Anything, "thread-1").Return(nil, errors.New("notion error"))
+
+	uc := usecase.NewRegisterBuyRecord(userRepo, txRepo, orderRepo, 0.24, testOthersDBID)
+	_, err := uc.Execute(context.Background(), domain.BuyRequest{
+		TargetDiscordID: "111", ThreadID: "thread-1", JPYAmount: 3000, ItemName: "Item",
+	})
+
+	require.ErrorContains(t, err, "get order for thread")
+}
+
+func TestRegisterBuyRecord_OrderShopURL(t *testing.T) {
+	orderRepo := mocks.NewOrderRepository(t)
+
+	orderRepo.On("GetOrderByThreadID", mock.Anything, "thread-1").
+		Return(&domain.Order{PageID: "order-page", ShopURL: "https://someone.booth.pm/items/1"}, nil)
+	orderRepo.On("GetOrderByThreadID", mock.Anything, "thread-2").Return(nil, port.ErrOrderNotFound)
+
+	uc := usecase.NewRegisterBuyRecord(
+		mocks.NewUserRepository(t), mocks.NewTransactionRepository(t), orderRepo, 0.24, testOthersDBID,
+	)
+
+	link, err := uc.OrderShopURL(context.Background(), "thread-1")
+	require.NoError(t, err)
+	require.Equal(t, "https://someone.booth.pm/items/1", link)
+
+	link, err = uc.OrderShopURL(context.Background(), "thread-2")
+	require.NoError(t, err)
+	require.Empty(t, link, "a thread without an order has no link")
+}
@@ -0,0 +1,133 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/xgnid-tw/gx5/domain"
+	"github.com/xgnid-tw/gx5/port"
+)
+
+// ReviseBuyRecord undoes or edits a record registered with /buy. Only the member
+// who registered it or an admin may do so, and only within the revision window.
+type ReviseBuyRecord struct {
+	itemRepo     port.ItemRepository
+	txRepo       port.TransactionRepository
+	userRepo     port.UserRepository
+	jpyToTWDRate float64
+	othersDBID   string
+	window       time.Duration
+}
+
+func NewReviseBuyRecord(
+	itemRepo port.ItemRepository, txRepo port.TransactionRepository, userRepo port.UserRepository,
+	jpyToTWDRate float64, othersDBID string, window time.Duration,
+) *ReviseBuyRecord {
+	return &ReviseBuyRecord{
+		itemRepo: itemRepo, txRepo: txRepo, userRepo: userRepo,
+		jpyToTWDRate: jpyToTWDRate, othersDBID: othersDBID, window: window,
+	}
+}
+
+func (uc *ReviseBuyRecord) Get(ctx context.Context, rev domain.BuyRevision) (*domain.ItemRecord, error) {
+	err := uc.authorize(rev)
+	if err != nil {
+		return nil, err
+	}
+
+	item, err := uc.itemRepo.GetItem(ctx, rev.PageID)
+	if err != nil {
+		return nil, fmt.Errorf("get item %s: %w", rev.PageID, err)
+	}
+
+	return item, nil
+}
+
+func (uc *ReviseBuyRecord) Cancel(ctx context.Context, rev domain.BuyRevision) (*domain.ItemRecord, error) {
+	item, err := uc.Get(ctx, rev)
+	if err != nil {
+		return nil, err
+	}
+
+	err = uc.txRepo.ArchiveTransaction(ctx, rev.PageID)
+	if err != nil {
+		return nil, fmt.Errorf("archive transaction %s: %w", item.ItemName, err)
+	}
+
+	return item, nil
+}
+
+// Edit overwrites the record with req and recomputes 台幣 at the current rate.
+// The record keeps its database, 購買人, 物品狀況 and order. 購買途徑 is derived
+// from the link again only when the link changed, so a store typed in Notion
+// survives an edit of the other fields.
+func (uc *ReviseBuyRecord) Edit(
+	ctx context.Context, rev domain.BuyRevision, req domain.BuyRequest,
+) (*domain.BuyResult, error) {
+	item, err := uc.Get(ctx, rev)
+	if err != nil {
+		return nil, err
+	}
+
+	owner, err := uc.owner(ctx, *item)
+	if err != nil {
+		return nil, err
+	}
+
+	shop := item.Shop
+	if req.Link != item.Link {
+		shop = domain.ShopFromURL(req.Link)
+	}
+
+	tx := domain.Transaction{
+		ItemName:   req.ItemName,
+		JPYAmount:  req.JPYAmount,
+		TWDAmount:  math.Round(req.JPYAmount * uc.jpyToTWDRate),
+		DatabaseID: item.DatabaseID,
+		Shop:       shop,
+		Link:       req.Link,
+		Note:       req.Note,
+
+		ExpectedArrival: req.ExpectedArrival,
+	}
+
+	err = uc.txRepo.UpdateTransaction(ctx, rev.PageID, tx)
+	if err != nil {
+		return nil, fmt.Errorf("update transaction %s: %w", item.ItemName, err)
+	}
+
+	result := buyResult(owner, rev.PageID, tx)
+	result.ItemStatus = item.Status
+
+	return result, nil
+}
+
+func (uc *ReviseBuyRecord) authorize(rev domain.BuyRevision) error {
+	if !rev.ActorIsAdmin && rev.ActorID != rev.RegisteredBy {
+		return fmt.Errorf("%w: %s did not register %s", port.ErrBuyRevisionDenied, rev.ActorID, rev.PageID)
+	}
+
+	if rev.At.Sub(rev.RegisteredAt) > uc.window {
+		return fmt.Errorf("%w: %s registered at %s", port.ErrBuyRevisionExpired, rev.PageID, rev.RegisteredAt)
+	}
+
+	return nil
+}
+
+// owner returns the member the record belongs to, whose currency the edited amount is shown in.
+func (uc *ReviseBuyRecord) owner(ctx context.Context, item domain.ItemRecord) (*domain.User, error) {
+	users, err := uc.userRepo.GetUsers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get users: %w", err)
+	}
+
+	for _, u := range users {
+		if ownsItem(u, item, uc.othersDBID) {
+			return u, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no member owns %s", item.ItemName)
+}
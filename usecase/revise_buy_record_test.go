@@ -0,0 +1,174 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/xgnid-tw/gx5/domain"
+	"github.com/xgnid-tw/gx5/mocks"
+	"github.com/xgnid-tw/gx5/port"
+	"github.com/xgnid-tw/gx5/usecase"
+)
+
+const reviseWindow = 15 * time.Minute
+
+var registeredAt = time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+
+func revision(actorID string, admin bool, after time.Duration) domain.BuyRevision {
+	return domain.BuyRevision{
+		PageID: "p1", RegisteredBy: "999", RegisteredAt: registeredAt,
+		ActorID: actorID, ActorIsAdmin: admin, At: registeredAt.Add(after),
+	}
+}
+
+func boughtItem() *domain.ItemRecord {
+	return &domain.ItemRecord{
+		PageID: "p1", ItemName: "CD", Status: domain.ItemStatusOrdered, DatabaseID: "abcd-1234",
+		JPYAmount: 3000, TWDAmount: 720,
+	}
+}
+
+func TestReviseBuyRecord_Cancel_ByRegistrant(t *testing.T) {
+	itemRepo := mocks.NewItemRepository(t)
+	txRepo := mocks.NewTransactionRepository(t)
+
+	itemRepo.On("GetItem", mock.Anything, "p1").Return(boughtItem(), nil)
+	txRepo.On("ArchiveTransaction", mock.Anything, "p1").Return(nil)
+
+	uc := usecase.NewReviseBuyRecord(
+		itemRepo, txRepo, mocks.NewUserRepository(t), 0.24, testOthersDBID, reviseWindow,
+	)
+	item, err := uc.Cancel(context.Background(), revision("999", false, 5*time.Minute))
+
+	require.NoError(t, err)
+	require.Equal(t, "CD", item.ItemName)
+}
+
+func TestReviseBuyRecord_Cancel_ByAdmin(t *testing.T) {
+	itemRepo := mocks.NewItemRepository(t)
+	txRepo := mocks.NewTransactionRepository(t)
+
+	itemRepo.On("GetItem", mock.Anything, "p1").Return(boughtItem(), nil)
+	txRepo.On("ArchiveTransaction", mock.Anything, "p1").Return(nil)
+
+	uc := usecase.NewReviseBuyRecord(
+		itemRepo, txRepo, mocks.NewUserRepository(t), 0.24, testOthersDBID, reviseWindow,
+	)
+	_, err := uc.Cancel(context.Background(), revision("555", true, time.Minute))
+
+	require.NoError(t, err)
+}
+
+func TestReviseBuyRecord_Cancel_Denied(t *testing.T) {
+	uc := usecase.NewReviseBuyRecord(
+		mocks.NewItemRepository(t), mocks.NewTransactionRepository(t), mocks.NewUserRepository(t),
+		0.24, testOthersDBID, reviseWindow,
+	)
+	_, err := uc.Cancel(context.Background(), revision("555", false, time.Minute))
+
+	require.ErrorIs(t, err, port.ErrBuyRevisionDenied)
+}
+
+func TestReviseBuyRecord_Cancel_Expired(t *testing.T) {
+	uc := usecase.NewReviseBuyRecord(
+		mocks.NewItemRepository(t), mocks.NewTransactionRepository(t), mocks.NewUserRepository(t),
+		0.24, testOthersDBID, reviseWindow,
+	)
+
+	_, err := uc.Cancel(context.Background(), revision("999", false, reviseWindow+time.Second))
+	require.ErrorIs(t, err, port.ErrBuyRevisionExpired)
+
+	// The window applies to admins as well.
+	_, err = uc.Cancel(context.Background(), revision("555", true, reviseWindow+time.Second))
+	require.ErrorIs(t, err, port.ErrBuyRevisionExpired)
+}
+
+func TestReviseBuyRecord_Cancel_ArchiveError(t *testing.T) {
+	itemRepo := mocks.NewItemRepository(t)
+	txRepo := mocks.NewTransactionRepository(t)
+
+	itemRepo.On("GetItem", mock.Anything, "p1").Return(boughtItem(), nil)
+	txRepo.On("ArchiveTransaction", mock.Anything, "p1").Return(errors.New("notion down"))
+
+	uc := usecase.NewReviseBuyRecord(
+		itemRepo, txRepo, mocks.NewUserRepository(t), 0.24, testOthersDBID, reviseWindow,
+	)
+	_, err := uc.Cancel(context.Background(), revision("999", false, time.Minute))
+
+	require.ErrorContains(t, err, "archive transaction CD")
+}
+
+func TestReviseBuyRecord_Edit(t *testing.T) {
+	itemRepo := mocks.NewItemRepository(t)
+	txRepo := mocks.NewTransactionRepository(t)
+	userRepo := mocks.NewUserRepository(t)
+
+	itemRepo.On("GetItem", mock.Anything, "p1").Return(boughtItem(), nil)
+	userRepo.On("GetUsers", mock.Anything).Return([]*domain.User{trackCarol, trackAlice}, nil)
+	txRepo.On("UpdateTransaction", mock.Anything, "p1", domain.Transaction{
+		ItemName:   "CD 初回盤",
+		JPYAmount:  3500,
+		TWDAmount:  840,
+		DatabaseID: "abcd-1234",
+		Shop:       "Booth",
+		Link:       "https://someone.booth.pm/items/1",
+
+		ExpectedArrival: "2026-11-30",
+	}).Return(nil)
+
+	uc := usecase.NewReviseBuyRecord(itemRepo, txRepo, userRepo, 0.24, testOthersDBID, reviseWindow)
+	result, err := uc.Edit(context.Background(), revision("999", false, time.Minute), domain.BuyRequest{
+		JPYAmount: 3500, ItemName: "CD 初回盤", Link: "https://someone.booth.pm/items/1",
+		ExpectedArrival: "2026-11-30",
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, "111", result.TargetDiscordID)
+	require.Equal(t, float64(3500), result.DisplayAmount)
+	require.Equal(t, domain.CurrencyJPY, result.Currency)
+	require.Equal(t, domain.ItemStatusOrdered, result.ItemStatus)
+}
+
+func TestReviseBuyRecord_Edit_SameLinkKeepsShop(t *testing.T) {
+	itemRepo := mocks.NewItemRepository(t)
+	txRepo := mocks.NewTransactionRepository(t)
+	userRepo := mocks.NewUserRepository(t)
+
+	item := boughtItem()
+	item.Shop, item.Link = "メルカリ", "https://jp.mercari.com/item/m1"
+
+	itemRepo.On("GetItem", mock.Anything, "p1").Return(item, nil)
+	userRepo.On("GetUsers", mock.Anything).Return([]*domain.User{trackCarol, trackAlice}, nil)
+	txRepo.On("UpdateTransaction", mock.Anything, "p1", mock.MatchedBy(func(tx domain.Transaction) bool {
+		return tx.Shop == "メルカリ" && tx.JPYAmount == 4000
+	})).Return(nil)
+
+	uc := usecase.NewReviseBuyRecord(itemRepo, txRepo, userRepo, 0.24, testOthersDBID, reviseWindow)
+	_, err := uc.Edit(context.Background(), revision("999", false, time.Minute), domain.BuyRequest{
+		JPYAmount: 4000, ItemName: "CD", Link: "https://jp.mercari.com/item/m1",
+	})
+
+	require.NoError(t, err)
+}
+
+func TestReviseBuyRecord_Edit_NoOwner(t *testing.T) {
+	itemRepo := mocks.NewItemRepository(t)
+	userRepo := mocks.NewUserRepository(t)
+
+	itemRepo.On("GetItem", mock.Anything, "p1").Return(boughtItem(), nil)
+	userRepo.On("GetUsers", mock.Anything).Return([]*domain.User{trackCarol}, nil)
+
+	uc := usecase.NewReviseBuyRecord(
+		itemRepo, mocks.NewTransactionRepository(t), userRepo, 0.24, testOthersDBID, reviseWindow,
+	)
+	_, err := uc.Edit(context.Background(), revision("999", false, time.Minute), domain.BuyRequest{
+		JPYAmount: 3500, ItemName: "CD",
+	})
+
+	require.ErrorContains(t, err, "no member owns CD")
+}
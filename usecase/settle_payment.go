@@ -0,0 +1,71 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/xgnid-tw/gx5/domain"
+	"github.com/xgnid-tw/gx5/port"
+)
+
+type SettlePayment struct {
+	userRepo    port.UserRepository
+	paymentRepo port.PaymentRepository
+	othersDBID  string
+}
+
+func NewSettlePayment(
+	userRepo port.UserRepository, paymentRepo port.PaymentRepository, othersDBID string,
+) *SettlePayment {
+	return &SettlePayment{userRepo: userRepo, paymentRepo: paymentRepo, othersDBID: othersDBID}
+}
+
+// ListUnpaid returns the member's unpaid items and their total.
+func (uc *SettlePayment) ListUnpaid(ctx context.Context, discordID string) (*domain.UnpaidLedger, error) {
+	user, err := uc.userRepo.GetUserByDiscordID(ctx, discordID)
+	if err != nil {
+		return nil, fmt.Errorf("get user by discord id: %w", err)
+	}
+
+	return loadLedger(ctx, uc.userRepo, uc.othersDBID, user)
+}
+
+// Settle marks the listed items as paid. Items that were settled or removed since
+// they were listed, or that belong to someone else, are skipped. When only some
+// items can be updated, the report of those that were is returned with the error.
+func (uc *SettlePayment) Settle(
+	ctx context.Context, discordID string, pageIDs []string,
+) (*domain.SettleReport, error) {
+	ledger, err := uc.ListUnpaid(ctx, discordID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only settle pages that are still unpaid and belong to this member.
+	var targets []string
+
+	for _, it := range ledger.Items {
+		if slices.Contains(pageIDs, it.PageID) {
+			targets = append(targets, it.PageID)
+		}
+	}
+
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("no unpaid items to settle for %s", ledger.User.Name)
+	}
+
+	settled, err := uc.paymentRepo.MarkPaid(ctx, targets)
+	report := &domain.SettleReport{Settled: len(settled), Requested: len(targets)}
+
+	if err != nil {
+		err = fmt.Errorf("mark paid: %w", err)
+		if len(settled) == 0 {
+			return nil, err
+		}
+
+		return report, err
+	}
+
+	return report, nil
+}
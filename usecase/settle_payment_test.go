@@ -0,0 +1,148 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/xgnid-tw/gx5/domain"
+	"github.com/xgnid-tw/gx5/mocks"
+	"github.com/xgnid-tw/gx5/usecase"
+)
+
+var settleTestItems = []domain.UnpaidItem{
+	{PageID: "p1", ItemName: "CD", TWDAmount: 480, JPYAmount: 2000},
+	{PageID: "p2", ItemName: "Badge", TWDAmount: 240, JPYAmount: 1000},
+}
+
+func TestSettlePayment_ListUnpaid_PersonalDB(t *testing.T) {
+	userRepo := mocks.NewUserRepository(t)
+	paymentRepo := mocks.NewPaymentRepository(t)
+
+	user := &domain.User{DiscordID: "111", Name: "Alice", NotionID: "abc", Currency: domain.CurrencyTWD}
+
+	userRepo.On("GetUserByDiscordID", mock.Anything, "111").Return(user, nil)
+	userRepo.On("GetUnpaidItems", mock.Anything, "abc").Return(settleTestItems, nil)
+
+	uc := usecase.NewSettlePayment(userRepo, paymentRepo, testOthersDBID)
+	ledger, err := uc.ListUnpaid(context.Background(), "111")
+
+	require.NoError(t, err)
+	require.Equal(t, *user, ledger.User)
+	require.Equal(t, settleTestItems, ledger.Items)
+	require.Equal(t, 720.0, ledger.Total)
+}
+
+func TestSettlePayment_ListUnpaid_OthersDB(t *testing.T) {
+	userRepo := mocks.NewUserRepository(t)
+	paymentRepo := mocks.NewPaymentRepository(t)
+
+	user := &domain.User{DiscordID: "333", Name: "Carol", NotionID: testOthersDBID, Currency: domain.CurrencyTWD}
+
+	userRepo.On("GetUserByDiscordID", mock.Anything, "333").Return(user, nil)
+	userRepo.On("GetOthersUnpaidItems", mock.Anything, "Carol").Return(settleTestItems[:1], nil)
+
+	uc := usecase.NewSettlePayment(userRepo, paymentRepo, testOthersDBID)
+	ledger, err := uc.ListUnpaid(context.Background(), "333")
+
+	require.NoError(t, err)
+	require.Len(t, ledger.Items, 1)
+	require.Equal(t, 480.0, ledger.Total)
+}
+
+func TestSettlePayment_Settle_SelectedItems(t *testing.T) {
+	userRepo := mocks.NewUserRepository(t)
+	paymentRepo := mocks.NewPaymentRepository(t)
+
+	user := &domain.User{DiscordID: "111", Name: "Alice", NotionID: "abc", Currency: domain.CurrencyTWD}
+
+	userRepo.On("GetUserByDiscordID", mock.Anything, "111").Return(user, nil)
+	userRepo.On("GetUnpaidItems", mock.Anything, "abc").Return(settleTestItems, nil)
+	paymentRepo.On("MarkPaid", mock.Anything, []string{"p2"}).Return([]string{"p2"}, nil)
+
+	uc := usecase.NewSettlePayment(userRepo, paymentRepo, testOthersDBID)
+	report, err := uc.Settle(context.Background(), "111", []string{"p2", "someone-elses-page"})
+
+	require.NoError(t, err)
+	require.Equal(t, &domain.SettleReport{Settled: 1, Requested: 1}, report)
+}
+
+func TestSettlePayment_Settle_NoneGiven(t *testing.T) {
+	userRepo := mocks.NewUserRepository(t)
+	paymentRepo := mocks.NewPaymentRepository(t)
+
+	user := &domain.User{DiscordID: "111", Name: "Alice", NotionID: "abc", Currency: domain.CurrencyTWD}
+
+	userRepo.On("GetUserByDiscordID", mock.Anything, "111").Return(user, nil)
+	userRepo.On("GetUnpaidItems", mock.Anything, "abc").Return(settleTestItems, nil)
+
+	uc := usecase.NewSettlePayment(userRepo, paymentRepo, testOthersDBID)
+	_, err := uc.Settle(context.Background(), "111", nil)
+
+	require.ErrorContains(t, err, "no unpaid items to settle")
+}
+
+func TestSettlePayment_Settle_NothingUnpaid(t *testing.T) {
+	userRepo := mocks.NewUserRepository(t)
+	paymentRepo := mocks.NewPaymentRepository(t)
+
+	user := &domain.User{DiscordID: "111", Name: "Alice", NotionID: "abc", Currency: domain.CurrencyTWD}
+
+	userRepo.On("GetUserByDiscordID", mock.Anything, "111").Return(user, nil)
+	userRepo.On("GetUnpaidItems", mock.Anything, "abc").Return([]domain.UnpaidItem{}, nil)
+
+	uc := usecase.NewSettlePayment(userRepo, paymentRepo, testOthersDBID)
+	_, err := uc.Settle(context.Background(), "111", []string{"p1"})
+
+	require.ErrorContains(t, err, "no unpaid items to settle")
+}
+
+func TestSettlePayment_Settle_MarkPaidError(t *testing.T) {
+	userRepo := mocks.NewUserRepository(t)
+	paymentRepo := mocks.NewPaymentRepository(t)
+
+	user := &domain.User{DiscordID: "111", Name: "Alice", NotionID: "abc", Currency: domain.CurrencyTWD}
+
+	userRepo.On("GetUserByDiscordID", mock.Anything, "111").Return(user, nil)
+	userRepo.On("GetUnpaidItems", mock.Anything, "abc").Return(settleTestItems, nil)
+	paymentRepo.On("MarkPaid", mock.Anything, mock.Anything).Return(nil, errors.New("notion error"))
+
+	uc := usecase.NewSettlePayment(userRepo, paymentRepo, testOthersDBID)
+	report, err := uc.Settle(context.Background(), "111", []string{"p1", "p2"})
+
+	require.ErrorContains(t, err, "mark paid")
+	require.Nil(t, report)
+}
+
+func TestSettlePayment_Settle_PartialFailure(t *testing.T) {
+	userRepo := mocks.NewUserRepository(t)
+	paymentRepo := mocks.NewPaymentRepository(t)
+
+	user := &domain.User{DiscordID: "111", Name: "Alice", NotionID: "abc", Currency: domain.CurrencyTWD}
+
+	userRepo.On("GetUserByDiscordID", mock.Anything, "111").Return(user, nil)
+	userRepo.On("GetUnpaidItems", mock.Anything, "abc").Return(settleTestItems, nil)
+	paymentRepo.On("MarkPaid", mock.Anything, []string{"p1", "p2"}).
+		Return([]string{"p2"}, errors.New("notion error"))
+
+	uc := usecase.NewSettlePayment(userRepo, paymentRepo, testOthersDBID)
+	report, err := uc.Settle(context.Background(), "111", []string{"p1", "p2"})
+
+	require.ErrorContains(t, err, "mark paid")
+	require.Equal(t, &domain.SettleReport{Settled: 1, Requested: 2}, report)
+}
+
+func TestSettlePayment_UserNotFound(t *testing.T) {
+	userRepo := mocks.NewUserRepository(t)
+	paymentRepo := mocks.NewPaymentRepository(t)
+
+	userRepo.On("GetUserByDiscordID", mock.Anything, "999").Return(nil, errors.New("user not found"))
+
+	uc := usecase.NewSettlePayment(userRepo, paymentRepo, testOthersDBID)
+	_, err := uc.ListUnpaid(context.Background(), "999")
+
+	require.ErrorContains(t, err, "get user by discord id")
+}
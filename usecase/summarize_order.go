@@ -0,0 +1,117 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/xgnid-tw/gx5/domain"
+	"github.com/xgnid-tw/gx5/port"
+)
+
+type SummarizeOrder struct {
+	orderRepo     port.OrderRepository
+	itemRepo      port.ItemRepository
+	userRepo      port.UserRepository
+	threadCreator port.ThreadCreator
+	othersDBID    string
+}
+
+func NewSummarizeOrder(
+	orderRepo port.OrderRepository, itemRepo port.ItemRepository, userRepo port.UserRepository,
+	threadCreator port.ThreadCreator, othersDBID string,
+) *SummarizeOrder {
+	return &SummarizeOrder{
+		orderRepo: orderRepo, itemRepo: itemRepo, userRepo: userRepo,
+		threadCreator: threadCreator, othersDBID: othersDBID,
+	}
+}
+
+// Execute collects the records linked to the thread's order from every member's
+// TBL-002 and the shared TBL-003, and totals them per order.
+func (uc *SummarizeOrder) Execute(ctx context.Context, threadID string) (*domain.OrderRollup, error) {
+	order, err := uc.orderRepo.GetOrderByThreadID(ctx, threadID)
+	if err != nil {
+		return nil, fmt.Errorf("get order for thread %s: %w", threadID, err)
+	}
+
+	return uc.rollup(ctx, order)
+}
+
+// Refresh recomputes the totals of the thread's order and rewrites its pinned
+// summary. Orders without a summary message are left alone.
+func (uc *SummarizeOrder) Refresh(ctx context.Context, threadID string) error {
+	order, err := uc.orderRepo.GetOrderByThreadID(ctx, threadID)
+	if err != nil {
+		return fmt.Errorf("get order for thread %s: %w", threadID, err)
+	}
+
+	if order.SummaryID == "" {
+		return nil
+	}
+
+	rollup, err := uc.rollup(ctx, order)
+	if err != nil {
+		return err
+	}
+
+	err = uc.threadCreator.UpdateSummary(ctx, threadID, order.SummaryID, *rollup)
+	if err != nil {
+		return fmt.Errorf("update order summary for %s: %w", order.ThreadName, err)
+	}
+
+	return nil
+}
+
+func (uc *SummarizeOrder) rollup(ctx context.Context, order *domain.Order) (*domain.OrderRollup, error) {
+	users, err := uc.userRepo.GetUsers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get users: %w", err)
+	}
+
+	rollup := &domain.OrderRollup{Order: *order}
+
+	add := func(name string, it domain.ItemRecord) {
+		rollup.Items++
+		rollup.TotalJPY += it.JPYAmount
+		rollup.TotalTWD += it.TWDAmount
+		rollup.Lines = append(rollup.Lines, domain.OrderLine{
+			Participant: name, ItemName: it.ItemName, JPYAmount: it.JPYAmount, TWDAmount: it.TWDAmount,
+		})
+
+		if name != "" && !slices.Contains(rollup.Participants, name) {
+			rollup.Participants = append(rollup.Participants, name)
+		}
+	}
+
+	// A member whose database cannot be read is reported instead of failing the
+	// whole summary; only the shared TBL-003 is required.
+	dbs := orderDatabases(users, uc.othersDBID)
+	items, errs := readOrderItems(ctx, uc.itemRepo, dbs, order.PageID)
+
+	if errs[0] != nil {
+		return nil, errs[0]
+	}
+
+	for n, db := range dbs {
+		if errs[n] != nil {
+			log.Printf("skip %s in order summary: %s", db.owner, errs[n])
+			rollup.Unreadable = append(rollup.Unreadable, db.owner)
+			continue
+		}
+
+		for _, it := range items[n] {
+			add(db.participant(it), it)
+		}
+	}
+
+	slices.Sort(rollup.Participants)
+	slices.Sort(rollup.Unreadable)
+	slices.SortStableFunc(rollup.Lines, func(a, b domain.OrderLine) int {
+		return strings.Compare(a.Participant, b.Participant)
+	})
+
+	return rollup, nil
+}
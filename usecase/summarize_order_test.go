@@ -0,0 +1,167 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/xgnid-tw/gx5/domain"
+	"github.com/xgnid-tw/gx5/mocks"
+	"github.com/xgnid-tw/gx5/port"
+	"github.com/xgnid-tw/gx5/usecase"
+)
+
+func TestSummarizeOrder_Success(t *testing.T) {
+	orderRepo := mocks.NewOrderRepository(t)
+	itemRepo := mocks.NewItemRepository(t)
+	userRepo := mocks.NewUserRepository(t)
+
+	order := &domain.Order{PageID: "order-page", ThreadName: "test order", ThreadID: "thread-1"}
+
+	orderRepo.On("GetOrderByThreadID", mock.Anything, "thread-1").Return(order, nil)
+	userRepo.On("GetUsers", mock.Anything).Return([]*domain.User{
+		{Name: "Bob", NotionID: "bob-db"},
+		{Name: "Alice", NotionID: "alice-db"},
+		{Name: "Carol", NotionID: testOthersDBID},
+		{Name: "Dave", NotionID: "dave-db"},
+	}, nil)
+	itemRepo.On("GetOrderItems", mock.Anything, testOthersDBID, "order-page").Return([]domain.ItemRecord{
+		{PageID: "o1", ItemName: "Pin", BuyerName: "Carol", JPYAmount: 500, TWDAmount: 120},
+	}, nil)
+	itemRepo.On("GetOrderItems", mock.Anything, "bob-db", "order-page").Return([]domain.ItemRecord{
+		{PageID: "b1", ItemName: "Badge", JPYAmount: 1000, TWDAmount: 240},
+		{PageID: "b2", ItemName: "Acrylic", JPYAmount: 2000, TWDAmount: 480},
+	}, nil)
+	itemRepo.On("GetOrderItems", mock.Anything, "alice-db", "order-page").Return([]domain.ItemRecord{
+		{PageID: "a1", ItemName: "Photo", JPYAmount: 3000, TWDAmount: 720},
+	}, nil)
+	itemRepo.On("GetOrderItems", mock.Anything, "dave-db", "order-page").Return(nil, nil)
+
+	uc := usecase.NewSummarizeOrder(orderRepo, itemRepo, userRepo, mocks.NewThreadCreator(t), testOthersDBID)
+	rollup, err := uc.Execute(context.Background(), "thread-1")
+
+	require.NoError(t, err)
+	require.Equal(t, &domain.OrderRollup{
+		Order:        *order,
+		Participants: []string{"Alice", "Bob", "Carol"},
+		Lines: []domain.OrderLine{
+			{Participant: "Alice", ItemName: "Photo", JPYAmount: 3000, TWDAmount: 720},
+			{Participant: "Bob", ItemName: "Badge", JPYAmount: 1000, TWDAmount: 240},
+			{Participant: "Bob", ItemName: "Acrylic", JPYAmount: 2000, TWDAmount: 480},
+			{Participant: "Carol", ItemName: "Pin", JPYAmount: 500, TWDAmount: 120},
+		},
+		Items:    4,
+		TotalJPY: 6500,
+		TotalTWD: 1560,
+	}, rollup)
+}
+
+func TestSummarizeOrder_OrderNotFound(t *testing.T) {
+	orderRepo := mocks.NewOrderRepository(t)
+	itemRepo := mocks.NewItemRepository(t)
+	userRepo := mocks.NewUserRepository(t)
+
+	orderRepo.On("GetOrderByThreadID", mock.Anything, "thread-1").Return(nil, port.ErrOrderNotFound)
+
+	uc := usecase.NewSummarizeOrder(orderRepo, itemRepo, userRepo, mocks.NewThreadCreator(t), testOthersDBID)
+	_, err := uc.Execute(context.Background(), "thread-1")
+
+	require.ErrorIs(t, err, port.ErrOrderNotFound)
+}
+
+func TestSummarizeOrder_ItemsError(t *testing.T) {
+	orderRepo := mocks.NewOrderRepository(t)
+	itemRepo := mocks.NewItemRepository(t)
+	userRepo := mocks.NewUserRepository(t)
+
+	orderRepo.On("GetOrderByThreadID", mock.Anything, "thread-1").
+		Return(&domain.Order{PageID: "order-page"}, nil)
+	userRepo.On("GetUsers", mock.Anything).Return([]*domain.User{
+		{Name: "Bob", NotionID: "bob-db"}, {Name: "Alice", NotionID: "alice-db"},
+	}, nil)
+	itemRepo.On("GetOrderItems", mock.Anything, testOthersDBID, "order-page").Return(nil, nil)
+	itemRepo.On("GetOrderItems", mock.Anything, "bob-db", "order-page").Return(nil, errors.New("notion error"))
+	itemRepo.On("GetOrderItems", mock.Anything, "alice-db", "order-page").Return([]domain.ItemRecord{
+		{PageID: "a1", ItemName: "Stand", JPYAmount: 2000, TWDAmount: 480},
+	}, nil)
+
+	uc := usecase.NewSummarizeOrder(orderRepo, itemRepo, userRepo, mocks.NewThreadCreator(t), testOthersDBID)
+	rollup, err := uc.Execute(context.Background(), "thread-1")
+
+	require.NoError(t, err)
+	require.Equal(t, []string{"Alice"}, rollup.Participants)
+	require.Equal(t, []string{"Bob"}, rollup.Unreadable)
+	require.InDelta(t, 2000, rollup.TotalJPY, 0.001)
+}
+
+func TestSummarizeOrder_OthersError(t *testing.T) {
+	orderRepo := mocks.NewOrderRepository(t)
+	itemRepo := mocks.NewItemRepository(t)
+	userRepo := mocks.NewUserRepository(t)
+
+	orderRepo.On("GetOrderByThreadID", mock.Anything, "thread-1").
+		Return(&domain.Order{PageID: "order-page"}, nil)
+	userRepo.On("GetUsers", mock.Anything).Return([]*domain.User{{Name: "Bob", NotionID: "bob-db"}}, nil)
+	itemRepo.On("GetOrderItems", mock.Anything, testOthersDBID, "order-page").Return(nil, errors.New("notion error"))
+	itemRepo.On("GetOrderItems", mock.Anything, "bob-db", "order-page").Return(nil, nil)
+
+	uc := usecase.NewSummarizeOrder(orderRepo, itemRepo, userRepo, mocks.NewThreadCreator(t), testOthersDBID)
+	_, err := uc.Execute(context.Background(), "thread-1")
+
+	require.ErrorContains(t, err, "get order items in others database")
+}
+
+func TestSummarizeOrder_Refresh(t *testing.T) {
+	orderRepo := mocks.NewOrderRepository(t)
+	itemRepo := mocks.NewItemRepository(t)
+	userRepo := mocks.NewUserRepository(t)
+	tc := mocks.NewThreadCreator(t)
+
+	order := &domain.Order{PageID: "order-page", ThreadID: "thread-1", SummaryID: "msg-1"}
+
+	orderRepo.On("GetOrderByThreadID", mock.Anything, "thread-1").Return(order, nil)
+	userRepo.On("GetUsers", mock.Anything).Return([]*domain.User{{Name: "Bob", NotionID: "bob-db"}}, nil)
+	itemRepo.On("GetOrderItems", mock.Anything, testOthersDBID, "order-page").Return(nil, nil)
+	itemRepo.On("GetOrderItems", mock.Anything, "bob-db", "order-page").Return([]domain.ItemRecord{
+		{PageID: "b1", ItemName: "Badge", JPYAmount: 1000, TWDAmount: 240},
+	}, nil)
+	tc.On("UpdateSummary", mock.Anything, "thread-1", "msg-1", mock.MatchedBy(func(r domain.OrderRollup) bool {
+		return r.Items == 1 && r.TotalJPY == 1000 && r.Participants[0] == "Bob"
+	})).Return(nil)
+
+	uc := usecase.NewSummarizeOrder(orderRepo, itemRepo, userRepo, tc, testOthersDBID)
+
+	require.NoError(t, uc.Refresh(context.Background(), "thread-1"))
+}
+
+func TestSummarizeOrder_Refresh_NoSummaryMessage(t *testing.T) {
+	orderRepo := mocks.NewOrderRepository(t)
+	tc := mocks.NewThreadCreator(t)
+
+	orderRepo.On("GetOrderByThreadID", mock.Anything, "thread-1").
+		Return(&domain.Order{PageID: "order-page", ThreadID: "thread-1"}, nil)
+
+	uc := usecase.NewSummarizeOrder(orderRepo, mocks.NewItemRepository(t), mocks.NewUserRepository(t), tc, testOthersDBID)
+
+	require.NoError(t, uc.Refresh(context.Background(), "thread-1"))
+}
+
+func TestSummarizeOrder_Refresh_UpdateError(t *testing.T) {
+	orderRepo := mocks.NewOrderRepository(t)
+	itemRepo := mocks.NewItemRepository(t)
+	userRepo := mocks.NewUserRepository(t)
+	tc := mocks.NewThreadCreator(t)
+
+	orderRepo.On("GetOrderByThreadID", mock.Anything, "thread-1").
+		Return(&domain.Order{PageID: "order-page", ThreadName: "test order", SummaryID: "msg-1"}, nil)
+	userRepo.On("GetUsers", mock.Anything).Return([]*domain.User{}, nil)
+	itemRepo.On("GetOrderItems", mock.Anything, testOthersDBID, "order-page").Return(nil, nil)
+	tc.On("UpdateSummary", mock.Anything, "thread-1", "msg-1", mock.Anything).Return(errors.New("unknown message"))
+
+	uc := usecase.NewSummarizeOrder(orderRepo, itemRepo, userRepo, tc, testOthersDBID)
+
+	require.ErrorContains(t, uc.Refresh(context.Background(), "thread-1"), "update order summary for test order")
+}
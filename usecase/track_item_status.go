@@ -0,0 +1,191 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/xgnid-tw/gx5/domain"
+	"github.com/xgnid-tw/gx5/port"
+)
+
+type TrackItemStatus struct {
+	orderRepo  port.OrderRepository
+	itemRepo   port.ItemRepository
+	userRepo   port.UserRepository
+	notifier   port.Notifier
+	othersDBID string
+}
+
+func NewTrackItemStatus(
+	orderRepo port.OrderRepository, itemRepo port.ItemRepository, userRepo port.UserRepository,
+	notifier port.Notifier, othersDBID string,
+) *TrackItemStatus {
+	return &TrackItemStatus{
+		orderRepo: orderRepo, itemRepo: itemRepo, userRepo: userRepo, notifier: notifier,
+		othersDBID: othersDBID,
+	}
+}
+
+// Advance moves a single item forward and DMs its owner when it reaches 已回台.
+func (uc *TrackItemStatus) Advance(
+	ctx context.Context, pageID string, to domain.ItemStatus,
+) (*domain.ItemStatusChange, error) {
+	change, err := uc.advance(ctx, pageID, to)
+	if err != nil {
+		return nil, err
+	}
+
+	uc.notifyArrived(ctx, []domain.ItemStatusChange{*change})
+
+	return change, nil
+}
+
+// AdvanceOrder moves every record linked to the thread's order to the given
+// status. Records already at or past it are skipped; a failure on one record or
+// one member's database does not stop the others.
+func (uc *TrackItemStatus) AdvanceOrder(
+	ctx context.Context, threadID string, to domain.ItemStatus,
+) (*domain.ItemStatusReport, error) {
+	if !domain.ItemStatusNotOrdered.CanAdvanceTo(to) {
+		return nil, fmt.Errorf("cannot bulk advance to %q", to)
+	}
+
+	order, err := uc.orderRepo.GetOrderByThreadID(ctx, threadID)
+	if err != nil {
+		return nil, fmt.Errorf("get order for thread %s: %w", threadID, err)
+	}
+
+	users, err := uc.userRepo.GetUsers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get users: %w", err)
+	}
+
+	items, errs := readOrderItems(ctx, uc.itemRepo, orderDatabases(users, uc.othersDBID), order.PageID)
+	report := &domain.ItemStatusReport{}
+
+	for _, dbItems := range items {
+		for _, it := range dbItems {
+			change, err := uc.advanceItem(ctx, it, to)
+
+			switch {
+			case errors.Is(err, port.ErrItemNotAdvanceable):
+				report.Skipped++
+			case err != nil:
+				errs = append(errs, err)
+			default:
+				report.Advanced = append(report.Advanced, *change)
+			}
+		}
+	}
+
+	report.Notified = uc.notifyArrived(ctx, report.Advanced)
+
+	return report, errors.Join(errs...)
+}
+
+func (uc *TrackItemStatus) advance(
+	ctx context.Context, pageID string, to domain.ItemStatus,
+) (*domain.ItemStatusChange, error) {
+	item, err := uc.itemRepo.GetItem(ctx, pageID)
+	if err != nil {
+		return nil, fmt.Errorf("get item %s: %w", pageID, err)
+	}
+
+	return uc.advanceItem(ctx, *item, to)
+}
+
+func (uc *TrackItemStatus) advanceItem(
+	ctx context.Context, item domain.ItemRecord, to domain.ItemStatus,
+) (*domain.ItemStatusChange, error) {
+	if to == "" {
+		next, ok := item.Status.Next()
+		if !ok {
+			return nil, fmt.Errorf("%w: %s is %q", port.ErrItemNotAdvanceable, item.ItemName, item.Status)
+		}
+
+		to = next
+	}
+
+	if !item.Status.CanAdvanceTo(to) {
+		return nil, fmt.Errorf("%w: %s is %q", port.ErrItemNotAdvanceable, item.ItemName, item.Status)
+	}
+
+	err := uc.itemRepo.UpdateItemStatus(ctx, item.PageID, to)
+	if err != nil {
+		return nil, fmt.Errorf("update item status for %s: %w", item.ItemName, err)
+	}
+
+	change := &domain.ItemStatusChange{Item: item, From: item.Status, To: to}
+	change.Item.Status = to
+
+	return change, nil
+}
+
+// notifyArrived sends one DM per member listing their items that reached 已回台
+// and returns the number of members notified. Failures are logged only.
+func (uc *TrackItemStatus) notifyArrived(ctx context.Context, changes []domain.ItemStatusChange) int {
+	var arrived []domain.ItemRecord
+
+	for _, c := range changes {
+		if c.To == domain.ItemStatusArrivedTW {
+			arrived = append(arrived, c.Item)
+		}
+	}
+
+	if len(arrived) == 0 {
+		return 0
+	}
+
+	users, err := uc.userRepo.GetUsers(ctx)
+	if err != nil {
+		log.Printf("get users for arrival notice: %s", err)
+		return 0
+	}
+
+	notified := 0
+
+	for _, u := range users {
+		var items []domain.ItemRecord
+
+		for _, it := range arrived {
+			if ownsItem(u, it, uc.othersDBID) {
+				items = append(items, it)
+			}
+		}
+
+		if len(items) == 0 {
+			continue
+		}
+
+		err = uc.notifier.NotifyItemsArrived(ctx, *u, items)
+		if err != nil {
+			log.Printf("notify arrival %s: %s", u.Name, err)
+			continue
+		}
+
+		notified++
+	}
+
+	return notified
+}
+
+// ownsItem reports whether the item's row belongs to u: their own TBL-002, or a
+// TBL-003 row whose 購買人 is their name.
+func ownsItem(u *domain.User, it domain.ItemRecord, othersDBID string) bool {
+	if sameNotionID(u.NotionID, othersDBID) {
+		return sameNotionID(it.DatabaseID, othersDBID) && it.BuyerName == u.Name
+	}
+
+	return sameNotionID(it.DatabaseID, u.NotionID)
+}
+
+// sameNotionID compares Notion IDs regardless of dashes and case, since the API
+// returns dashed UUIDs while TBL-001 and the config usually hold the compact form.
+func sameNotionID(a, b string) bool {
+	norm := func(id string) string { return strings.ToLower(strings.ReplaceAll(id, "-", "")) }
+
+	return a != "" && norm(a) == norm(b)
+}
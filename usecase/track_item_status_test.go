@@ -0,0 +1,150 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/xgnid-tw/gx5/domain"
+	"github.com/xgnid-tw/gx5/mocks"
+	"github.com/xgnid-tw/gx5/port"
+	"github.com/xgnid-tw/gx5/usecase"
+)
+
+var (
+	trackAlice = &domain.User{DiscordID: "111", Name: "Alice", NotionID: "abcd1234", Currency: domain.CurrencyJPY}
+	trackCarol = &domain.User{DiscordID: "333", Name: "Carol", NotionID: testOthersDBID, Currency: domain.CurrencyTWD}
+	trackDave  = &domain.User{DiscordID: "444", Name: "Dave", NotionID: "dave-db", Currency: domain.CurrencyJPY}
+)
+
+func TestTrackItemStatus_Advance_Next(t *testing.T) {
+	itemRepo := mocks.NewItemRepository(t)
+	userRepo := mocks.NewUserRepository(t)
+	notifier := mocks.NewNotifier(t)
+
+	itemRepo.On("GetItem", mock.Anything, "p1").Return(&domain.ItemRecord{
+		PageID: "p1", ItemName: "CD", Status: domain.ItemStatusNotOrdered, DatabaseID: "abcd-1234",
+	}, nil)
+	itemRepo.On("UpdateItemStatus", mock.Anything, "p1", domain.ItemStatusOrdered).Return(nil)
+
+	uc := usecase.NewTrackItemStatus(mocks.NewOrderRepository(t), itemRepo, userRepo, notifier, testOthersDBID)
+	change, err := uc.Advance(context.Background(), "p1", "")
+
+	require.NoError(t, err)
+	require.Equal(t, domain.ItemStatusNotOrdered, change.From)
+	require.Equal(t, domain.ItemStatusOrdered, change.To)
+}
+
+func TestTrackItemStatus_Advance_ArrivedNotifiesOwner(t *testing.T) {
+	itemRepo := mocks.NewItemRepository(t)
+	userRepo := mocks.NewUserRepository(t)
+	notifier := mocks.NewNotifier(t)
+
+	item := &domain.ItemRecord{
+		PageID: "p1", ItemName: "CD", Status: domain.ItemStatusArrivedJP, DatabaseID: "abcd-1234",
+	}
+
+	itemRepo.On("GetItem", mock.Anything, "p1").Return(item, nil)
+	itemRepo.On("UpdateItemStatus", mock.Anything, "p1", domain.ItemStatusArrivedTW).Return(nil)
+	userRepo.On("GetUsers", mock.Anything).Return([]*domain.User{trackAlice, trackCarol}, nil)
+	notifier.On("NotifyItemsArrived", mock.Anything, *trackAlice, mock.MatchedBy(func(items []domain.ItemRecord) bool {
+		return len(items) == 1 && items[0].PageID == "p1" && items[0].Status == domain.ItemStatusArrivedTW
+	})).Return(nil)
+
+	uc := usecase.NewTrackItemStatus(mocks.NewOrderRepository(t), itemRepo, userRepo, notifier, testOthersDBID)
+	_, err := uc.Advance(context.Background(), "p1", "")
+
+	require.NoError(t, err)
+}
+
+func TestTrackItemStatus_Advance_Final(t *testing.T) {
+	itemRepo := mocks.NewItemRepository(t)
+
+	itemRepo.On("GetItem", mock.Anything, "p1").Return(&domain.ItemRecord{
+		PageID: "p1", ItemName: "CD", Status: domain.ItemStatusArrivedTW,
+	}, nil)
+
+	uc := usecase.NewTrackItemStatus(
+		mocks.NewOrderRepository(t), itemRepo, mocks.NewUserRepository(t), mocks.NewNotifier(t), testOthersDBID,
+	)
+	_, err := uc.Advance(context.Background(), "p1", "")
+
+	require.ErrorIs(t, err, port.ErrItemNotAdvanceable)
+}
+
+func TestTrackItemStatus_Advance_Charge(t *testing.T) {
+	itemRepo := mocks.NewItemRepository(t)
+
+	itemRepo.On("GetItem", mock.Anything, "p1").Return(&domain.ItemRecord{
+		PageID: "p1", ItemName: "運費", Status: domain.ItemStatusShipping,
+	}, nil)
+
+	uc := usecase.NewTrackItemStatus(
+		mocks.NewOrderRepository(t), itemRepo, mocks.NewUserRepository(t), mocks.NewNotifier(t), testOthersDBID,
+	)
+	_, err := uc.Advance(context.Background(), "p1", domain.ItemStatusArrivedTW)
+
+	require.ErrorIs(t, err, port.ErrItemNotAdvanceable)
+}
+
+func TestTrackItemStatus_AdvanceOrder(t *testing.T) {
+	orderRepo := mocks.NewOrderRepository(t)
+	itemRepo := mocks.NewItemRepository(t)
+	userRepo := mocks.NewUserRepository(t)
+	notifier := mocks.NewNotifier(t)
+
+	orderRepo.On("GetOrderByThreadID", mock.Anything, "thread-1").
+		Return(&domain.Order{PageID: "order-page"}, nil)
+	userRepo.On("GetUsers", mock.Anything).Return([]*domain.User{trackAlice, trackCarol, trackDave}, nil)
+	itemRepo.On("GetOrderItems", mock.Anything, "abcd1234", "order-page").Return([]domain.ItemRecord{
+		{PageID: "p1", ItemName: "CD", Status: domain.ItemStatusArrivedJP, DatabaseID: "abcd1234"},
+		{PageID: "p3", ItemName: "Poster", Status: domain.ItemStatusArrivedTW, DatabaseID: "abcd1234"},
+	}, nil)
+	itemRepo.On("GetOrderItems", mock.Anything, testOthersDBID, "order-page").Return([]domain.ItemRecord{
+		{
+			PageID: "p2", ItemName: "Badge", Status: domain.ItemStatusOrdered,
+			DatabaseID: testOthersDBID, BuyerName: "Carol",
+		},
+	}, nil)
+	itemRepo.On("GetOrderItems", mock.Anything, "dave-db", "order-page").Return(nil, errors.New("api down"))
+	itemRepo.On("UpdateItemStatus", mock.Anything, "p1", domain.ItemStatusArrivedTW).Return(nil)
+	itemRepo.On("UpdateItemStatus", mock.Anything, "p2", domain.ItemStatusArrivedTW).Return(nil)
+	notifier.On("NotifyItemsArrived", mock.Anything, *trackAlice, mock.Anything).Return(nil)
+	notifier.On("NotifyItemsArrived", mock.Anything, *trackCarol, mock.Anything).
+		Return(errors.New("dm closed"))
+
+	uc := usecase.NewTrackItemStatus(orderRepo, itemRepo, userRepo, notifier, testOthersDBID)
+	report, err := uc.AdvanceOrder(context.Background(), "thread-1", domain.ItemStatusArrivedTW)
+
+	require.ErrorContains(t, err, "get order items for Dave")
+	require.Len(t, report.Advanced, 2)
+	require.Equal(t, 1, report.Skipped)
+	require.Equal(t, 1, report.Notified)
+	itemRepo.AssertNotCalled(t, "GetItem", mock.Anything, mock.Anything)
+}
+
+func TestTrackItemStatus_AdvanceOrder_OrderNotFound(t *testing.T) {
+	orderRepo := mocks.NewOrderRepository(t)
+
+	orderRepo.On("GetOrderByThreadID", mock.Anything, "thread-1").Return(nil, port.ErrOrderNotFound)
+
+	uc := usecase.NewTrackItemStatus(
+		orderRepo, mocks.NewItemRepository(t), mocks.NewUserRepository(t), mocks.NewNotifier(t), testOthersDBID,
+	)
+	_, err := uc.AdvanceOrder(context.Background(), "thread-1", domain.ItemStatusArrivedTW)
+
+	require.ErrorIs(t, err, port.ErrOrderNotFound)
+}
+
+func TestTrackItemStatus_AdvanceOrder_InvalidTarget(t *testing.T) {
+	uc := usecase.NewTrackItemStatus(
+		mocks.NewOrderRepository(t), mocks.NewItemRepository(t), mocks.NewUserRepository(t), mocks.NewNotifier(t),
+		testOthersDBID,
+	)
+	_, err := uc.AdvanceOrder(context.Background(), "thread-1", domain.ItemStatusShipping)
+
+	require.ErrorContains(t, err, "cannot bulk advance")
+}
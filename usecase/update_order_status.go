@@ -0,0 +1,64 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/xgnid-tw/gx5/domain"
+	"github.com/xgnid-tw/gx5/port"
+)
+
+// closedThreadPrefix marks a closed order in the Discord thread list.
+const closedThreadPrefix = "【已截止】"
+
+type UpdateOrderStatus struct {
+	repo          port.OrderRepository
+	threadCreator port.ThreadCreator
+}
+
+func NewUpdateOrderStatus(repo port.OrderRepository, threadCreator port.ThreadCreator) *UpdateOrderStatus {
+	return &UpdateOrderStatus{repo: repo, threadCreator: threadCreator}
+}
+
+// Execute moves the order opened in the thread to the next lifecycle status.
+// Closing an order also renames and locks its thread. The Notion record is
+// updated first, so a failure to close the thread returns the change together
+// with the error.
+func (uc *UpdateOrderStatus) Execute(
+	ctx context.Context, threadID string, to domain.OrderStatus,
+) (*domain.OrderStatusChange, error) {
+	order, err := uc.repo.GetOrderByThreadID(ctx, threadID)
+	if err != nil {
+		return nil, fmt.Errorf("get order for thread %s: %w", threadID, err)
+	}
+
+	from := order.Status
+	if from == "" {
+		from = domain.OrderStatusOpen
+	}
+
+	if !from.CanTransitionTo(to) {
+		return nil, fmt.Errorf("%w: %s is %q", port.ErrInvalidOrderTransition, order.ThreadName, from)
+	}
+
+	err = uc.repo.UpdateOrderStatus(ctx, order.PageID, to)
+	if err != nil {
+		return nil, fmt.Errorf("update order status for %s: %w", order.ThreadName, err)
+	}
+
+	change := &domain.OrderStatusChange{Order: *order, From: from, To: to}
+	change.Order.Status = to
+
+	if to == domain.OrderStatusClosed {
+		err = uc.threadCreator.CloseThread(ctx, threadID, closedThreadName(order.ThreadName))
+		if err != nil {
+			return change, fmt.Errorf("close thread %s: %w", threadID, err)
+		}
+	}
+
+	return change, nil
+}
+
+func closedThreadName(name string) string {
+	return closedThreadPrefix + name
+}
@@ -0,0 +1,140 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/xgnid-tw/gx5/domain"
+	"github.com/xgnid-tw/gx5/mocks"
+	"github.com/xgnid-tw/gx5/port"
+	"github.com/xgnid-tw/gx5/usecase"
+)
+
+func threadOrder(status domain.OrderStatus) *domain.Order {
+	return &domain.Order{
+		PageID:     "order-page",
+		ThreadName: "test order",
+		ThreadID:   "thread-1",
+		Status:     status,
+	}
+}
+
+func TestUpdateOrderStatus_CloseLocksThread(t *testing.T) {
+	repo := mocks.NewOrderRepository(t)
+	tc := mocks.NewThreadCreator(t)
+
+	repo.On("GetOrderByThreadID", mock.Anything, "thread-1").Return(threadOrder(domain.OrderStatusOpen), nil)
+	repo.On("UpdateOrderStatus", mock.Anything, "order-page", domain.OrderStatusClosed).Return(nil)
+	tc.On("CloseThread", mock.Anything, "thread-1", "【已截止】test order").Return(nil)
+
+	uc := usecase.NewUpdateOrderStatus(repo, tc)
+	change, err := uc.Execute(context.Background(), "thread-1", domain.OrderStatusClosed)
+
+	require.NoError(t, err)
+	require.Equal(t, domain.OrderStatusOpen, change.From)
+	require.Equal(t, domain.OrderStatusClosed, change.To)
+	require.Equal(t, domain.OrderStatusClosed, change.Order.Status)
+}
+
+func TestUpdateOrderStatus_UnsetStatusCountsAsOpen(t *testing.T) {
+	repo := mocks.NewOrderRepository(t)
+	tc := mocks.NewThreadCreator(t)
+
+	repo.On("GetOrderByThreadID", mock.Anything, "thread-1").Return(threadOrder(""), nil)
+	repo.On("UpdateOrderStatus", mock.Anything, "order-page", domain.OrderStatusClosed).Return(nil)
+	tc.On("CloseThread", mock.Anything, "thread-1", mock.Anything).Return(nil)
+
+	uc := usecase.NewUpdateOrderStatus(repo, tc)
+	change, err := uc.Execute(context.Background(), "thread-1", domain.OrderStatusClosed)
+
+	require.NoError(t, err)
+	require.Equal(t, domain.OrderStatusOpen, change.From)
+}
+
+func TestUpdateOrderStatus_LaterStepKeepsThread(t *testing.T) {
+	repo := mocks.NewOrderRepository(t)
+	tc := mocks.NewThreadCreator(t)
+
+	repo.On("GetOrderByThreadID", mock.Anything, "thread-1").Return(threadOrder(domain.OrderStatusOrdered), nil)
+	repo.On("UpdateOrderStatus", mock.Anything, "order-page", domain.OrderStatusShipped).Return(nil)
+
+	uc := usecase.NewUpdateOrderStatus(repo, tc)
+	change, err := uc.Execute(context.Background(), "thread-1", domain.OrderStatusShipped)
+
+	require.NoError(t, err)
+	require.Equal(t, domain.OrderStatusShipped, change.To)
+}
+
+func TestUpdateOrderStatus_InvalidTransition(t *testing.T) {
+	tests := []struct {
+		name string
+		from domain.OrderStatus
+		to   domain.OrderStatus
+	}{
+		{name: "skip a step", from: domain.OrderStatusOpen, to: domain.OrderStatusOrdered},
+		{name: "backwards", from: domain.OrderStatusShipped, to: domain.OrderStatusOrdered},
+		{name: "same status", from: domain.OrderStatusClosed, to: domain.OrderStatusClosed},
+		{name: "past settled", from: domain.OrderStatusSettled, to: domain.OrderStatusOpen},
+		{name: "unknown status", from: "取消", to: domain.OrderStatusClosed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewOrderRepository(t)
+			tc := mocks.NewThreadCreator(t)
+
+			repo.On("GetOrderByThreadID", mock.Anything, "thread-1").Return(threadOrder(tt.from), nil)
+
+			uc := usecase.NewUpdateOrderStatus(repo, tc)
+			_, err := uc.Execute(context.Background(), "thread-1", tt.to)
+
+			require.ErrorIs(t, err, port.ErrInvalidOrderTransition)
+		})
+	}
+}
+
+func TestUpdateOrderStatus_OrderNotFound(t *testing.T) {
+	repo := mocks.NewOrderRepository(t)
+	tc := mocks.NewThreadCreator(t)
+
+	repo.On("GetOrderByThreadID", mock.Anything, "thread-1").Return(nil, port.ErrOrderNotFound)
+
+	uc := usecase.NewUpdateOrderStatus(repo, tc)
+	_, err := uc.Execute(context.Background(), "thread-1", domain.OrderStatusClosed)
+
+	require.ErrorIs(t, err, port.ErrOrderNotFound)
+}
+
+func TestUpdateOrderStatus_UpdateError(t *testing.T) {
+	repo := mocks.NewOrderRepository(t)
+	tc := mocks.NewThreadCreator(t)
+
+	repo.On("GetOrderByThreadID", mock.Anything, "thread-1").Return(threadOrder(domain.OrderStatusOpen), nil)
+	repo.On("UpdateOrderStatus", mock.Anything, "order-page", domain.OrderStatusClosed).
+		Return(errors.New("notion error"))
+
+	uc := usecase.NewUpdateOrderStatus(repo, tc)
+	_, err := uc.Execute(context.Background(), "thread-1", domain.OrderStatusClosed)
+
+	require.ErrorContains(t, err, "update order status")
+}
+
+func TestUpdateOrderStatus_CloseThreadError(t *testing.T) {
+	repo := mocks.NewOrderRepository(t)
+	tc := mocks.NewThreadCreator(t)
+
+	repo.On("GetOrderByThreadID", mock.Anything, "thread-1").Return(threadOrder(domain.OrderStatusOpen), nil)
+	repo.On("UpdateOrderStatus", mock.Anything, "order-page", domain.OrderStatusClosed).Return(nil)
+	tc.On("CloseThread", mock.Anything, "thread-1", mock.Anything).Return(errors.New("missing permissions"))
+
+	uc := usecase.NewUpdateOrderStatus(repo, tc)
+	change, err := uc.Execute(context.Background(), "thread-1", domain.OrderStatusClosed)
+
+	require.ErrorContains(t, err, "close thread")
+	require.NotNil(t, change)
+	require.Equal(t, domain.OrderStatusClosed, change.To)
+}